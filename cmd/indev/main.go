@@ -17,11 +17,16 @@ import (
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/internal/telemetry/exporters"
 	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/rootcommand"
 )
 
 const uploadTelemetryCommand = "upload-telemetry"
 
+// exitReauthenticationRequired is the exit status of commands that failed
+// because the platform asked for a new sign-in, which scripts can check for.
+const exitReauthenticationRequired = 4
+
 //go:generate ../../scripts/completions.sh ../../
 
 func main() {
@@ -37,6 +42,10 @@ func main() {
 	}
 
 	err := run(os.Args[1:])
+	if errors.Is(err, client.ErrReauthenticationRequired) {
+		os.Exit(exitReauthenticationRequired)
+	}
+
 	if err != nil {
 		os.Exit(1)
 	}
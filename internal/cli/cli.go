@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,6 +16,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/intility/indev/internal/build"
+	"github.com/intility/indev/internal/env"
 	"github.com/intility/indev/internal/redact"
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/internal/ux"
@@ -21,9 +25,22 @@ import (
 
 const (
 	timeout = 3 * time.Second
+
+	// AnnotationWithoutSetup marks commands that run even when the settings
+	// or the token cache could not be loaded. Its value is "true", or the
+	// name of a boolean flag that has to be set for the command to run.
+	AnnotationWithoutSetup = "indev.without-setup"
 )
 
-var errNotAuthenticated = errors.New("not authenticated")
+var (
+	// ErrNotInteractive is returned by Confirm when there is no terminal to
+	// ask on.
+	ErrNotInteractive = errors.New("confirmation requires a terminal")
+
+	errNotAuthenticated   = errors.New("not authenticated")
+	errNoPassphrase       = errors.New("the token cache is encrypted, set INDEV_CACHE_PASSPHRASE or run indev in a terminal")
+	errPassphraseMismatch = errors.New("the passphrases do not match")
+)
 
 func CreateAuthGate(message any) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
@@ -85,9 +102,105 @@ func CreatePasswordPrompter(cmd *cobra.Command) func(string) (string, error) {
 	}
 }
 
+// CreatePassphrasePrompter returns a function that reads the passphrase of the
+// encrypted token cache from INDEV_CACHE_PASSPHRASE, or else asks for it on
+// the terminal, writing the prompt to w. When confirm is set, the passphrase
+// is asked for twice, as it is being chosen.
+func CreatePassphrasePrompter(w io.Writer, confirm bool) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		if passphrase := env.CachePassphrase(); passphrase != "" {
+			return []byte(passphrase), nil
+		}
+
+		fd := int(os.Stdin.Fd()) //nolint:gosec // G115 - stdin fd fits in int
+		if !term.IsTerminal(fd) {
+			return nil, errNoPassphrase
+		}
+
+		passphrase, err := readPassword(w, fd, "Token cache passphrase")
+		if err != nil || !confirm {
+			return passphrase, err
+		}
+
+		repeated, err := readPassword(w, fd, "Repeat passphrase")
+		if err != nil {
+			return nil, err
+		}
+
+		if string(repeated) != string(passphrase) {
+			return nil, errPassphraseMismatch
+		}
+
+		return passphrase, nil
+	}
+}
+
+func readPassword(w io.Writer, fd int, prompt string) ([]byte, error) {
+	ux.Fprintf(w, "%s: ", prompt)
+
+	password, err := term.ReadPassword(fd)
+
+	ux.Fprintf(w, "\n")
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read passphrase: %w", err)
+	}
+
+	return password, nil
+}
+
+// Confirm asks a yes or no question on the terminal of r, writing the
+// question to w. Anything but "y" or "yes" is a no.
+func Confirm(r io.Reader, w io.Writer, question string) (bool, error) {
+	file, ok := r.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(int(file.Fd())) { //nolint:gosec // G115 - file descriptors fit in int
+		return false, ErrNotInteractive
+	}
+
+	ux.Fprintf(w, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("could not read answer: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
 func CreatePrinter(cmd *cobra.Command) func(ctx context.Context, message string) error {
 	return func(ctx context.Context, message string) error {
 		ux.Fprintf(cmd.OutOrStdout(), "%s\n", message)
 		return nil
 	}
 }
+
+// RunsWithoutSetup reports whether the command runs even when the settings or
+// the token cache could not be loaded, as marked with AnnotationWithoutSetup.
+// Shell completion always does, as it cannot report errors.
+func RunsWithoutSetup(cmd *cobra.Command) bool {
+	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return true
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		value, ok := c.Annotations[AnnotationWithoutSetup]
+		if !ok {
+			continue
+		}
+
+		if value == "true" {
+			return true
+		}
+
+		set, err := cmd.Flags().GetBool(value)
+
+		return err == nil && set
+	}
+
+	return false
+}
@@ -9,7 +9,25 @@ import (
 )
 
 const (
-	envKeyDoNotTrack = "DO_NOT_TRACK"
+	envKeyDoNotTrack   = "DO_NOT_TRACK"
+	envKeyHTTPCassette = "INDEV_HTTP_CASSETTE"
+	envKeySandbox      = "INDEV_SANDBOX"
+	envKeyProxy        = "INDEV_PROXY"
+	envKeyNoProxy      = "INDEV_NO_PROXY"
+	envKeyCABundle     = "INDEV_CA_BUNDLE"
+	envKeyClientCert   = "INDEV_CLIENT_CERT"
+	envKeyClientKey    = "INDEV_CLIENT_KEY"
+	envKeyToken        = "INDEV_TOKEN"
+	envKeyTenantID     = "INDEV_TENANT_ID"
+	envKeyClientID     = "INDEV_CLIENT_ID"
+	envKeyClientSecret = "INDEV_CLIENT_SECRET"
+	envKeyPassphrase   = "INDEV_CACHE_PASSPHRASE"
+	envKeyUseAzureCLI  = "INDEV_USE_AZURE_CLI"
+
+	// workload identity, as set up by AKS and azure/login.
+	envKeyAzureTenantID           = "AZURE_TENANT_ID"
+	envKeyAzureClientID           = "AZURE_CLIENT_ID"
+	envKeyAzureFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
 )
 
 // system.
@@ -24,6 +42,96 @@ func DoNotTrack() bool {
 	return doNotTrack
 }
 
+// HTTPCassette returns the "<mode>:<path>" specification of the cassette
+// used to record or replay platform requests, or an empty string.
+func HTTPCassette() string {
+	return os.Getenv(envKeyHTTPCassette)
+}
+
+// Sandbox returns the URL of the sandbox that replaces the platform, or an
+// empty string.
+func Sandbox() string {
+	return os.Getenv(envKeySandbox)
+}
+
+// Proxy returns the URL of the proxy used for all requests, or an empty
+// string to use the standard proxy environment variables.
+func Proxy() string {
+	return os.Getenv(envKeyProxy)
+}
+
+// NoProxy returns the hosts that are reached without the proxy.
+func NoProxy() string {
+	return os.Getenv(envKeyNoProxy)
+}
+
+// CABundle returns the path of a PEM file with additional trusted
+// certificates.
+func CABundle() string {
+	return os.Getenv(envKeyCABundle)
+}
+
+// ClientCert returns the path of the PEM client certificate.
+func ClientCert() string {
+	return os.Getenv(envKeyClientCert)
+}
+
+// ClientKey returns the path of the PEM private key of the client
+// certificate.
+func ClientKey() string {
+	return os.Getenv(envKeyClientKey)
+}
+
+// Token returns an access token to use instead of signing in.
+func Token() string {
+	return os.Getenv(envKeyToken)
+}
+
+// TenantID returns the tenant of the service principal that indev signs in
+// as, falling back to the workload identity tenant.
+func TenantID() string {
+	return firstOf(envKeyTenantID, envKeyAzureTenantID)
+}
+
+// ClientID returns the client ID of the service principal that indev signs in
+// as, falling back to the workload identity client.
+func ClientID() string {
+	return firstOf(envKeyClientID, envKeyAzureClientID)
+}
+
+// ClientSecret returns the secret of the service principal.
+func ClientSecret() string {
+	return os.Getenv(envKeyClientSecret)
+}
+
+// CachePassphrase returns the passphrase of the encrypted token cache, or an
+// empty string to ask for it on the terminal.
+func CachePassphrase() string {
+	return os.Getenv(envKeyPassphrase)
+}
+
+// UseAzureCLI reports whether tokens are acquired from the Azure CLI instead
+// of the account signed in with indev.
+func UseAzureCLI() bool {
+	useAzureCLI, _ := strconv.ParseBool(os.Getenv(envKeyUseAzureCLI))
+	return useAzureCLI
+}
+
+// FederatedTokenFile returns the path of the workload identity token.
+func FederatedTokenFile() string {
+	return os.Getenv(envKeyAzureFederatedTokenFile)
+}
+
+func firstOf(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
 func Username() string {
 	usr, err := user.Current()
 	if err == nil {
@@ -0,0 +1,140 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Placeholder replaces secret values in redacted HTTP traffic.
+const Placeholder = "REDACTED"
+
+// secretHeaders lists the request and response headers whose values are
+// always redacted.
+var secretHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// secretFields lists, in lower case, the JSON object keys and query
+// parameters whose values are always redacted.
+var secretFields = map[string]struct{}{
+	"accesstoken":      {},
+	"apikey":           {},
+	"auth":             {},
+	"clientsecret":     {},
+	"dockerconfigjson": {},
+	"key":              {},
+	"kubeconfig":       {},
+	"password":         {},
+	"pullsecret":       {},
+	"refreshtoken":     {},
+	"secret":           {},
+	"token":            {},
+}
+
+// IsSecretField reports whether a JSON field or query parameter with the
+// given name holds a secret.
+func IsSecretField(name string) bool {
+	_, ok := secretFields[strings.ToLower(name)]
+
+	return ok
+}
+
+// Header returns a copy of header with the values of secret headers replaced
+// by [Placeholder].
+func Header(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := header.Clone()
+
+	for _, key := range secretHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, Placeholder)
+		}
+	}
+
+	return redacted
+}
+
+// Query returns a copy of query with the values of secret parameters
+// replaced by [Placeholder].
+func Query(query url.Values) url.Values {
+	redacted := make(url.Values, len(query))
+
+	for key, values := range query {
+		if IsSecretField(key) {
+			redacted.Set(key, Placeholder)
+			continue
+		}
+
+		redacted[key] = values
+	}
+
+	return redacted
+}
+
+// URL returns u without user information and with secret query parameters
+// redacted.
+func URL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = Query(u.Query()).Encode()
+
+	return redacted.String()
+}
+
+// JSON replaces the values of secret fields in a JSON document, at any depth.
+// Bodies that are not JSON are returned as is.
+func JSON(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	// numbers are kept as is, instead of being converted to float64
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if IsSecretField(key) {
+				v[key] = Placeholder
+				continue
+			}
+
+			v[key] = redactValue(field)
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
@@ -42,6 +42,9 @@ func ContextWithTracer(ctx context.Context, tracer trace.Tracer) context.Context
 // the `WithAttributes()` SpanOption as samplers will only have access to the
 // attributes provided when a Span is created.
 //
+// The tracer is taken from the context, or from the global tracer provider if
+// the context has none.
+//
 // Any Span that is created MUST also be ended. This is the responsibility of the user.
 // Implementations of this API may leak memory or other resources if Spans are not ended.
 func StartSpan( //nolint:ireturn
@@ -51,7 +54,9 @@ func StartSpan( //nolint:ireturn
 ) (context.Context, trace.Span) {
 	tracer, ok := TracerFromContext(ctx)
 	if !ok {
-		panic("no tracer found in context")
+		// packages such as the platform client are also used outside the
+		// CLI, where spans go to the global tracer provider of the program
+		tracer = otel.Tracer(build.AppName)
 	}
 
 	return tracer.Start(ctx, name, opts...) //nolint:spancheck
@@ -0,0 +1,126 @@
+package ux
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const progressInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Progress shows the state of a long-running operation. On a terminal, a
+// single line is redrawn with a spinner and the time that has passed.
+// Elsewhere, such as in CI logs, a line is printed whenever the state
+// changes.
+type Progress struct {
+	w     io.Writer
+	live  bool
+	start time.Time
+
+	mu     sync.Mutex
+	status string
+	frame  int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewProgress starts showing progress on w.
+func NewProgress(w io.Writer) *Progress {
+	progress := &Progress{
+		w:      w,
+		live:   isTerminal(w),
+		start:  time.Now(),
+		mu:     sync.Mutex{},
+		status: "",
+		frame:  0,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	if !progress.live {
+		close(progress.done)
+
+		return progress
+	}
+
+	go progress.animate()
+
+	return progress
+}
+
+// Update sets the state that is shown.
+func (p *Progress) Update(status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if status == p.status {
+		return
+	}
+
+	p.status = status
+
+	if !p.live {
+		Fprintf(p.w, "[%s] %s\n", p.elapsed(), status)
+
+		return
+	}
+
+	p.draw()
+}
+
+// Stop stops showing progress, and clears the line on a terminal so that
+// the outcome can be printed in its place.
+func (p *Progress) Stop() {
+	select {
+	case <-p.stop:
+		return
+	default:
+		close(p.stop)
+	}
+
+	<-p.done
+
+	if p.live {
+		Fprintf(p.w, "\r\033[K")
+	}
+}
+
+func (p *Progress) animate() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame = (p.frame + 1) % len(spinnerFrames)
+			p.draw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+// draw redraws the line of a terminal. It must be called with mu held.
+func (p *Progress) draw() {
+	Fprintf(p.w, "\r\033[K%s %s %s", StyleInfo.Render(spinnerFrames[p.frame]), p.status,
+		StyleInfo.Render("("+p.elapsed()+")"))
+}
+
+func (p *Progress) elapsed() string {
+	return time.Since(p.start).Truncate(time.Second).String()
+}
+
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(interface{ Fd() uintptr })
+
+	return ok && term.IsTerminal(int(file.Fd())) //nolint:gosec // G115 - file descriptors fit in int
+}
@@ -0,0 +1,22 @@
+package ux
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+
+	progress := NewProgress(&buf)
+	progress.Update("Waiting for deployment")
+	progress.Update("Waiting for deployment")
+	progress.Update("Deploying")
+	progress.Stop()
+	progress.Stop()
+
+	// output that is not a terminal gets a line per state
+	assert.Equal(t, "[0s] Waiting for deployment\n[0s] Deploying\n", buf.String())
+}
@@ -1,6 +1,7 @@
 package ux
 
 import (
+	"io"
 	"strings"
 	"unicode/utf8"
 )
@@ -86,6 +87,13 @@ func (t *Table) String() string {
 
 	var sb strings.Builder
 
+	t.writeHeader(&sb, longestInCol)
+	t.writeRows(&sb, longestInCol)
+
+	return sb.String()
+}
+
+func (t *Table) writeHeader(sb *strings.Builder, longestInCol map[int]int) {
 	// print padded cells
 	for i, header := range t.Header {
 		sb.WriteString(header)
@@ -98,7 +106,9 @@ func (t *Table) String() string {
 	}
 
 	sb.WriteString("\n")
+}
 
+func (t *Table) writeRows(sb *strings.Builder, longestInCol map[int]int) {
 	for _, row := range t.Rows {
 		for i, cell := range row {
 			sb.WriteString(cell)
@@ -112,6 +122,54 @@ func (t *Table) String() string {
 
 		sb.WriteString("\n")
 	}
+}
+
+// TableStream renders a table incrementally, writing rows as soon as a batch
+// of objects is available instead of waiting for the complete list. Column
+// widths are computed from the rows seen so far, so columns may widen, but
+// never shrink, from one batch to the next.
+type TableStream[T any] struct {
+	writer       io.Writer
+	rowFactory   ColFactory[T]
+	longestInCol map[int]int
+	rows         int
+}
+
+func NewTableStream[T any](writer io.Writer, rowFactory ColFactory[T]) *TableStream[T] {
+	return &TableStream[T]{
+		writer:       writer,
+		rowFactory:   rowFactory,
+		longestInCol: make(map[int]int),
+		rows:         0,
+	}
+}
 
-	return sb.String()
+// Write renders a batch of objects. The header is written together with the
+// first non-empty batch.
+func (s *TableStream[T]) Write(objects []T) {
+	if len(objects) == 0 {
+		return
+	}
+
+	table := TableFromObjects(objects, s.rowFactory)
+
+	for col, width := range table.calculateColumnWidths() {
+		s.longestInCol[col] = max(s.longestInCol[col], width)
+	}
+
+	var sb strings.Builder
+
+	if s.rows == 0 {
+		table.writeHeader(&sb, s.longestInCol)
+	}
+
+	table.writeRows(&sb, s.longestInCol)
+	s.rows += len(table.Rows)
+
+	Fprintf(s.writer, "%s", sb.String())
+}
+
+// Rows returns the number of rows written so far.
+func (s *TableStream[T]) Rows() int {
+	return s.rows
 }
@@ -1,9 +1,12 @@
 package ux
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewRow(t *testing.T) {
@@ -261,3 +264,57 @@ func TestTableIntegration(t *testing.T) {
 		assert.Contains(t, output, "moderator")
 	})
 }
+
+func TestTableStream(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+
+	rowFactory := func(i Item) []Row {
+		return []Row{NewRow("Name", i.Name)}
+	}
+
+	t.Run("writes header with the first batch only", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		stream := NewTableStream(&buf, rowFactory)
+		stream.Write([]Item{{Name: "a"}})
+		stream.Write([]Item{{Name: "b"}})
+
+		assert.Equal(t, 1, strings.Count(buf.String(), "Name"))
+		assert.Equal(t, 2, stream.Rows())
+	})
+
+	t.Run("writes nothing for empty batches", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		stream := NewTableStream(&buf, rowFactory)
+		stream.Write(nil)
+
+		assert.Empty(t, buf.String())
+		assert.Equal(t, 0, stream.Rows())
+	})
+
+	t.Run("matches Table output for a single batch", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		items := []Item{{Name: "alice"}, {Name: "bob"}}
+
+		NewTableStream(&buf, rowFactory).Write(items)
+
+		table := TableFromObjects(items, rowFactory)
+		assert.Equal(t, table.String(), buf.String())
+	})
+
+	t.Run("columns widen for later batches", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		stream := NewTableStream(&buf, rowFactory)
+		stream.Write([]Item{{Name: "verylongname"}})
+		stream.Write([]Item{{Name: "b"}})
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, len(lines[1]), len(lines[2]))
+	})
+}
@@ -7,6 +7,8 @@ import (
 
 	client "github.com/intility/indev/pkg/client"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -191,6 +193,56 @@ func (_c *AIAPIKeyClient_GetAIAPIKey_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// IterAIAPIKeys provides a mock function with given fields: ctx, deploymentID, opts
+func (_m *AIAPIKeyClient) IterAIAPIKeys(ctx context.Context, deploymentID string, opts client.ListOptions) iter.Seq2[client.AIAPIKey, error] {
+	ret := _m.Called(ctx, deploymentID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterAIAPIKeys")
+	}
+
+	var r0 iter.Seq2[client.AIAPIKey, error]
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.ListOptions) iter.Seq2[client.AIAPIKey, error]); ok {
+		r0 = rf(ctx, deploymentID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.AIAPIKey, error])
+		}
+	}
+
+	return r0
+}
+
+// AIAPIKeyClient_IterAIAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterAIAPIKeys'
+type AIAPIKeyClient_IterAIAPIKeys_Call struct {
+	*mock.Call
+}
+
+// IterAIAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deploymentID string
+//   - opts client.ListOptions
+func (_e *AIAPIKeyClient_Expecter) IterAIAPIKeys(ctx interface{}, deploymentID interface{}, opts interface{}) *AIAPIKeyClient_IterAIAPIKeys_Call {
+	return &AIAPIKeyClient_IterAIAPIKeys_Call{Call: _e.mock.On("IterAIAPIKeys", ctx, deploymentID, opts)}
+}
+
+func (_c *AIAPIKeyClient_IterAIAPIKeys_Call) Run(run func(ctx context.Context, deploymentID string, opts client.ListOptions)) *AIAPIKeyClient_IterAIAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *AIAPIKeyClient_IterAIAPIKeys_Call) Return(_a0 iter.Seq2[client.AIAPIKey, error]) *AIAPIKeyClient_IterAIAPIKeys_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AIAPIKeyClient_IterAIAPIKeys_Call) RunAndReturn(run func(context.Context, string, client.ListOptions) iter.Seq2[client.AIAPIKey, error]) *AIAPIKeyClient_IterAIAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAIAPIKeys provides a mock function with given fields: ctx, deploymentID
 func (_m *AIAPIKeyClient) ListAIAPIKeys(ctx context.Context, deploymentID string) ([]client.AIAPIKey, error) {
 	ret := _m.Called(ctx, deploymentID)
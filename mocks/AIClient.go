@@ -7,6 +7,8 @@ import (
 
 	client "github.com/intility/indev/pkg/client"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -188,6 +190,55 @@ func (_c *AIClient_GetAIDeployment_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// IterAIDeployments provides a mock function with given fields: ctx, opts
+func (_m *AIClient) IterAIDeployments(ctx context.Context, opts client.ListOptions) iter.Seq2[client.AIDeployment, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterAIDeployments")
+	}
+
+	var r0 iter.Seq2[client.AIDeployment, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.AIDeployment, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.AIDeployment, error])
+		}
+	}
+
+	return r0
+}
+
+// AIClient_IterAIDeployments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterAIDeployments'
+type AIClient_IterAIDeployments_Call struct {
+	*mock.Call
+}
+
+// IterAIDeployments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *AIClient_Expecter) IterAIDeployments(ctx interface{}, opts interface{}) *AIClient_IterAIDeployments_Call {
+	return &AIClient_IterAIDeployments_Call{Call: _e.mock.On("IterAIDeployments", ctx, opts)}
+}
+
+func (_c *AIClient_IterAIDeployments_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *AIClient_IterAIDeployments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *AIClient_IterAIDeployments_Call) Return(_a0 iter.Seq2[client.AIDeployment, error]) *AIClient_IterAIDeployments_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AIClient_IterAIDeployments_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.AIDeployment, error]) *AIClient_IterAIDeployments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAIDeployments provides a mock function with given fields: ctx
 func (_m *AIClient) ListAIDeployments(ctx context.Context) ([]client.AIDeployment, error) {
 	ret := _m.Called(ctx)
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	public "github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClaimsChallenger is an autogenerated mock type for the ClaimsChallenger type
+type ClaimsChallenger struct {
+	mock.Mock
+}
+
+type ClaimsChallenger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClaimsChallenger) EXPECT() *ClaimsChallenger_Expecter {
+	return &ClaimsChallenger_Expecter{mock: &_m.Mock}
+}
+
+// TokenWithClaims provides a mock function with given fields: ctx, claims
+func (_m *ClaimsChallenger) TokenWithClaims(ctx context.Context, claims string) (public.AuthResult, error) {
+	ret := _m.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TokenWithClaims")
+	}
+
+	var r0 public.AuthResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (public.AuthResult, error)); ok {
+		return rf(ctx, claims)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) public.AuthResult); ok {
+		r0 = rf(ctx, claims)
+	} else {
+		r0 = ret.Get(0).(public.AuthResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimsChallenger_TokenWithClaims_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TokenWithClaims'
+type ClaimsChallenger_TokenWithClaims_Call struct {
+	*mock.Call
+}
+
+// TokenWithClaims is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims string
+func (_e *ClaimsChallenger_Expecter) TokenWithClaims(ctx interface{}, claims interface{}) *ClaimsChallenger_TokenWithClaims_Call {
+	return &ClaimsChallenger_TokenWithClaims_Call{Call: _e.mock.On("TokenWithClaims", ctx, claims)}
+}
+
+func (_c *ClaimsChallenger_TokenWithClaims_Call) Run(run func(ctx context.Context, claims string)) *ClaimsChallenger_TokenWithClaims_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ClaimsChallenger_TokenWithClaims_Call) Return(_a0 public.AuthResult, _a1 error) *ClaimsChallenger_TokenWithClaims_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClaimsChallenger_TokenWithClaims_Call) RunAndReturn(run func(context.Context, string) (public.AuthResult, error)) *ClaimsChallenger_TokenWithClaims_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewClaimsChallenger creates a new instance of ClaimsChallenger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClaimsChallenger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClaimsChallenger {
+	mock := &ClaimsChallenger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
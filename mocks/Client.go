@@ -7,6 +7,8 @@ import (
 
 	client "github.com/intility/indev/pkg/client"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -71,6 +73,66 @@ func (_c *Client_AddClusterMember_Call) RunAndReturn(run func(context.Context, s
 	return _c
 }
 
+// AddNodePool provides a mock function with given fields: ctx, clusterID, pool
+func (_m *Client) AddNodePool(ctx context.Context, clusterID string, pool client.NodePool) (*client.NodePool, error) {
+	ret := _m.Called(ctx, clusterID, pool)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNodePool")
+	}
+
+	var r0 *client.NodePool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) (*client.NodePool, error)); ok {
+		return rf(ctx, clusterID, pool)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) *client.NodePool); ok {
+		r0 = rf(ctx, clusterID, pool)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.NodePool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.NodePool) error); ok {
+		r1 = rf(ctx, clusterID, pool)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_AddNodePool_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNodePool'
+type Client_AddNodePool_Call struct {
+	*mock.Call
+}
+
+// AddNodePool is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - pool client.NodePool
+func (_e *Client_Expecter) AddNodePool(ctx interface{}, clusterID interface{}, pool interface{}) *Client_AddNodePool_Call {
+	return &Client_AddNodePool_Call{Call: _e.mock.On("AddNodePool", ctx, clusterID, pool)}
+}
+
+func (_c *Client_AddNodePool_Call) Run(run func(ctx context.Context, clusterID string, pool client.NodePool)) *Client_AddNodePool_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.NodePool))
+	})
+	return _c
+}
+
+func (_c *Client_AddNodePool_Call) Return(_a0 *client.NodePool, _a1 error) *Client_AddNodePool_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_AddNodePool_Call) RunAndReturn(run func(context.Context, string, client.NodePool) (*client.NodePool, error)) *Client_AddNodePool_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddTeamMember provides a mock function with given fields: ctx, teamID, request
 func (_m *Client) AddTeamMember(ctx context.Context, teamID string, request []client.AddTeamMemberRequest) error {
 	ret := _m.Called(ctx, teamID, request)
@@ -297,6 +359,65 @@ func (_c *Client_CreateCluster_Call) RunAndReturn(run func(context.Context, clie
 	return _c
 }
 
+// CreatePullSecret provides a mock function with given fields: ctx, request
+func (_m *Client) CreatePullSecret(ctx context.Context, request client.NewPullSecretRequest) (*client.PullSecret, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePullSecret")
+	}
+
+	var r0 *client.PullSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.NewPullSecretRequest) (*client.PullSecret, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.NewPullSecretRequest) *client.PullSecret); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.PullSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.NewPullSecretRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_CreatePullSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePullSecret'
+type Client_CreatePullSecret_Call struct {
+	*mock.Call
+}
+
+// CreatePullSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request client.NewPullSecretRequest
+func (_e *Client_Expecter) CreatePullSecret(ctx interface{}, request interface{}) *Client_CreatePullSecret_Call {
+	return &Client_CreatePullSecret_Call{Call: _e.mock.On("CreatePullSecret", ctx, request)}
+}
+
+func (_c *Client_CreatePullSecret_Call) Run(run func(ctx context.Context, request client.NewPullSecretRequest)) *Client_CreatePullSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.NewPullSecretRequest))
+	})
+	return _c
+}
+
+func (_c *Client_CreatePullSecret_Call) Return(_a0 *client.PullSecret, _a1 error) *Client_CreatePullSecret_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_CreatePullSecret_Call) RunAndReturn(run func(context.Context, client.NewPullSecretRequest) (*client.PullSecret, error)) *Client_CreatePullSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateTeam provides a mock function with given fields: ctx, request
 func (_m *Client) CreateTeam(ctx context.Context, request client.NewTeamRequest) (*client.Team, error) {
 	ret := _m.Called(ctx, request)
@@ -498,6 +619,101 @@ func (_c *Client_DeleteCluster_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// DeleteNodePool provides a mock function with given fields: ctx, clusterID, poolID
+func (_m *Client) DeleteNodePool(ctx context.Context, clusterID string, poolID string) error {
+	ret := _m.Called(ctx, clusterID, poolID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteNodePool")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, clusterID, poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_DeleteNodePool_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteNodePool'
+type Client_DeleteNodePool_Call struct {
+	*mock.Call
+}
+
+// DeleteNodePool is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - poolID string
+func (_e *Client_Expecter) DeleteNodePool(ctx interface{}, clusterID interface{}, poolID interface{}) *Client_DeleteNodePool_Call {
+	return &Client_DeleteNodePool_Call{Call: _e.mock.On("DeleteNodePool", ctx, clusterID, poolID)}
+}
+
+func (_c *Client_DeleteNodePool_Call) Run(run func(ctx context.Context, clusterID string, poolID string)) *Client_DeleteNodePool_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteNodePool_Call) Return(_a0 error) *Client_DeleteNodePool_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_DeleteNodePool_Call) RunAndReturn(run func(context.Context, string, string) error) *Client_DeleteNodePool_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePullSecret provides a mock function with given fields: ctx, id
+func (_m *Client) DeletePullSecret(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePullSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_DeletePullSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePullSecret'
+type Client_DeletePullSecret_Call struct {
+	*mock.Call
+}
+
+// DeletePullSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Client_Expecter) DeletePullSecret(ctx interface{}, id interface{}) *Client_DeletePullSecret_Call {
+	return &Client_DeletePullSecret_Call{Call: _e.mock.On("DeletePullSecret", ctx, id)}
+}
+
+func (_c *Client_DeletePullSecret_Call) Run(run func(ctx context.Context, id string)) *Client_DeletePullSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeletePullSecret_Call) Return(_a0 error) *Client_DeletePullSecret_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_DeletePullSecret_Call) RunAndReturn(run func(context.Context, string) error) *Client_DeletePullSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteTeam provides a mock function with given fields: ctx, request
 func (_m *Client) DeleteTeam(ctx context.Context, request client.DeleteTeamRequest) error {
 	ret := _m.Called(ctx, request)
@@ -897,6 +1113,65 @@ func (_c *Client_GetMe_Call) RunAndReturn(run func(context.Context) (client.Me,
 	return _c
 }
 
+// GetPullSecret provides a mock function with given fields: ctx, name
+func (_m *Client) GetPullSecret(ctx context.Context, name string) (*client.PullSecret, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPullSecret")
+	}
+
+	var r0 *client.PullSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.PullSecret, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.PullSecret); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.PullSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetPullSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPullSecret'
+type Client_GetPullSecret_Call struct {
+	*mock.Call
+}
+
+// GetPullSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Client_Expecter) GetPullSecret(ctx interface{}, name interface{}) *Client_GetPullSecret_Call {
+	return &Client_GetPullSecret_Call{Call: _e.mock.On("GetPullSecret", ctx, name)}
+}
+
+func (_c *Client_GetPullSecret_Call) Run(run func(ctx context.Context, name string)) *Client_GetPullSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_GetPullSecret_Call) Return(_a0 *client.PullSecret, _a1 error) *Client_GetPullSecret_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetPullSecret_Call) RunAndReturn(run func(context.Context, string) (*client.PullSecret, error)) *Client_GetPullSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTeam provides a mock function with given fields: ctx, name
 func (_m *Client) GetTeam(ctx context.Context, name string) (*client.Team, error) {
 	ret := _m.Called(ctx, name)
@@ -1074,119 +1349,414 @@ func (_c *Client_GetUser_Call) RunAndReturn(run func(context.Context, string) (*
 	return _c
 }
 
-// ListAIAPIKeys provides a mock function with given fields: ctx, deploymentID
-func (_m *Client) ListAIAPIKeys(ctx context.Context, deploymentID string) ([]client.AIAPIKey, error) {
-	ret := _m.Called(ctx, deploymentID)
+// IterAIAPIKeys provides a mock function with given fields: ctx, deploymentID, opts
+func (_m *Client) IterAIAPIKeys(ctx context.Context, deploymentID string, opts client.ListOptions) iter.Seq2[client.AIAPIKey, error] {
+	ret := _m.Called(ctx, deploymentID, opts)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAIAPIKeys")
+		panic("no return value specified for IterAIAPIKeys")
 	}
 
-	var r0 []client.AIAPIKey
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]client.AIAPIKey, error)); ok {
-		return rf(ctx, deploymentID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []client.AIAPIKey); ok {
-		r0 = rf(ctx, deploymentID)
+	var r0 iter.Seq2[client.AIAPIKey, error]
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.ListOptions) iter.Seq2[client.AIAPIKey, error]); ok {
+		r0 = rf(ctx, deploymentID, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]client.AIAPIKey)
+			r0 = ret.Get(0).(iter.Seq2[client.AIAPIKey, error])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, deploymentID)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
+	return r0
 }
 
-// Client_ListAIAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAIAPIKeys'
-type Client_ListAIAPIKeys_Call struct {
+// Client_IterAIAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterAIAPIKeys'
+type Client_IterAIAPIKeys_Call struct {
 	*mock.Call
 }
 
-// ListAIAPIKeys is a helper method to define mock.On call
+// IterAIAPIKeys is a helper method to define mock.On call
 //   - ctx context.Context
 //   - deploymentID string
-func (_e *Client_Expecter) ListAIAPIKeys(ctx interface{}, deploymentID interface{}) *Client_ListAIAPIKeys_Call {
-	return &Client_ListAIAPIKeys_Call{Call: _e.mock.On("ListAIAPIKeys", ctx, deploymentID)}
+//   - opts client.ListOptions
+func (_e *Client_Expecter) IterAIAPIKeys(ctx interface{}, deploymentID interface{}, opts interface{}) *Client_IterAIAPIKeys_Call {
+	return &Client_IterAIAPIKeys_Call{Call: _e.mock.On("IterAIAPIKeys", ctx, deploymentID, opts)}
 }
 
-func (_c *Client_ListAIAPIKeys_Call) Run(run func(ctx context.Context, deploymentID string)) *Client_ListAIAPIKeys_Call {
+func (_c *Client_IterAIAPIKeys_Call) Run(run func(ctx context.Context, deploymentID string, opts client.ListOptions)) *Client_IterAIAPIKeys_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(client.ListOptions))
 	})
 	return _c
 }
 
-func (_c *Client_ListAIAPIKeys_Call) Return(_a0 []client.AIAPIKey, _a1 error) *Client_ListAIAPIKeys_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *Client_IterAIAPIKeys_Call) Return(_a0 iter.Seq2[client.AIAPIKey, error]) *Client_IterAIAPIKeys_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Client_ListAIAPIKeys_Call) RunAndReturn(run func(context.Context, string) ([]client.AIAPIKey, error)) *Client_ListAIAPIKeys_Call {
+func (_c *Client_IterAIAPIKeys_Call) RunAndReturn(run func(context.Context, string, client.ListOptions) iter.Seq2[client.AIAPIKey, error]) *Client_IterAIAPIKeys_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListAIDeployments provides a mock function with given fields: ctx
-func (_m *Client) ListAIDeployments(ctx context.Context) ([]client.AIDeployment, error) {
-	ret := _m.Called(ctx)
+// IterAIDeployments provides a mock function with given fields: ctx, opts
+func (_m *Client) IterAIDeployments(ctx context.Context, opts client.ListOptions) iter.Seq2[client.AIDeployment, error] {
+	ret := _m.Called(ctx, opts)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAIDeployments")
+		panic("no return value specified for IterAIDeployments")
 	}
 
-	var r0 []client.AIDeployment
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]client.AIDeployment, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) []client.AIDeployment); ok {
-		r0 = rf(ctx)
+	var r0 iter.Seq2[client.AIDeployment, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.AIDeployment, error]); ok {
+		r0 = rf(ctx, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]client.AIDeployment)
+			r0 = ret.Get(0).(iter.Seq2[client.AIDeployment, error])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
+	return r0
 }
 
-// Client_ListAIDeployments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAIDeployments'
-type Client_ListAIDeployments_Call struct {
+// Client_IterAIDeployments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterAIDeployments'
+type Client_IterAIDeployments_Call struct {
 	*mock.Call
 }
 
-// ListAIDeployments is a helper method to define mock.On call
+// IterAIDeployments is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *Client_Expecter) ListAIDeployments(ctx interface{}) *Client_ListAIDeployments_Call {
-	return &Client_ListAIDeployments_Call{Call: _e.mock.On("ListAIDeployments", ctx)}
+//   - opts client.ListOptions
+func (_e *Client_Expecter) IterAIDeployments(ctx interface{}, opts interface{}) *Client_IterAIDeployments_Call {
+	return &Client_IterAIDeployments_Call{Call: _e.mock.On("IterAIDeployments", ctx, opts)}
 }
 
-func (_c *Client_ListAIDeployments_Call) Run(run func(ctx context.Context)) *Client_ListAIDeployments_Call {
+func (_c *Client_IterAIDeployments_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *Client_IterAIDeployments_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(client.ListOptions))
 	})
 	return _c
 }
 
-func (_c *Client_ListAIDeployments_Call) Return(_a0 []client.AIDeployment, _a1 error) *Client_ListAIDeployments_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *Client_IterAIDeployments_Call) Return(_a0 iter.Seq2[client.AIDeployment, error]) *Client_IterAIDeployments_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Client_ListAIDeployments_Call) RunAndReturn(run func(context.Context) ([]client.AIDeployment, error)) *Client_ListAIDeployments_Call {
+func (_c *Client_IterAIDeployments_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.AIDeployment, error]) *Client_IterAIDeployments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IterClusters provides a mock function with given fields: ctx, opts
+func (_m *Client) IterClusters(ctx context.Context, opts client.ListOptions) iter.Seq2[client.Cluster, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterClusters")
+	}
+
+	var r0 iter.Seq2[client.Cluster, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.Cluster, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.Cluster, error])
+		}
+	}
+
+	return r0
+}
+
+// Client_IterClusters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterClusters'
+type Client_IterClusters_Call struct {
+	*mock.Call
+}
+
+// IterClusters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *Client_Expecter) IterClusters(ctx interface{}, opts interface{}) *Client_IterClusters_Call {
+	return &Client_IterClusters_Call{Call: _e.mock.On("IterClusters", ctx, opts)}
+}
+
+func (_c *Client_IterClusters_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *Client_IterClusters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *Client_IterClusters_Call) Return(_a0 iter.Seq2[client.Cluster, error]) *Client_IterClusters_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_IterClusters_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.Cluster, error]) *Client_IterClusters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IterPullSecrets provides a mock function with given fields: ctx, opts
+func (_m *Client) IterPullSecrets(ctx context.Context, opts client.ListOptions) iter.Seq2[client.PullSecret, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterPullSecrets")
+	}
+
+	var r0 iter.Seq2[client.PullSecret, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.PullSecret, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.PullSecret, error])
+		}
+	}
+
+	return r0
+}
+
+// Client_IterPullSecrets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterPullSecrets'
+type Client_IterPullSecrets_Call struct {
+	*mock.Call
+}
+
+// IterPullSecrets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *Client_Expecter) IterPullSecrets(ctx interface{}, opts interface{}) *Client_IterPullSecrets_Call {
+	return &Client_IterPullSecrets_Call{Call: _e.mock.On("IterPullSecrets", ctx, opts)}
+}
+
+func (_c *Client_IterPullSecrets_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *Client_IterPullSecrets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *Client_IterPullSecrets_Call) Return(_a0 iter.Seq2[client.PullSecret, error]) *Client_IterPullSecrets_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_IterPullSecrets_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.PullSecret, error]) *Client_IterPullSecrets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IterTeams provides a mock function with given fields: ctx, opts
+func (_m *Client) IterTeams(ctx context.Context, opts client.ListOptions) iter.Seq2[client.Team, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterTeams")
+	}
+
+	var r0 iter.Seq2[client.Team, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.Team, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.Team, error])
+		}
+	}
+
+	return r0
+}
+
+// Client_IterTeams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterTeams'
+type Client_IterTeams_Call struct {
+	*mock.Call
+}
+
+// IterTeams is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *Client_Expecter) IterTeams(ctx interface{}, opts interface{}) *Client_IterTeams_Call {
+	return &Client_IterTeams_Call{Call: _e.mock.On("IterTeams", ctx, opts)}
+}
+
+func (_c *Client_IterTeams_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *Client_IterTeams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *Client_IterTeams_Call) Return(_a0 iter.Seq2[client.Team, error]) *Client_IterTeams_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_IterTeams_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.Team, error]) *Client_IterTeams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IterUsers provides a mock function with given fields: ctx, opts
+func (_m *Client) IterUsers(ctx context.Context, opts client.ListOptions) iter.Seq2[client.User, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterUsers")
+	}
+
+	var r0 iter.Seq2[client.User, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.User, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.User, error])
+		}
+	}
+
+	return r0
+}
+
+// Client_IterUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterUsers'
+type Client_IterUsers_Call struct {
+	*mock.Call
+}
+
+// IterUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *Client_Expecter) IterUsers(ctx interface{}, opts interface{}) *Client_IterUsers_Call {
+	return &Client_IterUsers_Call{Call: _e.mock.On("IterUsers", ctx, opts)}
+}
+
+func (_c *Client_IterUsers_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *Client_IterUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *Client_IterUsers_Call) Return(_a0 iter.Seq2[client.User, error]) *Client_IterUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_IterUsers_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.User, error]) *Client_IterUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAIAPIKeys provides a mock function with given fields: ctx, deploymentID
+func (_m *Client) ListAIAPIKeys(ctx context.Context, deploymentID string) ([]client.AIAPIKey, error) {
+	ret := _m.Called(ctx, deploymentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAIAPIKeys")
+	}
+
+	var r0 []client.AIAPIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]client.AIAPIKey, error)); ok {
+		return rf(ctx, deploymentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []client.AIAPIKey); ok {
+		r0 = rf(ctx, deploymentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.AIAPIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, deploymentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListAIAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAIAPIKeys'
+type Client_ListAIAPIKeys_Call struct {
+	*mock.Call
+}
+
+// ListAIAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deploymentID string
+func (_e *Client_Expecter) ListAIAPIKeys(ctx interface{}, deploymentID interface{}) *Client_ListAIAPIKeys_Call {
+	return &Client_ListAIAPIKeys_Call{Call: _e.mock.On("ListAIAPIKeys", ctx, deploymentID)}
+}
+
+func (_c *Client_ListAIAPIKeys_Call) Run(run func(ctx context.Context, deploymentID string)) *Client_ListAIAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_ListAIAPIKeys_Call) Return(_a0 []client.AIAPIKey, _a1 error) *Client_ListAIAPIKeys_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListAIAPIKeys_Call) RunAndReturn(run func(context.Context, string) ([]client.AIAPIKey, error)) *Client_ListAIAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAIDeployments provides a mock function with given fields: ctx
+func (_m *Client) ListAIDeployments(ctx context.Context) ([]client.AIDeployment, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAIDeployments")
+	}
+
+	var r0 []client.AIDeployment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]client.AIDeployment, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []client.AIDeployment); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.AIDeployment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListAIDeployments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAIDeployments'
+type Client_ListAIDeployments_Call struct {
+	*mock.Call
+}
+
+// ListAIDeployments is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Client_Expecter) ListAIDeployments(ctx interface{}) *Client_ListAIDeployments_Call {
+	return &Client_ListAIDeployments_Call{Call: _e.mock.On("ListAIDeployments", ctx)}
+}
+
+func (_c *Client_ListAIDeployments_Call) Run(run func(ctx context.Context)) *Client_ListAIDeployments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_ListAIDeployments_Call) Return(_a0 []client.AIDeployment, _a1 error) *Client_ListAIDeployments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListAIDeployments_Call) RunAndReturn(run func(context.Context) ([]client.AIDeployment, error)) *Client_ListAIDeployments_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1249,6 +1819,64 @@ func (_c *Client_ListAIModels_Call) RunAndReturn(run func(context.Context) ([]cl
 	return _c
 }
 
+// ListClusterVersions provides a mock function with given fields: ctx
+func (_m *Client) ListClusterVersions(ctx context.Context) ([]client.ClusterVersion, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListClusterVersions")
+	}
+
+	var r0 []client.ClusterVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]client.ClusterVersion, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []client.ClusterVersion); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.ClusterVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListClusterVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListClusterVersions'
+type Client_ListClusterVersions_Call struct {
+	*mock.Call
+}
+
+// ListClusterVersions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Client_Expecter) ListClusterVersions(ctx interface{}) *Client_ListClusterVersions_Call {
+	return &Client_ListClusterVersions_Call{Call: _e.mock.On("ListClusterVersions", ctx)}
+}
+
+func (_c *Client_ListClusterVersions_Call) Run(run func(ctx context.Context)) *Client_ListClusterVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_ListClusterVersions_Call) Return(_a0 []client.ClusterVersion, _a1 error) *Client_ListClusterVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListClusterVersions_Call) RunAndReturn(run func(context.Context) ([]client.ClusterVersion, error)) *Client_ListClusterVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListClusters provides a mock function with given fields: ctx
 func (_m *Client) ListClusters(ctx context.Context) (client.ClusterList, error) {
 	ret := _m.Called(ctx)
@@ -1365,6 +1993,123 @@ func (_c *Client_ListIntegrationInstances_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// ListNodePools provides a mock function with given fields: ctx, clusterID
+func (_m *Client) ListNodePools(ctx context.Context, clusterID string) (client.NodePools, error) {
+	ret := _m.Called(ctx, clusterID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNodePools")
+	}
+
+	var r0 client.NodePools
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (client.NodePools, error)); ok {
+		return rf(ctx, clusterID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) client.NodePools); ok {
+		r0 = rf(ctx, clusterID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.NodePools)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, clusterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListNodePools_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNodePools'
+type Client_ListNodePools_Call struct {
+	*mock.Call
+}
+
+// ListNodePools is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+func (_e *Client_Expecter) ListNodePools(ctx interface{}, clusterID interface{}) *Client_ListNodePools_Call {
+	return &Client_ListNodePools_Call{Call: _e.mock.On("ListNodePools", ctx, clusterID)}
+}
+
+func (_c *Client_ListNodePools_Call) Run(run func(ctx context.Context, clusterID string)) *Client_ListNodePools_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_ListNodePools_Call) Return(_a0 client.NodePools, _a1 error) *Client_ListNodePools_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListNodePools_Call) RunAndReturn(run func(context.Context, string) (client.NodePools, error)) *Client_ListNodePools_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPullSecrets provides a mock function with given fields: ctx
+func (_m *Client) ListPullSecrets(ctx context.Context) ([]client.PullSecret, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullSecrets")
+	}
+
+	var r0 []client.PullSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]client.PullSecret, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []client.PullSecret); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.PullSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListPullSecrets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPullSecrets'
+type Client_ListPullSecrets_Call struct {
+	*mock.Call
+}
+
+// ListPullSecrets is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Client_Expecter) ListPullSecrets(ctx interface{}) *Client_ListPullSecrets_Call {
+	return &Client_ListPullSecrets_Call{Call: _e.mock.On("ListPullSecrets", ctx)}
+}
+
+func (_c *Client_ListPullSecrets_Call) Run(run func(ctx context.Context)) *Client_ListPullSecrets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_ListPullSecrets_Call) Return(_a0 []client.PullSecret, _a1 error) *Client_ListPullSecrets_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListPullSecrets_Call) RunAndReturn(run func(context.Context) ([]client.PullSecret, error)) *Client_ListPullSecrets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListTeams provides a mock function with given fields: ctx
 func (_m *Client) ListTeams(ctx context.Context) ([]client.Team, error) {
 	ret := _m.Called(ctx)
@@ -1577,6 +2322,126 @@ func (_c *Client_RemoveTeamMember_Call) RunAndReturn(run func(context.Context, s
 	return _c
 }
 
+// UpdateCluster provides a mock function with given fields: ctx, clusterID, request
+func (_m *Client) UpdateCluster(ctx context.Context, clusterID string, request client.UpdateClusterRequest) (*client.Cluster, error) {
+	ret := _m.Called(ctx, clusterID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCluster")
+	}
+
+	var r0 *client.Cluster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.UpdateClusterRequest) (*client.Cluster, error)); ok {
+		return rf(ctx, clusterID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.UpdateClusterRequest) *client.Cluster); ok {
+		r0 = rf(ctx, clusterID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Cluster)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.UpdateClusterRequest) error); ok {
+		r1 = rf(ctx, clusterID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_UpdateCluster_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCluster'
+type Client_UpdateCluster_Call struct {
+	*mock.Call
+}
+
+// UpdateCluster is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - request client.UpdateClusterRequest
+func (_e *Client_Expecter) UpdateCluster(ctx interface{}, clusterID interface{}, request interface{}) *Client_UpdateCluster_Call {
+	return &Client_UpdateCluster_Call{Call: _e.mock.On("UpdateCluster", ctx, clusterID, request)}
+}
+
+func (_c *Client_UpdateCluster_Call) Run(run func(ctx context.Context, clusterID string, request client.UpdateClusterRequest)) *Client_UpdateCluster_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.UpdateClusterRequest))
+	})
+	return _c
+}
+
+func (_c *Client_UpdateCluster_Call) Return(_a0 *client.Cluster, _a1 error) *Client_UpdateCluster_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_UpdateCluster_Call) RunAndReturn(run func(context.Context, string, client.UpdateClusterRequest) (*client.Cluster, error)) *Client_UpdateCluster_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNodePool provides a mock function with given fields: ctx, clusterID, pool
+func (_m *Client) UpdateNodePool(ctx context.Context, clusterID string, pool client.NodePool) (*client.NodePool, error) {
+	ret := _m.Called(ctx, clusterID, pool)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNodePool")
+	}
+
+	var r0 *client.NodePool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) (*client.NodePool, error)); ok {
+		return rf(ctx, clusterID, pool)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) *client.NodePool); ok {
+		r0 = rf(ctx, clusterID, pool)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.NodePool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.NodePool) error); ok {
+		r1 = rf(ctx, clusterID, pool)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_UpdateNodePool_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNodePool'
+type Client_UpdateNodePool_Call struct {
+	*mock.Call
+}
+
+// UpdateNodePool is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - pool client.NodePool
+func (_e *Client_Expecter) UpdateNodePool(ctx interface{}, clusterID interface{}, pool interface{}) *Client_UpdateNodePool_Call {
+	return &Client_UpdateNodePool_Call{Call: _e.mock.On("UpdateNodePool", ctx, clusterID, pool)}
+}
+
+func (_c *Client_UpdateNodePool_Call) Run(run func(ctx context.Context, clusterID string, pool client.NodePool)) *Client_UpdateNodePool_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.NodePool))
+	})
+	return _c
+}
+
+func (_c *Client_UpdateNodePool_Call) Return(_a0 *client.NodePool, _a1 error) *Client_UpdateNodePool_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_UpdateNodePool_Call) RunAndReturn(run func(context.Context, string, client.NodePool) (*client.NodePool, error)) *Client_UpdateNodePool_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
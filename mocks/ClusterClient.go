@@ -7,6 +7,8 @@ import (
 
 	client "github.com/intility/indev/pkg/client"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -71,6 +73,66 @@ func (_c *ClusterClient_AddClusterMember_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// AddNodePool provides a mock function with given fields: ctx, clusterID, pool
+func (_m *ClusterClient) AddNodePool(ctx context.Context, clusterID string, pool client.NodePool) (*client.NodePool, error) {
+	ret := _m.Called(ctx, clusterID, pool)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNodePool")
+	}
+
+	var r0 *client.NodePool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) (*client.NodePool, error)); ok {
+		return rf(ctx, clusterID, pool)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) *client.NodePool); ok {
+		r0 = rf(ctx, clusterID, pool)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.NodePool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.NodePool) error); ok {
+		r1 = rf(ctx, clusterID, pool)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClusterClient_AddNodePool_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNodePool'
+type ClusterClient_AddNodePool_Call struct {
+	*mock.Call
+}
+
+// AddNodePool is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - pool client.NodePool
+func (_e *ClusterClient_Expecter) AddNodePool(ctx interface{}, clusterID interface{}, pool interface{}) *ClusterClient_AddNodePool_Call {
+	return &ClusterClient_AddNodePool_Call{Call: _e.mock.On("AddNodePool", ctx, clusterID, pool)}
+}
+
+func (_c *ClusterClient_AddNodePool_Call) Run(run func(ctx context.Context, clusterID string, pool client.NodePool)) *ClusterClient_AddNodePool_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.NodePool))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_AddNodePool_Call) Return(_a0 *client.NodePool, _a1 error) *ClusterClient_AddNodePool_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClusterClient_AddNodePool_Call) RunAndReturn(run func(context.Context, string, client.NodePool) (*client.NodePool, error)) *ClusterClient_AddNodePool_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateCluster provides a mock function with given fields: ctx, request
 func (_m *ClusterClient) CreateCluster(ctx context.Context, request client.NewClusterRequest) (*client.Cluster, error) {
 	ret := _m.Called(ctx, request)
@@ -177,6 +239,54 @@ func (_c *ClusterClient_DeleteCluster_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// DeleteNodePool provides a mock function with given fields: ctx, clusterID, poolID
+func (_m *ClusterClient) DeleteNodePool(ctx context.Context, clusterID string, poolID string) error {
+	ret := _m.Called(ctx, clusterID, poolID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteNodePool")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, clusterID, poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClusterClient_DeleteNodePool_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteNodePool'
+type ClusterClient_DeleteNodePool_Call struct {
+	*mock.Call
+}
+
+// DeleteNodePool is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - poolID string
+func (_e *ClusterClient_Expecter) DeleteNodePool(ctx interface{}, clusterID interface{}, poolID interface{}) *ClusterClient_DeleteNodePool_Call {
+	return &ClusterClient_DeleteNodePool_Call{Call: _e.mock.On("DeleteNodePool", ctx, clusterID, poolID)}
+}
+
+func (_c *ClusterClient_DeleteNodePool_Call) Run(run func(ctx context.Context, clusterID string, poolID string)) *ClusterClient_DeleteNodePool_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_DeleteNodePool_Call) Return(_a0 error) *ClusterClient_DeleteNodePool_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ClusterClient_DeleteNodePool_Call) RunAndReturn(run func(context.Context, string, string) error) *ClusterClient_DeleteNodePool_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCluster provides a mock function with given fields: ctx, name
 func (_m *ClusterClient) GetCluster(ctx context.Context, name string) (*client.Cluster, error) {
 	ret := _m.Called(ctx, name)
@@ -354,6 +464,113 @@ func (_c *ClusterClient_GetClusterStatus_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// IterClusters provides a mock function with given fields: ctx, opts
+func (_m *ClusterClient) IterClusters(ctx context.Context, opts client.ListOptions) iter.Seq2[client.Cluster, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterClusters")
+	}
+
+	var r0 iter.Seq2[client.Cluster, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.Cluster, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.Cluster, error])
+		}
+	}
+
+	return r0
+}
+
+// ClusterClient_IterClusters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterClusters'
+type ClusterClient_IterClusters_Call struct {
+	*mock.Call
+}
+
+// IterClusters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *ClusterClient_Expecter) IterClusters(ctx interface{}, opts interface{}) *ClusterClient_IterClusters_Call {
+	return &ClusterClient_IterClusters_Call{Call: _e.mock.On("IterClusters", ctx, opts)}
+}
+
+func (_c *ClusterClient_IterClusters_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *ClusterClient_IterClusters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_IterClusters_Call) Return(_a0 iter.Seq2[client.Cluster, error]) *ClusterClient_IterClusters_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ClusterClient_IterClusters_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.Cluster, error]) *ClusterClient_IterClusters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListClusterVersions provides a mock function with given fields: ctx
+func (_m *ClusterClient) ListClusterVersions(ctx context.Context) ([]client.ClusterVersion, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListClusterVersions")
+	}
+
+	var r0 []client.ClusterVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]client.ClusterVersion, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []client.ClusterVersion); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.ClusterVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClusterClient_ListClusterVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListClusterVersions'
+type ClusterClient_ListClusterVersions_Call struct {
+	*mock.Call
+}
+
+// ListClusterVersions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ClusterClient_Expecter) ListClusterVersions(ctx interface{}) *ClusterClient_ListClusterVersions_Call {
+	return &ClusterClient_ListClusterVersions_Call{Call: _e.mock.On("ListClusterVersions", ctx)}
+}
+
+func (_c *ClusterClient_ListClusterVersions_Call) Run(run func(ctx context.Context)) *ClusterClient_ListClusterVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_ListClusterVersions_Call) Return(_a0 []client.ClusterVersion, _a1 error) *ClusterClient_ListClusterVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClusterClient_ListClusterVersions_Call) RunAndReturn(run func(context.Context) ([]client.ClusterVersion, error)) *ClusterClient_ListClusterVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListClusters provides a mock function with given fields: ctx
 func (_m *ClusterClient) ListClusters(ctx context.Context) (client.ClusterList, error) {
 	ret := _m.Called(ctx)
@@ -412,6 +629,65 @@ func (_c *ClusterClient_ListClusters_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// ListNodePools provides a mock function with given fields: ctx, clusterID
+func (_m *ClusterClient) ListNodePools(ctx context.Context, clusterID string) (client.NodePools, error) {
+	ret := _m.Called(ctx, clusterID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNodePools")
+	}
+
+	var r0 client.NodePools
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (client.NodePools, error)); ok {
+		return rf(ctx, clusterID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) client.NodePools); ok {
+		r0 = rf(ctx, clusterID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.NodePools)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, clusterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClusterClient_ListNodePools_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNodePools'
+type ClusterClient_ListNodePools_Call struct {
+	*mock.Call
+}
+
+// ListNodePools is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+func (_e *ClusterClient_Expecter) ListNodePools(ctx interface{}, clusterID interface{}) *ClusterClient_ListNodePools_Call {
+	return &ClusterClient_ListNodePools_Call{Call: _e.mock.On("ListNodePools", ctx, clusterID)}
+}
+
+func (_c *ClusterClient_ListNodePools_Call) Run(run func(ctx context.Context, clusterID string)) *ClusterClient_ListNodePools_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_ListNodePools_Call) Return(_a0 client.NodePools, _a1 error) *ClusterClient_ListNodePools_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClusterClient_ListNodePools_Call) RunAndReturn(run func(context.Context, string) (client.NodePools, error)) *ClusterClient_ListNodePools_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RemoveClusterMember provides a mock function with given fields: ctx, clusterID, memberID
 func (_m *ClusterClient) RemoveClusterMember(ctx context.Context, clusterID string, memberID string) error {
 	ret := _m.Called(ctx, clusterID, memberID)
@@ -460,6 +736,126 @@ func (_c *ClusterClient_RemoveClusterMember_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// UpdateCluster provides a mock function with given fields: ctx, clusterID, request
+func (_m *ClusterClient) UpdateCluster(ctx context.Context, clusterID string, request client.UpdateClusterRequest) (*client.Cluster, error) {
+	ret := _m.Called(ctx, clusterID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCluster")
+	}
+
+	var r0 *client.Cluster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.UpdateClusterRequest) (*client.Cluster, error)); ok {
+		return rf(ctx, clusterID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.UpdateClusterRequest) *client.Cluster); ok {
+		r0 = rf(ctx, clusterID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.Cluster)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.UpdateClusterRequest) error); ok {
+		r1 = rf(ctx, clusterID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClusterClient_UpdateCluster_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCluster'
+type ClusterClient_UpdateCluster_Call struct {
+	*mock.Call
+}
+
+// UpdateCluster is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - request client.UpdateClusterRequest
+func (_e *ClusterClient_Expecter) UpdateCluster(ctx interface{}, clusterID interface{}, request interface{}) *ClusterClient_UpdateCluster_Call {
+	return &ClusterClient_UpdateCluster_Call{Call: _e.mock.On("UpdateCluster", ctx, clusterID, request)}
+}
+
+func (_c *ClusterClient_UpdateCluster_Call) Run(run func(ctx context.Context, clusterID string, request client.UpdateClusterRequest)) *ClusterClient_UpdateCluster_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.UpdateClusterRequest))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_UpdateCluster_Call) Return(_a0 *client.Cluster, _a1 error) *ClusterClient_UpdateCluster_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClusterClient_UpdateCluster_Call) RunAndReturn(run func(context.Context, string, client.UpdateClusterRequest) (*client.Cluster, error)) *ClusterClient_UpdateCluster_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNodePool provides a mock function with given fields: ctx, clusterID, pool
+func (_m *ClusterClient) UpdateNodePool(ctx context.Context, clusterID string, pool client.NodePool) (*client.NodePool, error) {
+	ret := _m.Called(ctx, clusterID, pool)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNodePool")
+	}
+
+	var r0 *client.NodePool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) (*client.NodePool, error)); ok {
+		return rf(ctx, clusterID, pool)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.NodePool) *client.NodePool); ok {
+		r0 = rf(ctx, clusterID, pool)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.NodePool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.NodePool) error); ok {
+		r1 = rf(ctx, clusterID, pool)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClusterClient_UpdateNodePool_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNodePool'
+type ClusterClient_UpdateNodePool_Call struct {
+	*mock.Call
+}
+
+// UpdateNodePool is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clusterID string
+//   - pool client.NodePool
+func (_e *ClusterClient_Expecter) UpdateNodePool(ctx interface{}, clusterID interface{}, pool interface{}) *ClusterClient_UpdateNodePool_Call {
+	return &ClusterClient_UpdateNodePool_Call{Call: _e.mock.On("UpdateNodePool", ctx, clusterID, pool)}
+}
+
+func (_c *ClusterClient_UpdateNodePool_Call) Run(run func(ctx context.Context, clusterID string, pool client.NodePool)) *ClusterClient_UpdateNodePool_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.NodePool))
+	})
+	return _c
+}
+
+func (_c *ClusterClient_UpdateNodePool_Call) Return(_a0 *client.NodePool, _a1 error) *ClusterClient_UpdateNodePool_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClusterClient_UpdateNodePool_Call) RunAndReturn(run func(context.Context, string, client.NodePool) (*client.NodePool, error)) *ClusterClient_UpdateNodePool_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewClusterClient creates a new instance of ClusterClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClusterClient(t interface {
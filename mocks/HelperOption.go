@@ -0,0 +1,68 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	credentialstore "github.com/intility/indev/pkg/credentialstore"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HelperOption is an autogenerated mock type for the HelperOption type
+type HelperOption struct {
+	mock.Mock
+}
+
+type HelperOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HelperOption) EXPECT() *HelperOption_Expecter {
+	return &HelperOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *HelperOption) Execute(_a0 *credentialstore.HelperCredentialStore) {
+	_m.Called(_a0)
+}
+
+// HelperOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type HelperOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *credentialstore.HelperCredentialStore
+func (_e *HelperOption_Expecter) Execute(_a0 interface{}) *HelperOption_Execute_Call {
+	return &HelperOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *HelperOption_Execute_Call) Run(run func(_a0 *credentialstore.HelperCredentialStore)) *HelperOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*credentialstore.HelperCredentialStore))
+	})
+	return _c
+}
+
+func (_c *HelperOption_Execute_Call) Return() *HelperOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *HelperOption_Execute_Call) RunAndReturn(run func(*credentialstore.HelperCredentialStore)) *HelperOption_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewHelperOption creates a new instance of HelperOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHelperOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HelperOption {
+	mock := &HelperOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,90 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// KeyFunc is an autogenerated mock type for the KeyFunc type
+type KeyFunc struct {
+	mock.Mock
+}
+
+type KeyFunc_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *KeyFunc) EXPECT() *KeyFunc_Expecter {
+	return &KeyFunc_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: salt
+func (_m *KeyFunc) Execute(salt []byte) ([]byte, error) {
+	ret := _m.Called(salt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]byte) ([]byte, error)); ok {
+		return rf(salt)
+	}
+	if rf, ok := ret.Get(0).(func([]byte) []byte); ok {
+		r0 = rf(salt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(salt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyFunc_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type KeyFunc_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - salt []byte
+func (_e *KeyFunc_Expecter) Execute(salt interface{}) *KeyFunc_Execute_Call {
+	return &KeyFunc_Execute_Call{Call: _e.mock.On("Execute", salt)}
+}
+
+func (_c *KeyFunc_Execute_Call) Run(run func(salt []byte)) *KeyFunc_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]byte))
+	})
+	return _c
+}
+
+func (_c *KeyFunc_Execute_Call) Return(_a0 []byte, _a1 error) *KeyFunc_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *KeyFunc_Execute_Call) RunAndReturn(run func([]byte) ([]byte, error)) *KeyFunc_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewKeyFunc creates a new instance of KeyFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyFunc {
+	mock := &KeyFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
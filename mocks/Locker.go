@@ -0,0 +1,90 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Locker is an autogenerated mock type for the Locker type
+type Locker struct {
+	mock.Mock
+}
+
+type Locker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Locker) EXPECT() *Locker_Expecter {
+	return &Locker_Expecter{mock: &_m.Mock}
+}
+
+// Lock provides a mock function with given fields: exclusive
+func (_m *Locker) Lock(exclusive bool) (func() error, error) {
+	ret := _m.Called(exclusive)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 func() error
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool) (func() error, error)); ok {
+		return rf(exclusive)
+	}
+	if rf, ok := ret.Get(0).(func(bool) func() error); ok {
+		r0 = rf(exclusive)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func() error)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(exclusive)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Locker_Lock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lock'
+type Locker_Lock_Call struct {
+	*mock.Call
+}
+
+// Lock is a helper method to define mock.On call
+//   - exclusive bool
+func (_e *Locker_Expecter) Lock(exclusive interface{}) *Locker_Lock_Call {
+	return &Locker_Lock_Call{Call: _e.mock.On("Lock", exclusive)}
+}
+
+func (_c *Locker_Lock_Call) Run(run func(exclusive bool)) *Locker_Lock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *Locker_Lock_Call) Return(_a0 func() error, _a1 error) *Locker_Lock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Locker_Lock_Call) RunAndReturn(run func(bool) (func() error, error)) *Locker_Lock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLocker creates a new instance of Locker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Locker {
+	mock := &Locker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,88 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Quarantiner is an autogenerated mock type for the Quarantiner type
+type Quarantiner struct {
+	mock.Mock
+}
+
+type Quarantiner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Quarantiner) EXPECT() *Quarantiner_Expecter {
+	return &Quarantiner_Expecter{mock: &_m.Mock}
+}
+
+// Quarantine provides a mock function with given fields: partitionKey
+func (_m *Quarantiner) Quarantine(partitionKey string) (string, error) {
+	ret := _m.Called(partitionKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Quarantine")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(partitionKey)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(partitionKey)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(partitionKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Quarantiner_Quarantine_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Quarantine'
+type Quarantiner_Quarantine_Call struct {
+	*mock.Call
+}
+
+// Quarantine is a helper method to define mock.On call
+//   - partitionKey string
+func (_e *Quarantiner_Expecter) Quarantine(partitionKey interface{}) *Quarantiner_Quarantine_Call {
+	return &Quarantiner_Quarantine_Call{Call: _e.mock.On("Quarantine", partitionKey)}
+}
+
+func (_c *Quarantiner_Quarantine_Call) Run(run func(partitionKey string)) *Quarantiner_Quarantine_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Quarantiner_Quarantine_Call) Return(_a0 string, _a1 error) *Quarantiner_Quarantine_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Quarantiner_Quarantine_Call) RunAndReturn(run func(string) (string, error)) *Quarantiner_Quarantine_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQuarantiner creates a new instance of Quarantiner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQuarantiner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Quarantiner {
+	mock := &Quarantiner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	client "github.com/intility/indev/pkg/client"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -247,6 +249,55 @@ func (_c *TeamsClient_GetTeamMembers_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// IterTeams provides a mock function with given fields: ctx, opts
+func (_m *TeamsClient) IterTeams(ctx context.Context, opts client.ListOptions) iter.Seq2[client.Team, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterTeams")
+	}
+
+	var r0 iter.Seq2[client.Team, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.Team, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.Team, error])
+		}
+	}
+
+	return r0
+}
+
+// TeamsClient_IterTeams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterTeams'
+type TeamsClient_IterTeams_Call struct {
+	*mock.Call
+}
+
+// IterTeams is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *TeamsClient_Expecter) IterTeams(ctx interface{}, opts interface{}) *TeamsClient_IterTeams_Call {
+	return &TeamsClient_IterTeams_Call{Call: _e.mock.On("IterTeams", ctx, opts)}
+}
+
+func (_c *TeamsClient_IterTeams_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *TeamsClient_IterTeams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *TeamsClient_IterTeams_Call) Return(_a0 iter.Seq2[client.Team, error]) *TeamsClient_IterTeams_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TeamsClient_IterTeams_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.Team, error]) *TeamsClient_IterTeams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListTeams provides a mock function with given fields: ctx
 func (_m *TeamsClient) ListTeams(ctx context.Context) ([]client.Team, error) {
 	ret := _m.Called(ctx)
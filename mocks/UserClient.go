@@ -7,6 +7,8 @@ import (
 
 	client "github.com/intility/indev/pkg/client"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -82,6 +84,55 @@ func (_c *UserClient_GetUser_Call) RunAndReturn(run func(context.Context, string
 	return _c
 }
 
+// IterUsers provides a mock function with given fields: ctx, opts
+func (_m *UserClient) IterUsers(ctx context.Context, opts client.ListOptions) iter.Seq2[client.User, error] {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterUsers")
+	}
+
+	var r0 iter.Seq2[client.User, error]
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListOptions) iter.Seq2[client.User, error]); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[client.User, error])
+		}
+	}
+
+	return r0
+}
+
+// UserClient_IterUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterUsers'
+type UserClient_IterUsers_Call struct {
+	*mock.Call
+}
+
+// IterUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts client.ListOptions
+func (_e *UserClient_Expecter) IterUsers(ctx interface{}, opts interface{}) *UserClient_IterUsers_Call {
+	return &UserClient_IterUsers_Call{Call: _e.mock.On("IterUsers", ctx, opts)}
+}
+
+func (_c *UserClient_IterUsers_Call) Run(run func(ctx context.Context, opts client.ListOptions)) *UserClient_IterUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(client.ListOptions))
+	})
+	return _c
+}
+
+func (_c *UserClient_IterUsers_Call) Return(_a0 iter.Seq2[client.User, error]) *UserClient_IterUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserClient_IterUsers_Call) RunAndReturn(run func(context.Context, client.ListOptions) iter.Seq2[client.User, error]) *UserClient_IterUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListUsers provides a mock function with given fields: ctx
 func (_m *UserClient) ListUsers(ctx context.Context) ([]client.User, error) {
 	ret := _m.Called(ctx)
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
@@ -22,6 +25,16 @@ var (
 	ErrNoAccounts       = errors.New("no accounts found")
 	ErrTokenExpired     = errors.New("token has expired")
 	ErrDeclinedScopes   = errors.New("scopes have been declined")
+	ErrAccountNotFound  = errors.New("account is not signed in")
+	// ErrReauthenticationRequired is returned when the platform asks for a
+	// new sign-in, such as after a conditional access policy changed, and
+	// the user cannot be asked to sign in.
+	ErrReauthenticationRequired = errors.New("re-authentication required")
+	// ErrScopesNotSupported is returned when a token for other scopes than
+	// the platform is requested from a credential that cannot acquire one,
+	// such as an access token passed in the environment.
+	ErrScopesNotSupported = errors.New("cannot acquire tokens for other scopes")
+	ErrTokenTooShortLived = errors.New("token expires sooner than requested")
 )
 
 type Config struct {
@@ -48,6 +61,16 @@ type Authenticator struct {
 	cache       cache.ExportReplace
 	flow        Flow
 	printer     Printer
+	httpClient  *http.Client
+	// account and tenant select one of the signed-in accounts
+	account string
+	tenant  string
+	// noInteraction is set when the user cannot be asked to sign in, such
+	// as in scripts
+	noInteraction bool
+
+	clientMu     sync.Mutex
+	publicClient *public.Client
 }
 
 type Flow int
@@ -55,6 +78,9 @@ type Flow int
 const (
 	FlowInteractive Flow = iota
 	FlowDeviceCode
+	// FlowAutomatic picks the interactive flow when a browser can be opened,
+	// and the device code flow otherwise.
+	FlowAutomatic
 )
 
 type Option func(authenticator *Authenticator)
@@ -62,22 +88,35 @@ type Option func(authenticator *Authenticator)
 // NewAuthenticator creates a new authenticator with the given configuration.
 func NewAuthenticator(config Config, options ...Option) *Authenticator {
 	authenticator := &Authenticator{
-		clientID:    config.ClientID,
-		authority:   config.Authority,
-		scopes:      config.Scopes,
-		redirectURI: config.RedirectURI,
-		cache:       tokencache.New(),
-		flow:        FlowInteractive,
-		printer:     nil,
+		clientID:      config.ClientID,
+		authority:     config.Authority,
+		scopes:        config.Scopes,
+		redirectURI:   config.RedirectURI,
+		cache:         tokencache.New(),
+		flow:          FlowInteractive,
+		printer:       nil,
+		httpClient:    nil,
+		account:       "",
+		tenant:        "",
+		noInteraction: false,
+		clientMu:      sync.Mutex{},
+		publicClient:  nil,
 	}
 
-	for _, opt := range options {
-		opt(authenticator)
-	}
+	authenticator.Configure(options...)
 
 	return authenticator
 }
 
+// Configure applies options to an existing authenticator, such as the
+// account selected with a flag. It must be called before the authenticator
+// is used concurrently.
+func (a *Authenticator) Configure(options ...Option) {
+	for _, opt := range options {
+		opt(a)
+	}
+}
+
 // WithTokenCache configures the authenticator to use a custom token cache.
 //
 //goland:noinspection GoUnusedExportedFunction
@@ -97,6 +136,41 @@ func WithDeviceCodeFlow(printer Printer) Option {
 	}
 }
 
+// WithAccount selects the signed-in account with the given username or home
+// account ID, instead of the first one. Signing in with the interactive flow
+// suggests the username to the user.
+func WithAccount(username string) Option {
+	return func(auth *Authenticator) {
+		auth.account = username
+	}
+}
+
+// WithTenant selects a signed-in account of the given tenant, and signs in to
+// that tenant if there is none. The tenant is an ID or a domain name.
+func WithTenant(tenant string) Option {
+	return func(auth *Authenticator) {
+		auth.tenant = tenant
+	}
+}
+
+// WithInteraction sets whether the user may be asked to sign in when the
+// platform sends a claims challenge. Without interaction, the challenge
+// fails with ErrReauthenticationRequired unless the refresh token satisfies
+// it.
+func WithInteraction(allowed bool) Option {
+	return func(auth *Authenticator) {
+		auth.noInteraction = !allowed
+	}
+}
+
+// WithHTTPClient configures the authenticator to send its requests to Entra
+// ID with the given client, for instance to go through a proxy.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(auth *Authenticator) {
+		auth.httpClient = httpClient
+	}
+}
+
 // Authenticate initiates the authentication flow. If the user is already authenticated,
 // the cached token is used. If the user is not authenticated, the user is prompted to
 // authenticate using the configured flow.
@@ -115,16 +189,21 @@ func (a *Authenticator) Authenticate(ctx context.Context) (public.AuthResult, er
 	}
 
 	accounts, err := getCachedAccounts(publicClient, ctx)
-	if len(accounts) > 0 {
-		result, err = a.acquireTokenSilent(ctx, publicClient, accounts[0])
+	if err == nil {
+		var account public.Account
+
+		if account, err = FindAccount(accounts, a.account, a.tenant); err == nil {
+			result, err = a.acquireTokenSilent(ctx, publicClient, account)
+		}
 	}
 
-	if err != nil || len(accounts) == 0 {
+	if err != nil {
 		result, err = a.authenticateWithFlow(
 			ctx,
 			publicClient,
 			a.flow,
 			a.scopes,
+			"",
 		)
 		if err != nil {
 			span.RecordError(err)
@@ -135,12 +214,38 @@ func (a *Authenticator) Authenticate(ctx context.Context) (public.AuthResult, er
 	return result, nil
 }
 
+// createPublicClient returns the MSAL client of the authenticator. The client
+// is created on first use and reused afterwards.
 func (a *Authenticator) createPublicClient(_ context.Context) (public.Client, error) {
-	client, err := public.New(
-		a.clientID,
+	a.clientMu.Lock()
+	defer a.clientMu.Unlock()
+
+	if a.publicClient != nil {
+		return *a.publicClient, nil
+	}
+
+	client, err := a.newPublicClient(a.cache)
+	if err != nil {
+		return client, err
+	}
+
+	a.publicClient = &client
+
+	return client, nil
+}
+
+// newPublicClient creates an MSAL client that keeps its tokens in tokenCache.
+func (a *Authenticator) newPublicClient(tokenCache cache.ExportReplace) (public.Client, error) {
+	options := []public.Option{
 		public.WithAuthority(a.authority),
-		public.WithCache(a.cache),
-	)
+		public.WithCache(tokenCache),
+	}
+
+	if a.httpClient != nil {
+		options = append(options, public.WithHTTPClient(a.httpClient))
+	}
+
+	client, err := public.New(a.clientID, options...)
 	if err != nil {
 		return client, fmt.Errorf("could not create public client: %w", err)
 	}
@@ -153,18 +258,51 @@ func (a *Authenticator) authenticateWithFlow(
 	publicClient public.Client,
 	flow Flow,
 	scopes []string,
+	claims string,
 ) (public.AuthResult, error) {
 	var (
 		result public.AuthResult
 		err    error
 	)
 
+	redirectURI := a.redirectURI
+
+	if flow == FlowAutomatic {
+		var reason string
+
+		flow, redirectURI, reason = a.chooseFlow()
+		if flow == FlowDeviceCode {
+			if err = a.announceDeviceCode(ctx, reason); err != nil {
+				return result, redact.Errorf("could not print device code message: %w", redact.Safe(err))
+			}
+		}
+	}
+
 	switch flow {
 	case FlowInteractive:
 		ctx, span := telemetry.StartSpan(ctx, "InteractiveAcquisition")
 		defer span.End()
 
-		result, err = publicClient.AcquireTokenInteractive(ctx, scopes, public.WithRedirectURI(a.redirectURI))
+		// another process may hold the port of the redirect URI, such as a
+		// second login, in which case a random free port is used
+		if uri, err := loopbackRedirect(redirectURI); err == nil {
+			redirectURI = uri
+		}
+
+		options := []public.AcquireInteractiveOption{public.WithRedirectURI(redirectURI)}
+		if a.account != "" {
+			options = append(options, public.WithLoginHint(a.account))
+		}
+
+		if a.tenant != "" {
+			options = append(options, public.WithTenantID(a.tenant))
+		}
+
+		if claims != "" {
+			options = append(options, public.WithClaims(claims))
+		}
+
+		result, err = publicClient.AcquireTokenInteractive(ctx, scopes, options...)
 	case FlowDeviceCode:
 		ctx, span := telemetry.StartSpan(ctx, "DeviceCodeAcquisition")
 		defer span.End()
@@ -173,7 +311,16 @@ func (a *Authenticator) authenticateWithFlow(
 
 		span.AddEvent("AcquireDeviceCode")
 
-		code, err = publicClient.AcquireTokenByDeviceCode(ctx, scopes)
+		var options []public.AcquireByDeviceCodeOption
+		if a.tenant != "" {
+			options = append(options, public.WithTenantID(a.tenant))
+		}
+
+		if claims != "" {
+			options = append(options, public.WithClaims(claims))
+		}
+
+		code, err = publicClient.AcquireTokenByDeviceCode(ctx, scopes, options...)
 		if err != nil {
 			return result, fmt.Errorf("could not acquire device code: %w", err)
 		}
@@ -208,36 +355,151 @@ func (a *Authenticator) IsAuthenticated(ctx context.Context) (bool, error) {
 	ctx, span := telemetry.StartSpan(ctx, "IsAuthenticated")
 	defer span.End()
 
+	if _, err := a.AuthenticateSilent(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AuthenticateSilent acquires a token for the cached account without user
+// interaction. It fails if there is no cached account, or if the account
+// cannot be refreshed silently.
+func (a *Authenticator) AuthenticateSilent(ctx context.Context) (public.AuthResult, error) {
+	var result public.AuthResult
+
 	publicClient, err := a.createPublicClient(ctx)
 	if err != nil {
-		return false, err
+		return result, err
 	}
 
 	accounts, err := getCachedAccounts(publicClient, ctx)
 	if err != nil {
-		return false, err
+		return result, err
 	}
 
-	if len(accounts) == 0 {
-		return false, ErrNoAccounts
+	account, err := FindAccount(accounts, a.account, a.tenant)
+	if err != nil {
+		return result, err
 	}
 
-	result, err := a.acquireTokenSilent(ctx, publicClient, accounts[0])
+	result, err = a.acquireTokenSilent(ctx, publicClient, account)
 	if err != nil {
-		return false, fmt.Errorf("could not acquire token: %w", err)
+		return result, fmt.Errorf("could not acquire token: %w", err)
 	}
 
 	if result.ExpiresOn.Before(time.Now()) {
-		return false, ErrTokenExpired
+		return result, ErrTokenExpired
 	}
 
 	for _, s := range a.scopes {
 		if slices.Contains(result.DeclinedScopes, s) {
-			return false, ErrDeclinedScopes
+			return result, ErrDeclinedScopes
 		}
 	}
 
-	return true, nil
+	return result, nil
+}
+
+// AuthenticateWithClaims acquires a token that satisfies a claims challenge
+// of the platform, as sent when a conditional access policy changed or a
+// token was revoked. The refresh token of the account is tried first, and
+// when it does not satisfy the claims, the user signs in again with the
+// configured flow, unless interaction is disabled.
+func (a *Authenticator) AuthenticateWithClaims(ctx context.Context, claims string) (public.AuthResult, error) {
+	ctx, span := telemetry.StartSpan(ctx, "AuthenticateWithClaims")
+	defer span.End()
+
+	var result public.AuthResult
+
+	publicClient, err := a.createPublicClient(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	accounts, err := getCachedAccounts(publicClient, ctx)
+	if err == nil {
+		var account public.Account
+
+		if account, err = FindAccount(accounts, a.account, a.tenant); err == nil {
+			result, err = publicClient.AcquireTokenSilent(
+				ctx,
+				a.scopes,
+				public.WithSilentAccount(account),
+				public.WithTenantID(account.Realm),
+				public.WithClaims(claims))
+			if err == nil {
+				return result, nil
+			}
+		}
+	}
+
+	span.RecordError(err)
+
+	if a.noInteraction {
+		return result, fmt.Errorf("%w: the platform requires you to sign in again, "+
+			"run the command in a terminal to do so", ErrReauthenticationRequired)
+	}
+
+	result, err = a.authenticateWithFlow(ctx, publicClient, a.flow, a.scopes, claims)
+	if err != nil {
+		return result, fmt.Errorf("could not acquire token: %w", err)
+	}
+
+	return result, nil
+}
+
+// AuthenticateFor acquires a token for the cached account without user
+// interaction, for the given scopes or the platform if there are none. When
+// refresh is set, the cached access tokens are hidden from MSAL, so that a
+// new one is acquired with the refresh token of the account. The other
+// cached access tokens are kept.
+func (a *Authenticator) AuthenticateFor(ctx context.Context, scopes []string, refresh bool) (public.AuthResult, error) {
+	ctx, span := telemetry.StartSpan(ctx, "AuthenticateFor")
+	defer span.End()
+
+	var result public.AuthResult
+
+	if len(scopes) == 0 {
+		scopes = a.scopes
+	}
+
+	publicClient, err := a.createPublicClient(ctx)
+	if refresh && err == nil {
+		publicClient, err = a.newPublicClient(newWithoutAccessTokens(a.cache))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	accounts, err := getCachedAccounts(publicClient, ctx)
+	if err != nil {
+		return result, err
+	}
+
+	account, err := FindAccount(accounts, a.account, a.tenant)
+	if err != nil {
+		return result, err
+	}
+
+	result, err = publicClient.AcquireTokenSilent(ctx, scopes,
+		public.WithSilentAccount(account),
+		public.WithTenantID(account.Realm),
+	)
+	if err != nil {
+		span.RecordError(err)
+		return result, redact.Errorf("could not acquire token for %s: %w",
+			strings.Join(scopes, " "), redact.Safe(err))
+	}
+
+	return result, nil
+}
+
+// Source describes the tokens of the authenticator, which come from the
+// account that the user signed in to.
+func (a *Authenticator) Source() string {
+	return "interactive sign-in"
 }
 
 func (a *Authenticator) GetCurrentAccount(ctx context.Context) (public.Account, error) {
@@ -254,11 +516,66 @@ func (a *Authenticator) GetCurrentAccount(ctx context.Context) (public.Account,
 		return public.Account{}, err
 	}
 
+	return FindAccount(accounts, a.account, a.tenant)
+}
+
+// Accounts returns the accounts that are signed in.
+func (a *Authenticator) Accounts(ctx context.Context) ([]public.Account, error) {
+	publicClient, err := a.createPublicClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return getCachedAccounts(publicClient, ctx)
+}
+
+// RemoveAccount signs the account out by removing its tokens from the token
+// cache. The tokens of the same user in other tenants are removed too, as
+// they share the refresh token.
+func (a *Authenticator) RemoveAccount(ctx context.Context, account public.Account) error {
+	ctx, span := telemetry.StartSpan(ctx, "RemoveAccount")
+	defer span.End()
+
+	publicClient, err := a.createPublicClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = publicClient.RemoveAccount(ctx, account); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("could not remove account %s: %w", account.PreferredUsername, err)
+	}
+
+	return nil
+}
+
+// FindAccount returns the first of the accounts with the given username or
+// home account ID, in the given tenant. An empty username or tenant matches
+// any account.
+func FindAccount(accounts []public.Account, username, tenant string) (public.Account, error) {
 	if len(accounts) == 0 {
 		return public.Account{}, ErrNoAccounts
 	}
 
-	return accounts[0], nil
+	for _, account := range accounts {
+		if username != "" && !strings.EqualFold(account.PreferredUsername, username) &&
+			account.HomeAccountID != username {
+			continue
+		}
+
+		if tenant != "" && !strings.EqualFold(account.Realm, tenant) {
+			continue
+		}
+
+		return account, nil
+	}
+
+	selection := username
+	if tenant != "" {
+		selection = strings.TrimSpace(username + " in tenant " + tenant)
+	}
+
+	return public.Account{}, fmt.Errorf("%w: %s", ErrAccountNotFound, selection)
 }
 
 func (a *Authenticator) acquireTokenSilent(
@@ -269,10 +586,13 @@ func (a *Authenticator) acquireTokenSilent(
 	ctx, span := telemetry.StartSpan(ctx, "SilentAcquisition")
 	defer span.End()
 
+	// the token is refreshed in the tenant of the account, which may not be
+	// the home tenant of the user
 	result, err := client.AcquireTokenSilent(
 		ctx,
 		a.scopes,
-		public.WithSilentAccount(account))
+		public.WithSilentAccount(account),
+		public.WithTenantID(account.Realm))
 	if err != nil {
 		span.RecordError(err)
 		return result, fmt.Errorf("could not acquire token silently: %w", err)
@@ -0,0 +1,49 @@
+package authenticator
+
+import (
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAccount(t *testing.T) {
+	ada := public.Account{HomeAccountID: "ada.home", Realm: "home", PreferredUsername: "ada@example.com"}
+	adaGuest := public.Account{HomeAccountID: "ada.home", Realm: "customer", PreferredUsername: "ada@example.com"}
+	alan := public.Account{HomeAccountID: "alan.home", Realm: "home", PreferredUsername: "alan@example.com"}
+	accounts := []public.Account{ada, adaGuest, alan}
+
+	tests := []struct {
+		name     string
+		username string
+		tenant   string
+		want     public.Account
+		wantErr  error
+	}{
+		{name: "first account by default", want: ada},
+		{name: "by username", username: "ALAN@example.com", want: alan},
+		{name: "by home account ID", username: "alan.home", want: alan},
+		{name: "by username and tenant", username: "ada@example.com", tenant: "customer", want: adaGuest},
+		{name: "by tenant", tenant: "customer", want: adaGuest},
+		{name: "unknown username", username: "grace@example.com", wantErr: ErrAccountNotFound},
+		{name: "unknown tenant", username: "alan@example.com", tenant: "customer", wantErr: ErrAccountNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := FindAccount(accounts, tt.username, tt.tenant)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, account)
+		})
+	}
+
+	_, err := FindAccount(nil, "", "")
+	require.ErrorIs(t, err, ErrNoAccounts)
+}
@@ -0,0 +1,222 @@
+package authenticator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+
+	"github.com/intility/indev/internal/build"
+	"github.com/intility/indev/internal/telemetry"
+)
+
+// azureCLIProgram is the name of the Azure CLI executable.
+const azureCLIProgram = "az"
+
+// defaultAzureCLITimeout is how long the Azure CLI may take to hand out a
+// token, which includes refreshing it.
+const defaultAzureCLITimeout = 30 * time.Second
+
+var (
+	ErrAzureCLINotFound    = errors.New("the Azure CLI (az) is not installed or not in PATH")
+	ErrAzureCLINotSignedIn = errors.New("the Azure CLI is not signed in, run `az login`")
+	ErrAzureCLIWrongTenant = errors.New("the Azure CLI is signed in to another tenant")
+	ErrAzureCLIFailed      = errors.New("the Azure CLI could not get an access token")
+)
+
+// AzureCLIConfig configures how tokens are acquired from the Azure CLI.
+type AzureCLIConfig struct {
+	// Tenant is the tenant that the Azure CLI must hand out tokens for, or
+	// empty for the tenant of its current subscription.
+	Tenant string
+	// Scopes default to the scopes of the platform audience.
+	Scopes []string
+	// Timeout defaults to 30 seconds.
+	Timeout time.Duration
+}
+
+// AzureCLI acquires tokens for the account that is signed in with `az login`,
+// by running `az account get-access-token`, so that users of the Azure CLI do
+// not have to sign in to indev as well. The Azure CLI keeps and refreshes the
+// tokens itself.
+type AzureCLI struct {
+	config AzureCLIConfig
+}
+
+// azureCLIToken is the output of `az account get-access-token -o json`.
+type azureCLIToken struct {
+	AccessToken string `json:"accessToken"`
+	// ExpiresOn is the expiry in seconds since the epoch. Older versions of
+	// the Azure CLI only print ExpiresOnLocal.
+	ExpiresOn      int64  `json:"expires_on"`
+	ExpiresOnLocal string `json:"expiresOn"`
+	Tenant         string `json:"tenant"`
+}
+
+// NewAzureCLI creates a token acquirer for the account of the Azure CLI.
+func NewAzureCLI(config AzureCLIConfig) *AzureCLI {
+	if len(config.Scopes) == 0 {
+		config.Scopes = build.Scopes()
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultAzureCLITimeout
+	}
+
+	return &AzureCLI{config: config}
+}
+
+func (a *AzureCLI) Authenticate(ctx context.Context) (public.AuthResult, error) {
+	return a.AuthenticateFor(ctx, nil, false)
+}
+
+func (a *AzureCLI) AuthenticateSilent(ctx context.Context) (public.AuthResult, error) {
+	return a.Authenticate(ctx)
+}
+
+// AuthenticateFor acquires a token for the resource of the given scopes, or
+// of the platform if there are none. The Azure CLI hands out its cached
+// token even when refresh is set, as it cannot be told otherwise.
+func (a *AzureCLI) AuthenticateFor(ctx context.Context, scopes []string, _ bool) (public.AuthResult, error) {
+	ctx, span := telemetry.StartSpan(ctx, "AzureCLI.Authenticate")
+	defer span.End()
+
+	if len(scopes) == 0 {
+		scopes = a.config.Scopes
+	}
+
+	output, err := a.run(ctx, azureCLIResource(scopes))
+	if err != nil {
+		span.RecordError(err)
+
+		return public.AuthResult{}, err
+	}
+
+	return a.result(output)
+}
+
+func (a *AzureCLI) Source() string {
+	return "Azure CLI"
+}
+
+func (a *AzureCLI) GetCurrentAccount(ctx context.Context) (public.Account, error) {
+	result, err := a.Authenticate(ctx)
+	if err != nil {
+		return public.Account{}, err
+	}
+
+	return result.Account, nil
+}
+
+func (a *AzureCLI) run(ctx context.Context, resource string) ([]byte, error) {
+	path, err := exec.LookPath(azureCLIProgram)
+	if err != nil {
+		return nil, ErrAzureCLINotFound
+	}
+
+	args := []string{"account", "get-access-token", "--resource", resource, "-o", "json"}
+	if a.config.Tenant != "" {
+		args = append(args, "--tenant", a.config.Tenant)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w: az did not answer within %s", ErrAzureCLIFailed, a.config.Timeout)
+	}
+
+	if err != nil {
+		return nil, a.failure(strings.TrimSpace(stderr.String()), err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// failure explains why the Azure CLI could not hand out a token, from the
+// error message that it printed.
+func (a *AzureCLI) failure(message string, err error) error {
+	switch {
+	case strings.Contains(message, "az login"):
+		if a.config.Tenant != "" {
+			return fmt.Errorf("%w, run `az login --tenant %s`", ErrAzureCLINotSignedIn, a.config.Tenant)
+		}
+
+		return ErrAzureCLINotSignedIn
+	case strings.Contains(message, "AADSTS90002"), strings.Contains(message, "AADSTS50020"):
+		return fmt.Errorf("%w: %s", ErrAzureCLIWrongTenant, message)
+	case message == "":
+		return fmt.Errorf("%w: %w", ErrAzureCLIFailed, err)
+	default:
+		return fmt.Errorf("%w: %s", ErrAzureCLIFailed, message)
+	}
+}
+
+// result converts the output of the Azure CLI into a token of the account
+// that is named in its claims.
+func (a *AzureCLI) result(output []byte) (public.AuthResult, error) {
+	var token azureCLIToken
+	if err := json.Unmarshal(output, &token); err != nil || token.AccessToken == "" {
+		return public.AuthResult{}, fmt.Errorf("%w: unexpected output of az account get-access-token", ErrAzureCLIFailed)
+	}
+
+	result := public.AuthResult{
+		AccessToken: token.AccessToken,
+		ExpiresOn:   token.expiry(),
+	}
+
+	if claims, ok := parseClaims(token.AccessToken); ok {
+		result.Account = claims.account()
+	}
+
+	tenant := result.Account.Realm
+	if tenant == "" {
+		tenant = token.Tenant
+		result.Account.Realm = tenant
+	}
+
+	// tenants given by domain name, such as contoso.onmicrosoft.com, are
+	// checked by the Azure CLI only
+	if a.config.Tenant != "" && !strings.Contains(a.config.Tenant, ".") && !strings.EqualFold(tenant, a.config.Tenant) {
+		return public.AuthResult{}, fmt.Errorf("%w: it hands out tokens for tenant %s instead of %s, run `az login --tenant %s`",
+			ErrAzureCLIWrongTenant, tenant, a.config.Tenant, a.config.Tenant)
+	}
+
+	return result, nil
+}
+
+func (t azureCLIToken) expiry() time.Time {
+	if t.ExpiresOn > 0 {
+		return time.Unix(t.ExpiresOn, 0)
+	}
+
+	// older versions print the local time without a zone
+	expires, err := time.ParseInLocation("2006-01-02 15:04:05.999999", t.ExpiresOnLocal, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return expires
+}
+
+// azureCLIResource returns the resource of the first scope, such as
+// "api://app" for "api://app/user_impersonation", as the Azure CLI asks for
+// tokens by resource.
+func azureCLIResource(scopes []string) string {
+	return strings.TrimSuffix(DefaultScopes(scopes[:1])[0], "/.default")
+}
@@ -0,0 +1,141 @@
+package authenticator
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAzureCLI is an az script that records its arguments and prints the
+// given output, on stdout when it succeeds and on stderr when it fails.
+const fakeAzureCLI = `#!/bin/sh
+echo "$@" > "$FAKE_AZ_DIR/args"
+if [ -f "$FAKE_AZ_DIR/stderr" ]; then
+	cat "$FAKE_AZ_DIR/stderr" >&2
+	exit 1
+fi
+cat "$FAKE_AZ_DIR/stdout"
+`
+
+// installFakeAzureCLI puts the fake az script in PATH, and returns the
+// directory with its output.
+func installFakeAzureCLI(t *testing.T, stdout, stderr string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake Azure CLI is a shell script")
+	}
+
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "az"), []byte(fakeAzureCLI), 0o700)) //nolint:gosec // the script must be executable
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stdout"), []byte(stdout), 0o600))
+
+	if stderr != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "stderr"), []byte(stderr), 0o600))
+	}
+
+	// the script needs the shell tools in PATH
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_AZ_DIR", dir)
+
+	return dir
+}
+
+func azureCLIOutput(token string, expires time.Time, tenant string) string {
+	return `{
+  "accessToken": "` + token + `",
+  "expiresOn": "` + expires.Local().Format("2006-01-02 15:04:05.000000") + `",
+  "expires_on": ` + strconv.FormatInt(expires.Unix(), 10) + `,
+  "subscription": "00000000-0000-0000-0000-000000000001",
+  "tenant": "` + tenant + `",
+  "tokenType": "Bearer"
+}`
+}
+
+func TestAzureCLI(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	token := testJWT(t, map[string]any{"oid": "object", "tid": "tenant", "upn": "ada@example.com"})
+
+	t.Run("hands out the token of the Azure CLI", func(t *testing.T) {
+		dir := installFakeAzureCLI(t, azureCLIOutput(token, expires, "tenant"), "")
+
+		result, err := NewAzureCLI(AzureCLIConfig{Scopes: []string{"api://platform/user_impersonation"}}).
+			Authenticate(testContext())
+		require.NoError(t, err)
+
+		assert.Equal(t, token, result.AccessToken)
+		assert.Equal(t, expires, result.ExpiresOn)
+		assert.Equal(t, "tenant", result.Account.Realm)
+		assert.Equal(t, "ada@example.com", result.Account.PreferredUsername)
+		assert.Equal(t, "object.tenant", result.Account.HomeAccountID)
+
+		args, err := os.ReadFile(filepath.Join(dir, "args"))
+		require.NoError(t, err)
+		assert.Equal(t, "account get-access-token --resource api://platform -o json\n", string(args))
+	})
+
+	t.Run("asks for the configured tenant", func(t *testing.T) {
+		dir := installFakeAzureCLI(t, azureCLIOutput(token, expires, "tenant"), "")
+
+		_, err := NewAzureCLI(AzureCLIConfig{Tenant: "tenant"}).AuthenticateFor(testContext(),
+			[]string{"https://graph.microsoft.com/.default"}, false)
+		require.NoError(t, err)
+
+		args, err := os.ReadFile(filepath.Join(dir, "args"))
+		require.NoError(t, err)
+		assert.Contains(t, string(args), "--resource https://graph.microsoft.com -o json --tenant tenant")
+	})
+
+	t.Run("tokens of another tenant are rejected", func(t *testing.T) {
+		installFakeAzureCLI(t, azureCLIOutput(token, expires, "tenant"), "")
+
+		_, err := NewAzureCLI(AzureCLIConfig{Tenant: "9b5ff18e-53c0-45a2-8bc2-9c0c8f60b2c6"}).Authenticate(testContext())
+		require.ErrorIs(t, err, ErrAzureCLIWrongTenant)
+		assert.ErrorContains(t, err, "az login --tenant 9b5ff18e-53c0-45a2-8bc2-9c0c8f60b2c6")
+	})
+
+	t.Run("not signed in", func(t *testing.T) {
+		installFakeAzureCLI(t, "", "ERROR: Please run 'az login' to setup account.")
+
+		account, err := NewAzureCLI(AzureCLIConfig{}).GetCurrentAccount(testContext())
+		require.ErrorIs(t, err, ErrAzureCLINotSignedIn)
+		assert.True(t, account.IsZero())
+	})
+
+	t.Run("other failures carry the message of the Azure CLI", func(t *testing.T) {
+		installFakeAzureCLI(t, "", "ERROR: AADSTS65001: The user or administrator has not consented.")
+
+		_, err := NewAzureCLI(AzureCLIConfig{}).Authenticate(testContext())
+		require.ErrorIs(t, err, ErrAzureCLIFailed)
+		assert.ErrorContains(t, err, "AADSTS65001")
+	})
+
+	t.Run("unexpected output", func(t *testing.T) {
+		installFakeAzureCLI(t, "not json", "")
+
+		_, err := NewAzureCLI(AzureCLIConfig{}).Authenticate(testContext())
+		require.ErrorIs(t, err, ErrAzureCLIFailed)
+	})
+
+	t.Run("missing Azure CLI", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+
+		_, err := NewAzureCLI(AzureCLIConfig{}).Authenticate(testContext())
+		require.ErrorIs(t, err, ErrAzureCLINotFound)
+	})
+}
+
+func TestAzureCLITokenExpiry(t *testing.T) {
+	local := time.Date(2026, 10, 16, 12, 30, 0, 0, time.Local)
+
+	assert.Equal(t, local, azureCLIToken{ExpiresOnLocal: "2026-10-16 12:30:00.000000"}.expiry())
+	assert.Equal(t, time.Unix(1760000000, 0), azureCLIToken{ExpiresOn: 1760000000, ExpiresOnLocal: "invalid"}.expiry())
+}
@@ -0,0 +1,124 @@
+package authenticator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+const jwtParts = 3
+
+var ErrUnknownTokenAccount = errors.New("the account of the access token is unknown")
+
+// BearerToken hands out an access token that was acquired elsewhere, such as
+// one passed to a pipeline. The account and expiry are read from the claims
+// of the token, without verifying its signature, which is left to the
+// platform.
+type BearerToken struct {
+	token   string
+	account public.Account
+	expires time.Time
+}
+
+// NewBearerToken creates a BearerToken for the given access token.
+func NewBearerToken(token string) *BearerToken {
+	bearer := &BearerToken{
+		token:   token,
+		account: public.Account{},
+		expires: time.Time{},
+	}
+
+	if claims, ok := parseClaims(token); ok {
+		bearer.account = claims.account()
+		if claims.Expires > 0 {
+			bearer.expires = time.Unix(claims.Expires, 0)
+		}
+	}
+
+	return bearer
+}
+
+func (b *BearerToken) Authenticate(context.Context) (public.AuthResult, error) {
+	if !b.expires.IsZero() && time.Now().After(b.expires) {
+		return public.AuthResult{}, ErrTokenExpired
+	}
+
+	return public.AuthResult{
+		Account:     b.account,
+		AccessToken: b.token,
+		ExpiresOn:   b.expires,
+	}, nil
+}
+
+func (b *BearerToken) AuthenticateSilent(ctx context.Context) (public.AuthResult, error) {
+	return b.Authenticate(ctx)
+}
+
+func (b *BearerToken) GetCurrentAccount(context.Context) (public.Account, error) {
+	if b.account.IsZero() {
+		return public.Account{}, ErrUnknownTokenAccount
+	}
+
+	return b.account, nil
+}
+
+func (b *BearerToken) Source() string {
+	return "access token"
+}
+
+// claims are the claims of an Entra ID access token that identify its
+// account.
+type claims struct {
+	ObjectID          string `json:"oid"`
+	TenantID          string `json:"tid"`
+	UPN               string `json:"upn"`
+	PreferredUsername string `json:"preferred_username"`
+	AppID             string `json:"appid"`
+	Expires           int64  `json:"exp"`
+}
+
+func parseClaims(token string) (claims, bool) {
+	var parsed claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != jwtParts {
+		return parsed, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return parsed, false
+	}
+
+	if err = json.Unmarshal(payload, &parsed); err != nil {
+		return parsed, false
+	}
+
+	return parsed, true
+}
+
+func (c claims) account() public.Account {
+	if c.ObjectID == "" || c.TenantID == "" {
+		return public.Account{}
+	}
+
+	username := c.UPN
+	if username == "" {
+		username = c.PreferredUsername
+	}
+
+	if username == "" {
+		username = c.AppID
+	}
+
+	return public.Account{
+		HomeAccountID:     c.ObjectID + "." + c.TenantID,
+		Realm:             c.TenantID,
+		PreferredUsername: username,
+	}
+}
@@ -0,0 +1,75 @@
+package authenticator
+
+// ChainConfig holds the non-interactive credentials that may be configured
+// in the environment, such as in a CI pipeline.
+type ChainConfig struct {
+	// Token is an access token to use as is.
+	Token string
+	// TenantID, ClientID and ClientSecret are the client credentials of a
+	// service principal.
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// FederatedTokenFile is the path of a workload identity token that is
+	// exchanged for an access token of the service principal.
+	FederatedTokenFile string
+	// AzureCLI is set to use the account that is signed in with `az login`
+	// instead of the one signed in with indev.
+	AzureCLI bool
+	// AzureCLITenant is the tenant that the Azure CLI must be signed in to,
+	// or empty for any.
+	AzureCLITenant string
+}
+
+// NewCredentialChain returns the first credential that is configured, trying
+// in order an access token, client credentials, a federated token file, the
+// Azure CLI when it is enabled, and finally the interactive acquirer, which
+// uses the cached account of the user. A configured credential that fails is
+// reported rather than skipped, so that a broken pipeline secret does not
+// fall back to another account.
+func NewCredentialChain( //nolint:ireturn // the chain selects an implementation
+	config ChainConfig,
+	interactive TokenAcquirer,
+	options ...ClientCredentialsOption,
+) TokenAcquirer {
+	switch {
+	case config.Token != "":
+		return NewBearerToken(config.Token)
+	case config.ClientID != "" && config.ClientSecret != "":
+		return NewClientCredentials(ClientCredentialsConfig{
+			TenantID:           config.TenantID,
+			ClientID:           config.ClientID,
+			ClientSecret:       config.ClientSecret,
+			FederatedTokenFile: "",
+			Authority:          "",
+			Scopes:             nil,
+		}, options...)
+	case config.ClientID != "" && config.FederatedTokenFile != "":
+		return NewClientCredentials(ClientCredentialsConfig{
+			TenantID:           config.TenantID,
+			ClientID:           config.ClientID,
+			ClientSecret:       "",
+			FederatedTokenFile: config.FederatedTokenFile,
+			Authority:          "",
+			Scopes:             nil,
+		}, options...)
+	case config.AzureCLI:
+		return NewAzureCLI(AzureCLIConfig{
+			Tenant:  config.AzureCLITenant,
+			Scopes:  nil,
+			Timeout: 0,
+		})
+	default:
+		return interactive
+	}
+}
+
+// Source describes where the tokens of an acquirer come from, such as
+// "interactive sign-in", or returns an empty string if it is not known.
+func Source(acquirer TokenAcquirer) string {
+	if described, ok := acquirer.(interface{ Source() string }); ok {
+		return described.Source()
+	}
+
+	return ""
+}
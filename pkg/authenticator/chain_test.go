@@ -0,0 +1,124 @@
+package authenticator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJWT(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+func TestNewCredentialChain(t *testing.T) {
+	interactive := &fakeAcquirer{expiresIn: time.Hour}
+
+	tests := []struct {
+		name   string
+		config ChainConfig
+		source string
+	}{
+		{
+			name:   "access token comes first",
+			config: ChainConfig{Token: "token", TenantID: "tenant", ClientID: "client", ClientSecret: "secret"},
+			source: "access token",
+		},
+		{
+			name:   "client credentials",
+			config: ChainConfig{TenantID: "tenant", ClientID: "client", ClientSecret: "secret", FederatedTokenFile: "/token"},
+			source: "client credentials client",
+		},
+		{
+			name:   "workload identity",
+			config: ChainConfig{TenantID: "tenant", ClientID: "client", FederatedTokenFile: "/token"},
+			source: "workload identity client",
+		},
+		{
+			name:   "pipeline credentials come before the Azure CLI",
+			config: ChainConfig{TenantID: "tenant", ClientID: "client", ClientSecret: "secret", AzureCLI: true},
+			source: "client credentials client",
+		},
+		{
+			name:   "Azure CLI",
+			config: ChainConfig{AzureCLI: true},
+			source: "Azure CLI",
+		},
+		{
+			name:   "client ID alone falls back to the signed-in user",
+			config: ChainConfig{ClientID: "client"},
+			source: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acquirer := NewCredentialChain(tt.config, interactive)
+
+			assert.Equal(t, tt.source, Source(acquirer))
+
+			if tt.source == "" {
+				assert.Same(t, interactive, acquirer)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	token := testJWT(t, map[string]any{
+		"oid": "object",
+		"tid": "tenant",
+		"upn": "user@example.com",
+		"exp": expires.Unix(),
+	})
+
+	bearer := NewBearerToken(token)
+
+	result, err := bearer.Authenticate(testContext())
+	require.NoError(t, err)
+	assert.Equal(t, token, result.AccessToken)
+	assert.Equal(t, expires, result.ExpiresOn)
+	assert.Equal(t, "object.tenant", result.Account.HomeAccountID)
+	assert.Equal(t, "tenant", result.Account.Realm)
+	assert.Equal(t, "user@example.com", result.Account.PreferredUsername)
+
+	account, err := bearer.GetCurrentAccount(testContext())
+	require.NoError(t, err)
+	assert.Equal(t, "object.tenant", account.HomeAccountID)
+}
+
+func TestBearerToken_ServicePrincipal(t *testing.T) {
+	bearer := NewBearerToken(testJWT(t, map[string]any{"oid": "object", "tid": "tenant", "appid": "client"}))
+
+	account, err := bearer.GetCurrentAccount(testContext())
+	require.NoError(t, err)
+	assert.Equal(t, "client", account.PreferredUsername)
+}
+
+func TestBearerToken_Expired(t *testing.T) {
+	bearer := NewBearerToken(testJWT(t, map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}))
+
+	_, err := bearer.Authenticate(testContext())
+	require.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestBearerToken_Opaque(t *testing.T) {
+	bearer := NewBearerToken("opaque")
+
+	result, err := bearer.Authenticate(testContext())
+	require.NoError(t, err)
+	assert.Equal(t, "opaque", result.AccessToken)
+
+	_, err = bearer.GetCurrentAccount(testContext())
+	require.ErrorIs(t, err, ErrUnknownTokenAccount)
+}
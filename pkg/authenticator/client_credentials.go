@@ -0,0 +1,266 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+
+	"github.com/intility/indev/internal/build"
+	"github.com/intility/indev/internal/telemetry"
+)
+
+const defaultAuthorityHost = "https://login.microsoftonline.com/"
+
+var ErrMissingClientCredentials = errors.New("tenant ID, client ID and a client secret or federated token file are required")
+
+// ClientCredentialsConfig configures how a service principal signs in.
+type ClientCredentialsConfig struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// FederatedTokenFile is the path of a workload identity token, such as
+	// the one projected into AKS pods, that is used instead of a secret. It
+	// is read again every time a token is acquired, as it is rotated.
+	FederatedTokenFile string
+	// Authority defaults to the Entra ID authority of the tenant.
+	Authority string
+	// Scopes default to the .default scope of the platform audience.
+	Scopes []string
+}
+
+// ClientCredentials acquires tokens for a service principal with the client
+// credentials flow, for services and pipelines that cannot sign in
+// interactively. The service principal proves its identity with a secret or
+// a federated token. Tokens are cached in memory.
+type ClientCredentials struct {
+	config     ClientCredentialsConfig
+	httpClient *http.Client
+
+	clientMu sync.Mutex
+	client   *confidential.Client
+}
+
+type ClientCredentialsOption func(*ClientCredentials)
+
+// WithClientCredentialsHTTPClient configures the service principal to send
+// its requests to Entra ID with the given client.
+func WithClientCredentialsHTTPClient(httpClient *http.Client) ClientCredentialsOption {
+	return func(credentials *ClientCredentials) {
+		credentials.httpClient = httpClient
+	}
+}
+
+// NewClientCredentials creates a token acquirer for the service principal
+// with the given configuration.
+func NewClientCredentials(config ClientCredentialsConfig, options ...ClientCredentialsOption) *ClientCredentials {
+	if config.Authority == "" {
+		config.Authority = defaultAuthorityHost + config.TenantID
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes(build.Scopes())
+	}
+
+	credentials := &ClientCredentials{
+		config:     config,
+		httpClient: nil,
+		clientMu:   sync.Mutex{},
+		client:     nil,
+	}
+
+	for _, opt := range options {
+		opt(credentials)
+	}
+
+	return credentials
+}
+
+// DefaultScopes converts delegated scopes such as
+// "api://app/user_impersonation" into the ".default" scopes of the same
+// resources, which are the only ones an application can be granted.
+func DefaultScopes(scopes []string) []string {
+	converted := make([]string, 0, len(scopes))
+
+	for _, scope := range scopes {
+		scheme, resource, found := strings.Cut(scope, "://")
+		if !found {
+			scheme, resource = "", scope
+		} else {
+			scheme += "://"
+		}
+
+		if i := strings.Index(resource, "/"); i >= 0 {
+			resource = resource[:i]
+		}
+
+		converted = append(converted, scheme+resource+"/.default")
+	}
+
+	return converted
+}
+
+func (c *ClientCredentials) Authenticate(ctx context.Context) (public.AuthResult, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ClientCredentials.Authenticate")
+	defer span.End()
+
+	client, err := c.confidentialClient()
+	if err != nil {
+		return public.AuthResult{}, err
+	}
+
+	// tokens are served from the in-memory cache of the client until they
+	// are about to expire
+	result, err := client.AcquireTokenByCredential(ctx, c.config.Scopes)
+	if err != nil {
+		return public.AuthResult{}, fmt.Errorf("could not acquire token for client %s: %w", c.config.ClientID, err)
+	}
+
+	// app-only tokens carry no account, so one is made up from the service
+	// principal to identify the tenant
+	if result.Account.IsZero() {
+		result.Account = c.account()
+	}
+
+	return result, nil
+}
+
+// AuthenticateFor acquires a token for the given scopes, or the configured
+// ones if there are none. The token cache is skipped when refresh is set.
+func (c *ClientCredentials) AuthenticateFor(ctx context.Context, scopes []string, refresh bool) (public.AuthResult, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ClientCredentials.AuthenticateFor")
+	defer span.End()
+
+	if len(scopes) == 0 {
+		scopes = c.config.Scopes
+	}
+
+	getClient := c.confidentialClient
+
+	// a client of its own starts with an empty cache, so it acquires a new
+	// token
+	if refresh {
+		getClient = c.newConfidentialClient
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return public.AuthResult{}, err
+	}
+
+	result, err := client.AcquireTokenByCredential(ctx, scopes)
+	if err != nil {
+		return public.AuthResult{}, fmt.Errorf("could not acquire token for client %s: %w", c.config.ClientID, err)
+	}
+
+	if result.Account.IsZero() {
+		result.Account = c.account()
+	}
+
+	return result, nil
+}
+
+func (c *ClientCredentials) AuthenticateSilent(ctx context.Context) (public.AuthResult, error) {
+	return c.Authenticate(ctx)
+}
+
+func (c *ClientCredentials) Source() string {
+	if c.config.FederatedTokenFile != "" {
+		return "workload identity " + c.config.ClientID
+	}
+
+	return "client credentials " + c.config.ClientID
+}
+
+func (c *ClientCredentials) GetCurrentAccount(context.Context) (public.Account, error) {
+	return c.account(), nil
+}
+
+func (c *ClientCredentials) account() public.Account {
+	return public.Account{
+		HomeAccountID:     c.config.ClientID + "." + c.config.TenantID,
+		Realm:             c.config.TenantID,
+		PreferredUsername: c.config.ClientID,
+	}
+}
+
+func (c *ClientCredentials) confidentialClient() (*confidential.Client, error) {
+	c.clientMu.Lock()
+	defer c.clientMu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	client, err := c.newConfidentialClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c.client = client
+
+	return c.client, nil
+}
+
+// newConfidentialClient creates a client for the service principal, with an
+// empty in-memory token cache.
+func (c *ClientCredentials) newConfidentialClient() (*confidential.Client, error) {
+	credential, err := c.credential()
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]confidential.Option, 0, 2) //nolint:mnd // at most two options
+	if c.httpClient != nil {
+		options = append(options, confidential.WithHTTPClient(c.httpClient))
+	}
+
+	// authorities outside Entra ID, such as AD FS, are unknown to instance
+	// discovery
+	if !strings.HasPrefix(c.config.Authority, defaultAuthorityHost) {
+		options = append(options, confidential.WithInstanceDiscovery(false))
+	}
+
+	client, err := confidential.New(c.config.Authority, c.config.ClientID, credential, options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create confidential client: %w", err)
+	}
+
+	return &client, nil
+}
+
+func (c *ClientCredentials) credential() (confidential.Credential, error) {
+	if c.config.TenantID == "" || c.config.ClientID == "" {
+		return confidential.Credential{}, ErrMissingClientCredentials
+	}
+
+	if c.config.FederatedTokenFile != "" {
+		return confidential.NewCredFromAssertionCallback(c.readFederatedToken), nil
+	}
+
+	if c.config.ClientSecret == "" {
+		return confidential.Credential{}, ErrMissingClientCredentials
+	}
+
+	credential, err := confidential.NewCredFromSecret(c.config.ClientSecret)
+	if err != nil {
+		return confidential.Credential{}, fmt.Errorf("invalid client secret: %w", err)
+	}
+
+	return credential, nil
+}
+
+func (c *ClientCredentials) readFederatedToken(context.Context, confidential.AssertionRequestOptions) (string, error) {
+	token, err := os.ReadFile(c.config.FederatedTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read federated token: %w", err)
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
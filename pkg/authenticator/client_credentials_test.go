@@ -0,0 +1,146 @@
+package authenticator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeEntraID serves the discovery document and token endpoint of a
+// tenant, counting the tokens it issues and keeping the last token request.
+func newFakeEntraID(t *testing.T, issued *atomic.Int32, form *url.Values) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /tenant/v2.0/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"token_endpoint":         server.URL + "/tenant/oauth2/v2.0/token",
+			"authorization_endpoint": server.URL + "/tenant/oauth2/v2.0/authorize",
+			"issuer":                 server.URL + "/tenant/v2.0",
+		})
+	})
+
+	mux.HandleFunc("POST /tenant/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Contains(t, r.PostForm.Get("scope"), "api://platform/.default")
+
+		issued.Add(1)
+		*form = r.PostForm
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "app-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	return server
+}
+
+func TestClientCredentials(t *testing.T) {
+	var (
+		issued atomic.Int32
+		form   url.Values
+	)
+
+	server := newFakeEntraID(t, &issued, &form)
+	credentials := NewClientCredentials(ClientCredentialsConfig{
+		TenantID:     "tenant",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Authority:    server.URL + "/tenant",
+		Scopes:       []string{"api://platform/.default"},
+	}, WithClientCredentialsHTTPClient(server.Client()))
+
+	for range 2 {
+		result, err := credentials.Authenticate(testContext())
+		require.NoError(t, err)
+		assert.Equal(t, "app-token", result.AccessToken)
+		assert.Equal(t, "tenant", result.Account.Realm)
+		assert.Equal(t, "client.tenant", result.Account.HomeAccountID)
+	}
+
+	assert.Equal(t, int32(1), issued.Load(), "the token is cached")
+	assert.Equal(t, "secret", form.Get("client_secret"))
+	assert.Equal(t, "client credentials client", credentials.Source())
+}
+
+func TestClientCredentials_Refresh(t *testing.T) {
+	var (
+		issued atomic.Int32
+		form   url.Values
+	)
+
+	server := newFakeEntraID(t, &issued, &form)
+	credentials := NewClientCredentials(ClientCredentialsConfig{
+		TenantID:     "tenant",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Authority:    server.URL + "/tenant",
+		Scopes:       []string{"api://platform/.default"},
+	}, WithClientCredentialsHTTPClient(server.Client()))
+
+	_, err := credentials.Authenticate(testContext())
+	require.NoError(t, err)
+
+	result, err := credentials.AuthenticateFor(testContext(), nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", result.AccessToken)
+
+	assert.Equal(t, int32(2), issued.Load(), "the cached token is skipped")
+	assert.Empty(t, form.Get("claims"), "no client capabilities are declared")
+}
+
+func TestClientCredentials_FederatedToken(t *testing.T) {
+	var (
+		issued atomic.Int32
+		form   url.Values
+	)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token\n"), 0o600))
+
+	server := newFakeEntraID(t, &issued, &form)
+	credentials := NewClientCredentials(ClientCredentialsConfig{
+		TenantID:           "tenant",
+		ClientID:           "client",
+		FederatedTokenFile: tokenFile,
+		Authority:          server.URL + "/tenant",
+		Scopes:             []string{"api://platform/.default"},
+	}, WithClientCredentialsHTTPClient(server.Client()))
+
+	result, err := credentials.Authenticate(testContext())
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", result.AccessToken)
+
+	assert.Equal(t, "federated-token", form.Get("client_assertion"))
+	assert.Empty(t, form.Get("client_secret"))
+	assert.Equal(t, "workload identity client", credentials.Source())
+}
+
+func TestClientCredentials_Missing(t *testing.T) {
+	credentials := NewClientCredentials(ClientCredentialsConfig{TenantID: "tenant", ClientID: "client"})
+
+	_, err := credentials.Authenticate(testContext())
+	require.ErrorIs(t, err, ErrMissingClientCredentials)
+}
+
+func TestDefaultScopes(t *testing.T) {
+	assert.Equal(t,
+		[]string{"api://platform/.default", "https://graph.microsoft.com/.default", "app/.default"},
+		DefaultScopes([]string{"api://platform/user_impersonation", "https://graph.microsoft.com/User.Read", "app"}),
+	)
+}
@@ -0,0 +1,103 @@
+package authenticator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"runtime"
+)
+
+// WithAutomaticFlow configures the authenticator to sign in with a browser
+// when one can be opened, and with the device code flow otherwise, such as
+// in SSH sessions and on machines without a display. When the port of the
+// redirect URI is taken, the browser redirects to a random free loopback
+// port instead. The printer displays the device code message.
+func WithAutomaticFlow(printer Printer) Option {
+	return func(auth *Authenticator) {
+		auth.flow = FlowAutomatic
+		auth.printer = printer
+	}
+}
+
+// chooseFlow picks the flow of FlowAutomatic and the redirect URI of the
+// interactive flow. The reason is set when the device code flow is picked.
+func (a *Authenticator) chooseFlow() (Flow, string, string) {
+	if reason := browserUnavailable(runtime.GOOS, os.Getenv); reason != "" {
+		return FlowDeviceCode, "", reason
+	}
+
+	redirectURI, err := loopbackRedirect(a.redirectURI)
+	if err != nil {
+		return FlowDeviceCode, "", fmt.Sprintf("no loopback port is free for the browser to redirect to: %v", err)
+	}
+
+	return FlowInteractive, redirectURI, ""
+}
+
+// announceDeviceCode tells the user why the device code flow was picked.
+func (a *Authenticator) announceDeviceCode(ctx context.Context, reason string) error {
+	if a.printer == nil {
+		return ErrNoPrinter
+	}
+
+	return a.printer(ctx, fmt.Sprintf("Signing in with a device code, as %s.", reason))
+}
+
+// browserUnavailable returns why a browser cannot be opened on this machine
+// to sign in, or an empty string when it can. Sessions over SSH cannot reach
+// the loopback redirect of a browser on the client, and Unix machines
+// without a display have no browser to open, unless BROWSER names one, as
+// in dev containers.
+func browserUnavailable(goos string, getenv func(string) string) string {
+	if getenv("BROWSER") != "" {
+		return ""
+	}
+
+	if getenv("SSH_CONNECTION") != "" || getenv("SSH_CLIENT") != "" || getenv("SSH_TTY") != "" {
+		return "an SSH session was detected"
+	}
+
+	switch goos {
+	case "windows", "darwin", "ios", "android":
+		return ""
+	}
+
+	if getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "" {
+		return "there is no display to open a browser on"
+	}
+
+	return ""
+}
+
+// loopbackRedirect returns the redirect URI to use for the interactive flow.
+// When the port of the configured URI cannot be bound, the URI without a
+// port is returned, for which a random free port is used. Entra ID accepts
+// any port on loopback redirect URIs.
+func loopbackRedirect(redirectURI string) (string, error) {
+	parsed, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect URI: %w", err)
+	}
+
+	if parsed.Port() != "" {
+		listener, err := net.Listen("tcp", net.JoinHostPort("localhost", parsed.Port()))
+		if err == nil {
+			_ = listener.Close()
+
+			return redirectURI, nil
+		}
+
+		parsed.Host = parsed.Hostname()
+	}
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return "", fmt.Errorf("could not listen on loopback: %w", err)
+	}
+
+	_ = listener.Close()
+
+	return parsed.String(), nil
+}
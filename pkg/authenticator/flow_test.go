@@ -0,0 +1,59 @@
+package authenticator
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserUnavailable(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		env        map[string]string
+		wantReason bool
+	}{
+		{name: "linux desktop", goos: "linux", env: map[string]string{"DISPLAY": ":0"}},
+		{name: "wayland", goos: "linux", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0"}},
+		{name: "headless linux", goos: "linux", wantReason: true},
+		{name: "macOS", goos: "darwin"},
+		{name: "windows", goos: "windows"},
+		{name: "ssh session", goos: "darwin", env: map[string]string{"SSH_CONNECTION": "10.0.0.1 22 10.0.0.2 22"}, wantReason: true},
+		{name: "ssh with X forwarding", goos: "linux", env: map[string]string{"SSH_TTY": "/dev/pts/0", "DISPLAY": "localhost:10.0"}, wantReason: true},
+		{name: "dev container browser", goos: "linux", env: map[string]string{"BROWSER": "/vscode/bin/helpers/browser.sh"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := browserUnavailable(tt.goos, func(key string) string { return tt.env[key] })
+			assert.Equal(t, tt.wantReason, reason != "", reason)
+		})
+	}
+}
+
+func TestLoopbackRedirect(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	// the port is taken, so a random port is used
+	redirectURI, err := loopbackRedirect("http://localhost:" + port)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost", redirectURI)
+
+	require.NoError(t, listener.Close())
+
+	redirectURI, err = loopbackRedirect("http://localhost:" + port)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(redirectURI)
+	require.NoError(t, err)
+	assert.Equal(t, port, parsed.Port())
+}
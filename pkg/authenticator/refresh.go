@@ -0,0 +1,136 @@
+package authenticator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+)
+
+// accessTokenSection is the section of the MSAL token cache that holds the
+// access tokens, as defined by the cache schema shared by all MSAL libraries.
+const accessTokenSection = "AccessToken"
+
+// withoutAccessTokens is a token cache that hides the cached access tokens
+// from MSAL, which has no option to skip them. MSAL then redeems the refresh
+// token of the account for a new access token. The hidden access tokens are
+// merged back when MSAL stores the cache, so that the tokens of other
+// accounts, tenants and scopes are kept.
+type withoutAccessTokens struct {
+	cache.ExportReplace
+
+	mu sync.Mutex
+	// hidden are the access tokens of the cache last loaded, by key
+	hidden map[string]json.RawMessage
+}
+
+func newWithoutAccessTokens(tokenCache cache.ExportReplace) *withoutAccessTokens {
+	return &withoutAccessTokens{
+		ExportReplace: tokenCache,
+		mu:            sync.Mutex{},
+		hidden:        nil,
+	}
+}
+
+func (c *withoutAccessTokens) Replace(ctx context.Context, target cache.Unmarshaler, hints cache.ReplaceHints) error {
+	filter := accessTokenFilter{Unmarshaler: target, cache: c}
+
+	return c.ExportReplace.Replace(ctx, filter, hints) //nolint:wrapcheck // transparent wrapper
+}
+
+func (c *withoutAccessTokens) Export(ctx context.Context, source cache.Marshaler, hints cache.ExportHints) error {
+	merger := accessTokenMerger{Marshaler: source, cache: c}
+
+	return c.ExportReplace.Export(ctx, merger, hints) //nolint:wrapcheck // transparent wrapper
+}
+
+// accessTokenFilter removes the access tokens from the cache it loads, and
+// keeps them aside.
+type accessTokenFilter struct {
+	cache.Unmarshaler
+
+	cache *withoutAccessTokens
+}
+
+func (f accessTokenFilter) Unmarshal(data []byte) error {
+	var sections map[string]json.RawMessage
+
+	// caches that cannot be parsed are left for MSAL to report
+	if len(data) == 0 || json.Unmarshal(data, &sections) != nil {
+		return f.Unmarshaler.Unmarshal(data) //nolint:wrapcheck // transparent wrapper
+	}
+
+	var hidden map[string]json.RawMessage
+
+	if section, ok := sections[accessTokenSection]; ok {
+		if err := json.Unmarshal(section, &hidden); err != nil {
+			return fmt.Errorf("could not decode access tokens: %w", err)
+		}
+	}
+
+	f.cache.mu.Lock()
+	f.cache.hidden = hidden
+	f.cache.mu.Unlock()
+
+	delete(sections, accessTokenSection)
+
+	filtered, err := json.Marshal(sections)
+	if err != nil {
+		return fmt.Errorf("could not encode token cache: %w", err)
+	}
+
+	return f.Unmarshaler.Unmarshal(filtered) //nolint:wrapcheck // transparent wrapper
+}
+
+// accessTokenMerger adds the hidden access tokens to the cache that MSAL
+// stores. The access tokens acquired by MSAL replace hidden ones with the
+// same key.
+type accessTokenMerger struct {
+	cache.Marshaler
+
+	cache *withoutAccessTokens
+}
+
+func (m accessTokenMerger) Marshal() ([]byte, error) {
+	data, err := m.Marshaler.Marshal()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	m.cache.mu.Lock()
+	hidden := maps.Clone(m.cache.hidden)
+	m.cache.mu.Unlock()
+
+	if len(hidden) == 0 {
+		return data, nil
+	}
+
+	var sections map[string]json.RawMessage
+	if err = json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("could not decode token cache: %w", err)
+	}
+
+	var acquired map[string]json.RawMessage
+
+	if section, ok := sections[accessTokenSection]; ok {
+		if err = json.Unmarshal(section, &acquired); err != nil {
+			return nil, fmt.Errorf("could not decode access tokens: %w", err)
+		}
+	}
+
+	maps.Copy(hidden, acquired)
+
+	if sections[accessTokenSection], err = json.Marshal(hidden); err != nil {
+		return nil, fmt.Errorf("could not encode access tokens: %w", err)
+	}
+
+	merged, err := json.Marshal(sections)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode token cache: %w", err)
+	}
+
+	return merged, nil
+}
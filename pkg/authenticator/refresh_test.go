@@ -0,0 +1,99 @@
+package authenticator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedCache keeps the cache data, like a token cache on disk.
+type storedCache struct {
+	data []byte
+}
+
+func (c *storedCache) Replace(_ context.Context, target cache.Unmarshaler, _ cache.ReplaceHints) error {
+	return target.Unmarshal(c.data)
+}
+
+func (c *storedCache) Export(_ context.Context, source cache.Marshaler, _ cache.ExportHints) error {
+	data, err := source.Marshal()
+	c.data = data
+
+	return err
+}
+
+// msalCache is the cache as MSAL sees it.
+type msalCache struct {
+	data []byte
+}
+
+func (c *msalCache) Unmarshal(data []byte) error {
+	c.data = data
+
+	return nil
+}
+
+func (c *msalCache) Marshal() ([]byte, error) {
+	return c.data, nil
+}
+
+func TestWithoutAccessTokens(t *testing.T) {
+	t.Run("hides the access tokens", func(t *testing.T) {
+		stored := &storedCache{data: []byte(`{
+			"AccessToken": {"key": {"secret": "access-token"}},
+			"RefreshToken": {"key": {"secret": "refresh-token"}},
+			"Account": {"key": {"home_account_id": "oid.tid"}}
+		}`)}
+
+		var loaded msalCache
+
+		require.NoError(t, newWithoutAccessTokens(stored).Replace(testContext(), &loaded, cache.ReplaceHints{}))
+		assert.JSONEq(t, `{
+			"RefreshToken": {"key": {"secret": "refresh-token"}},
+			"Account": {"key": {"home_account_id": "oid.tid"}}
+		}`, string(loaded.data))
+	})
+
+	t.Run("keeps the access tokens of other accounts", func(t *testing.T) {
+		stored := &storedCache{data: []byte(`{
+			"AccessToken": {
+				"ada-token": {"secret": "short-lived"},
+				"alan-token": {"secret": "alan"}
+			},
+			"RefreshToken": {"ada-refresh": {"secret": "refresh-token"}}
+		}`)}
+		tokenCache := newWithoutAccessTokens(stored)
+
+		var msal msalCache
+
+		require.NoError(t, tokenCache.Replace(testContext(), &msal, cache.ReplaceHints{}))
+
+		// MSAL redeems the refresh token of ada for a new access token
+		msal.data = []byte(`{
+			"AccessToken": {"ada-token": {"secret": "refreshed"}},
+			"RefreshToken": {"ada-refresh": {"secret": "rotated"}}
+		}`)
+
+		require.NoError(t, tokenCache.Export(testContext(), &msal, cache.ExportHints{}))
+		assert.JSONEq(t, `{
+			"AccessToken": {
+				"ada-token": {"secret": "refreshed"},
+				"alan-token": {"secret": "alan"}
+			},
+			"RefreshToken": {"ada-refresh": {"secret": "rotated"}}
+		}`, string(stored.data))
+	})
+
+	t.Run("leaves empty and invalid caches to MSAL", func(t *testing.T) {
+		for _, data := range []string{"", "not json"} {
+			var loaded msalCache
+
+			require.NoError(t, newWithoutAccessTokens(&storedCache{data: []byte(data)}).Replace(
+				testContext(), &loaded, cache.ReplaceHints{}))
+			assert.Equal(t, data, string(loaded.data))
+		}
+	})
+}
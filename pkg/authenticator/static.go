@@ -0,0 +1,54 @@
+package authenticator
+
+import (
+	"context"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+// PlaceholderToken is the access token handed out by StaticAcquirer.
+const PlaceholderToken = "placeholder"
+
+const placeholderObjectID = "00000000-0000-0000-0000-000000000000"
+
+const staticTokenLifetime = time.Hour
+
+// StaticAcquirer hands out a placeholder token without contacting the
+// identity provider. It is used when commands talk to a recorded or fake
+// platform that does not validate tokens.
+type StaticAcquirer struct {
+	Account public.Account
+}
+
+// NewStaticAcquirer creates a StaticAcquirer for a placeholder account in the
+// given tenant.
+func NewStaticAcquirer(tenantID, username string) StaticAcquirer {
+	return StaticAcquirer{
+		Account: public.Account{
+			HomeAccountID:     placeholderObjectID + "." + tenantID,
+			Realm:             tenantID,
+			PreferredUsername: username,
+		},
+	}
+}
+
+func (a StaticAcquirer) Authenticate(context.Context) (public.AuthResult, error) {
+	return public.AuthResult{
+		Account:     a.Account,
+		AccessToken: PlaceholderToken,
+		ExpiresOn:   time.Now().Add(staticTokenLifetime),
+	}, nil
+}
+
+func (a StaticAcquirer) AuthenticateSilent(ctx context.Context) (public.AuthResult, error) {
+	return a.Authenticate(ctx)
+}
+
+func (a StaticAcquirer) Source() string {
+	return "placeholder token"
+}
+
+func (a StaticAcquirer) GetCurrentAccount(context.Context) (public.Account, error) {
+	return a.Account, nil
+}
@@ -0,0 +1,292 @@
+package authenticator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+
+	"github.com/intility/indev/internal/telemetry"
+)
+
+// defaultRefreshMargin is how long before its expiry a cached token is
+// considered stale and refreshed.
+const defaultRefreshMargin = 2 * time.Minute
+
+// TokenAcquirer acquires tokens from the identity provider. It is implemented
+// by Authenticator.
+type TokenAcquirer interface {
+	Authenticate(ctx context.Context) (public.AuthResult, error)
+	AuthenticateSilent(ctx context.Context) (public.AuthResult, error)
+	GetCurrentAccount(ctx context.Context) (public.Account, error)
+}
+
+// TokenSource keeps the access token in memory for the lifetime of the
+// process, so that a command performing several requests only hits the token
+// cache and the identity provider once. It is safe for concurrent use, and
+// concurrent callers share a single refresh.
+type TokenSource struct {
+	acquirer      TokenAcquirer
+	refreshMargin time.Duration
+	now           func() time.Time
+
+	// refresh is a semaphore that allows a single refresh at a time, while
+	// letting waiting callers give up when their context is cancelled.
+	refresh chan struct{}
+
+	mu    sync.RWMutex
+	token *public.AuthResult
+}
+
+type TokenSourceOption func(*TokenSource)
+
+// NewTokenSource creates a token source that acquires tokens using the given
+// acquirer.
+func NewTokenSource(acquirer TokenAcquirer, options ...TokenSourceOption) *TokenSource {
+	source := &TokenSource{
+		acquirer:      acquirer,
+		refreshMargin: defaultRefreshMargin,
+		now:           time.Now,
+		refresh:       make(chan struct{}, 1),
+		mu:            sync.RWMutex{},
+		token:         nil,
+	}
+
+	source.Configure(options...)
+
+	return source
+}
+
+// Configure applies options to an existing token source, dropping any cached
+// token. It must be called before the token source is used concurrently.
+func (s *TokenSource) Configure(options ...TokenSourceOption) {
+	for _, opt := range options {
+		opt(s)
+	}
+
+	s.Invalidate()
+}
+
+// WithAcquirer replaces the acquirer that tokens are acquired with.
+func WithAcquirer(acquirer TokenAcquirer) TokenSourceOption {
+	return func(source *TokenSource) {
+		source.acquirer = acquirer
+	}
+}
+
+// WithRefreshMargin configures how long before its expiry a cached token is
+// refreshed.
+func WithRefreshMargin(margin time.Duration) TokenSourceOption {
+	return func(source *TokenSource) {
+		source.refreshMargin = margin
+	}
+}
+
+// Token returns a valid access token. A cached token is returned if it is not
+// about to expire, otherwise a new token is acquired, prompting the user with
+// the configured flow if necessary.
+func (s *TokenSource) Token(ctx context.Context) (public.AuthResult, error) {
+	return s.get(ctx, s.acquirer.Authenticate)
+}
+
+// ClaimsAcquirer is implemented by acquirers that can acquire a token that
+// satisfies a claims challenge, such as Authenticator.
+type ClaimsAcquirer interface {
+	AuthenticateWithClaims(ctx context.Context, claims string) (public.AuthResult, error)
+}
+
+// TokenWithClaims drops the cached token and acquires one that satisfies the
+// claims challenge of a rejected request. Acquirers that cannot satisfy
+// claims challenges fail with ErrReauthenticationRequired.
+func (s *TokenSource) TokenWithClaims(ctx context.Context, claims string) (public.AuthResult, error) {
+	challenged, ok := s.acquirer.(ClaimsAcquirer)
+	if !ok {
+		return public.AuthResult{}, fmt.Errorf("%w: the platform rejected the access token and asked for a new sign-in",
+			ErrReauthenticationRequired)
+	}
+
+	s.Invalidate()
+
+	return s.get(ctx, func(ctx context.Context) (public.AuthResult, error) {
+		return challenged.AuthenticateWithClaims(ctx, claims)
+	})
+}
+
+// ScopedAcquirer is implemented by acquirers that can acquire tokens for
+// other scopes than the platform, and skip their cache, such as
+// Authenticator and ClientCredentials.
+type ScopedAcquirer interface {
+	AuthenticateFor(ctx context.Context, scopes []string, refresh bool) (public.AuthResult, error)
+}
+
+// TokenFor returns an access token for the given scopes, or for the platform
+// if there are none, that is valid for at least minValidity. Platform tokens
+// are acquired like the ones of Token, and kept for later use.
+func (s *TokenSource) TokenFor(
+	ctx context.Context,
+	scopes []string,
+	minValidity time.Duration,
+) (public.AuthResult, error) {
+	var (
+		token public.AuthResult
+		err   error
+	)
+
+	scoped, ok := s.acquirer.(ScopedAcquirer)
+
+	switch {
+	case len(scopes) == 0:
+		token, err = s.Token(ctx)
+	case ok:
+		token, err = scoped.AuthenticateFor(ctx, scopes, false)
+	default:
+		return token, fmt.Errorf("%w with %s", ErrScopesNotSupported, s.Source())
+	}
+
+	if err != nil || s.validFor(token, minValidity) {
+		return token, err
+	}
+
+	if !ok {
+		return token, fmt.Errorf("%w: the token of %s expires at %s",
+			ErrTokenTooShortLived, s.Source(), token.ExpiresOn.Format(time.RFC3339))
+	}
+
+	token, err = scoped.AuthenticateFor(ctx, scopes, true)
+	if err != nil {
+		return token, err
+	}
+
+	if !s.validFor(token, minValidity) {
+		return token, fmt.Errorf("%w: the identity provider issued a token that expires at %s",
+			ErrTokenTooShortLived, token.ExpiresOn.Format(time.RFC3339))
+	}
+
+	if len(scopes) == 0 {
+		s.mu.Lock()
+		s.token = &token
+		s.mu.Unlock()
+	}
+
+	return token, nil
+}
+
+func (s *TokenSource) validFor(token public.AuthResult, validity time.Duration) bool {
+	return token.ExpiresOn.IsZero() || !s.now().Add(validity).After(token.ExpiresOn)
+}
+
+// IsAuthenticated reports whether a token can be obtained without user
+// interaction. A successfully acquired token is kept for later use.
+func (s *TokenSource) IsAuthenticated(ctx context.Context) (bool, error) {
+	ctx, span := telemetry.StartSpan(ctx, "TokenSource.IsAuthenticated")
+	defer span.End()
+
+	if _, err := s.get(ctx, s.acquirer.AuthenticateSilent); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetCurrentAccount returns the account of the cached token, or the current
+// account of the acquirer if no token has been acquired yet.
+func (s *TokenSource) GetCurrentAccount(ctx context.Context) (public.Account, error) {
+	s.mu.RLock()
+	token := s.token
+	s.mu.RUnlock()
+
+	if token != nil {
+		return token.Account, nil
+	}
+
+	account, err := s.acquirer.GetCurrentAccount(ctx)
+	if err != nil {
+		return account, fmt.Errorf("could not get current account: %w", err)
+	}
+
+	return account, nil
+}
+
+// Accounts returns the accounts that are signed in. Acquirers that do not
+// keep accounts, such as client credentials, have only the current account.
+func (s *TokenSource) Accounts(ctx context.Context) ([]public.Account, error) {
+	if lister, ok := s.acquirer.(interface {
+		Accounts(ctx context.Context) ([]public.Account, error)
+	}); ok {
+		accounts, err := lister.Accounts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not list accounts: %w", err)
+		}
+
+		return accounts, nil
+	}
+
+	account, err := s.acquirer.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get current account: %w", err)
+	}
+
+	return []public.Account{account}, nil
+}
+
+// Source describes where tokens come from, such as "interactive sign-in".
+func (s *TokenSource) Source() string {
+	return Source(s.acquirer)
+}
+
+// Invalidate drops the cached token, forcing the next call to acquire a new one.
+func (s *TokenSource) Invalidate() {
+	s.mu.Lock()
+	s.token = nil
+	s.mu.Unlock()
+}
+
+func (s *TokenSource) get(
+	ctx context.Context,
+	acquire func(ctx context.Context) (public.AuthResult, error),
+) (public.AuthResult, error) {
+	if token, ok := s.cached(); ok {
+		return token, nil
+	}
+
+	select {
+	case s.refresh <- struct{}{}:
+	case <-ctx.Done():
+		return public.AuthResult{}, fmt.Errorf("waiting for token refresh: %w", ctx.Err())
+	}
+
+	defer func() { <-s.refresh }()
+
+	// another caller may have refreshed the token while we were waiting
+	if token, ok := s.cached(); ok {
+		return token, nil
+	}
+
+	token, err := acquire(ctx)
+	if err != nil {
+		return token, err
+	}
+
+	s.mu.Lock()
+	s.token = &token
+	s.mu.Unlock()
+
+	return token, nil
+}
+
+func (s *TokenSource) cached() (public.AuthResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.token == nil || s.token.AccessToken == "" {
+		return public.AuthResult{}, false
+	}
+
+	if s.now().Add(s.refreshMargin).After(s.token.ExpiresOn) {
+		return public.AuthResult{}, false
+	}
+
+	return *s.token, true
+}
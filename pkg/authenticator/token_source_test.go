@@ -0,0 +1,277 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/intility/indev/internal/telemetry"
+)
+
+func testContext() context.Context {
+	return telemetry.ContextWithTracer(context.Background(), noop.NewTracerProvider().Tracer("test"))
+}
+
+type fakeAcquirer struct {
+	interactive atomic.Int32
+	silent      atomic.Int32
+	expiresIn   time.Duration
+	delay       time.Duration
+	err         error
+}
+
+func (f *fakeAcquirer) result() (public.AuthResult, error) {
+	time.Sleep(f.delay)
+
+	if f.err != nil {
+		return public.AuthResult{}, f.err
+	}
+
+	return public.AuthResult{
+		AccessToken: "token",
+		ExpiresOn:   time.Now().Add(f.expiresIn),
+		Account:     public.Account{HomeAccountID: "oid.tid", PreferredUsername: "user@example.com"},
+	}, nil
+}
+
+func (f *fakeAcquirer) Authenticate(context.Context) (public.AuthResult, error) {
+	f.interactive.Add(1)
+
+	return f.result()
+}
+
+func (f *fakeAcquirer) AuthenticateSilent(context.Context) (public.AuthResult, error) {
+	f.silent.Add(1)
+
+	return f.result()
+}
+
+func (f *fakeAcquirer) GetCurrentAccount(context.Context) (public.Account, error) {
+	return public.Account{HomeAccountID: "from.acquirer"}, nil
+}
+
+func TestTokenSource_Token(t *testing.T) {
+	t.Run("caches token until close to expiry", func(t *testing.T) {
+		acquirer := &fakeAcquirer{expiresIn: time.Hour}
+		source := NewTokenSource(acquirer)
+
+		for range 5 {
+			token, err := source.Token(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "token", token.AccessToken)
+		}
+
+		assert.Equal(t, int32(1), acquirer.interactive.Load())
+	})
+
+	t.Run("refreshes token within the refresh margin", func(t *testing.T) {
+		acquirer := &fakeAcquirer{expiresIn: time.Minute}
+		source := NewTokenSource(acquirer, WithRefreshMargin(2*time.Minute))
+
+		_, err := source.Token(context.Background())
+		require.NoError(t, err)
+
+		_, err = source.Token(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), acquirer.interactive.Load())
+	})
+
+	t.Run("does not cache failures", func(t *testing.T) {
+		acquirer := &fakeAcquirer{expiresIn: time.Hour, err: errors.New("boom")}
+		source := NewTokenSource(acquirer)
+
+		_, err := source.Token(context.Background())
+		require.Error(t, err)
+
+		acquirer.err = nil
+
+		_, err = source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), acquirer.interactive.Load())
+	})
+
+	t.Run("concurrent callers share a single refresh", func(t *testing.T) {
+		acquirer := &fakeAcquirer{expiresIn: time.Hour, delay: 20 * time.Millisecond}
+		source := NewTokenSource(acquirer)
+
+		var wg sync.WaitGroup
+
+		for range 20 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, err := source.Token(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), acquirer.interactive.Load())
+	})
+
+	t.Run("waiting caller gives up when its context is cancelled", func(t *testing.T) {
+		acquirer := &fakeAcquirer{expiresIn: time.Hour, delay: 200 * time.Millisecond}
+		source := NewTokenSource(acquirer)
+
+		go func() { _, _ = source.Token(context.Background()) }()
+
+		time.Sleep(20 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := source.Token(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestTokenSource_IsAuthenticated(t *testing.T) {
+	t.Run("acquires silently and shares the token", func(t *testing.T) {
+		acquirer := &fakeAcquirer{expiresIn: time.Hour}
+		source := NewTokenSource(acquirer)
+
+		ok, err := source.IsAuthenticated(testContext())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		_, err = source.Token(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(1), acquirer.silent.Load())
+		assert.Equal(t, int32(0), acquirer.interactive.Load())
+	})
+
+	t.Run("reports silent acquisition failures", func(t *testing.T) {
+		acquirer := &fakeAcquirer{err: ErrNoAccounts}
+		source := NewTokenSource(acquirer)
+
+		ok, err := source.IsAuthenticated(testContext())
+		require.ErrorIs(t, err, ErrNoAccounts)
+		assert.False(t, ok)
+	})
+}
+
+func TestTokenSource_GetCurrentAccount(t *testing.T) {
+	acquirer := &fakeAcquirer{expiresIn: time.Hour}
+	source := NewTokenSource(acquirer)
+
+	account, err := source.GetCurrentAccount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from.acquirer", account.HomeAccountID)
+
+	_, err = source.Token(context.Background())
+	require.NoError(t, err)
+
+	account, err = source.GetCurrentAccount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "oid.tid", account.HomeAccountID)
+
+	source.Invalidate()
+
+	account, err = source.GetCurrentAccount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from.acquirer", account.HomeAccountID)
+}
+
+func TestTokenSource_ConfigureOffline(t *testing.T) {
+	acquirer := &fakeAcquirer{expiresIn: time.Hour}
+	source := NewTokenSource(acquirer)
+
+	_, err := source.Token(testContext())
+	require.NoError(t, err)
+
+	source.Configure(WithAcquirer(NewOfflineAcquirer(acquirer)))
+
+	token, err := source.Token(testContext())
+	require.NoError(t, err)
+	assert.Equal(t, PlaceholderToken, token.AccessToken, "the cached token is dropped")
+	assert.Equal(t, "from.acquirer", token.Account.HomeAccountID)
+	assert.Equal(t, int32(1), acquirer.interactive.Load(), "offline tokens are not acquired")
+	assert.Equal(t, int32(0), acquirer.silent.Load())
+}
+
+type scopedAcquirer struct {
+	fakeAcquirer
+
+	scopes    [][]string
+	refreshes int
+	refreshed time.Duration
+}
+
+func (s *scopedAcquirer) AuthenticateFor(_ context.Context, scopes []string, refresh bool) (public.AuthResult, error) {
+	s.scopes = append(s.scopes, scopes)
+
+	token, err := s.result()
+	if refresh {
+		s.refreshes++
+		token.ExpiresOn = time.Now().Add(s.refreshed)
+	}
+
+	return token, err
+}
+
+func TestTokenSource_TokenFor(t *testing.T) {
+	t.Run("uses the platform token when it is valid for long enough", func(t *testing.T) {
+		acquirer := &scopedAcquirer{fakeAcquirer: fakeAcquirer{expiresIn: time.Hour}}
+		source := NewTokenSource(acquirer)
+
+		_, err := source.TokenFor(context.Background(), nil, 30*time.Minute)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(1), acquirer.interactive.Load())
+		assert.Empty(t, acquirer.scopes)
+	})
+
+	t.Run("refreshes a token that expires too soon", func(t *testing.T) {
+		acquirer := &scopedAcquirer{fakeAcquirer: fakeAcquirer{expiresIn: 10 * time.Minute}, refreshed: time.Hour}
+		source := NewTokenSource(acquirer)
+
+		token, err := source.TokenFor(context.Background(), nil, 30*time.Minute)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresOn, time.Minute)
+		assert.Equal(t, 1, acquirer.refreshes)
+
+		// the refreshed token is kept
+		_, err = source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), acquirer.interactive.Load())
+	})
+
+	t.Run("fails when the refreshed token expires too soon", func(t *testing.T) {
+		acquirer := &scopedAcquirer{fakeAcquirer: fakeAcquirer{expiresIn: 10 * time.Minute}, refreshed: time.Hour}
+		source := NewTokenSource(acquirer)
+
+		_, err := source.TokenFor(context.Background(), nil, 2*time.Hour)
+		require.ErrorIs(t, err, ErrTokenTooShortLived)
+	})
+
+	t.Run("acquires tokens for other scopes", func(t *testing.T) {
+		acquirer := &scopedAcquirer{fakeAcquirer: fakeAcquirer{expiresIn: time.Hour}}
+		source := NewTokenSource(acquirer)
+
+		_, err := source.TokenFor(context.Background(), []string{"https://graph.microsoft.com/.default"}, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{{"https://graph.microsoft.com/.default"}}, acquirer.scopes)
+		assert.Equal(t, int32(0), acquirer.interactive.Load())
+	})
+
+	t.Run("other scopes are not supported by every acquirer", func(t *testing.T) {
+		source := NewTokenSource(NewBearerToken("token"))
+
+		_, err := source.TokenFor(context.Background(), []string{"https://graph.microsoft.com/.default"}, 0)
+		require.ErrorIs(t, err, ErrScopesNotSupported)
+	})
+}
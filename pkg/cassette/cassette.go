@@ -0,0 +1,330 @@
+// Package cassette provides an http.RoundTripper that records platform
+// interactions to a YAML file and replays them later, without network access
+// or credentials.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/internal/redact"
+)
+
+// Redacted replaces secret values in recorded interactions.
+const Redacted = redact.Placeholder
+
+const filePermissions = 0o600
+
+type Mode string
+
+const (
+	// ModeRecord forwards requests to the platform and records the
+	// interactions.
+	ModeRecord Mode = "record"
+	// ModeReplay serves recorded interactions without network access.
+	ModeReplay Mode = "replay"
+)
+
+var (
+	ErrInvalidMode         = errors.New(`cassette mode must be one of "record", "replay"`)
+	ErrInvalidSpec         = errors.New("invalid cassette specification")
+	ErrInteractionNotFound = errors.New("no recorded interaction matches the request")
+)
+
+// Cassette is the file format of recorded interactions.
+type Cassette struct {
+	// TenantID is the tenant of the recording user. It allows tenant specific
+	// commands to be replayed.
+	TenantID     string        `yaml:"tenantId,omitempty"`
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+type Request struct {
+	Method  string      `yaml:"method"`
+	URL     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `yaml:"statusCode"`
+	Headers    http.Header `yaml:"headers,omitempty"`
+	Body       string      `yaml:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+// depending on its mode. It is safe for concurrent use.
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	// used marks the interactions that have already been replayed, so that
+	// repeated identical requests are answered in recording order
+	used []bool
+}
+
+// NewFromSpec creates a recorder from a "<mode>:<path>" specification, as
+// used by the INDEV_HTTP_CASSETTE environment variable.
+func NewFromSpec(spec string, next http.RoundTripper) (*Recorder, error) {
+	name, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" {
+		return nil, fmt.Errorf("%w: expected <mode>:<path>, got %q", ErrInvalidSpec, spec)
+	}
+
+	mode, err := ParseMode(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(path, mode, next)
+}
+
+// ParseMode parses the name of a cassette mode.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(value); mode {
+	case ModeRecord, ModeReplay:
+		return mode, nil
+	default:
+		return "", ErrInvalidMode
+	}
+}
+
+// New creates a recorder for the cassette at path. In replay mode the
+// cassette is loaded from disk, in record mode it is created, overwriting any
+// previous recording. next is the transport used to reach the platform while
+// recording, and defaults to http.DefaultTransport.
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	recorder := &Recorder{
+		path:     path,
+		mode:     mode,
+		next:     next,
+		mu:       sync.Mutex{},
+		cassette: Cassette{TenantID: "", Interactions: nil},
+		used:     nil,
+	}
+
+	switch mode {
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read cassette: %w", err)
+		}
+
+		if err = yaml.Unmarshal(data, &recorder.cassette); err != nil {
+			return nil, fmt.Errorf("could not parse cassette: %w", err)
+		}
+
+		recorder.used = make([]bool, len(recorder.cassette.Interactions))
+	case ModeRecord:
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, fmt.Errorf("could not create cassette directory: %w", err)
+		}
+	default:
+		return nil, ErrInvalidMode
+	}
+
+	return recorder, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// TenantID returns the tenant the cassette was recorded in.
+func (r *Recorder) TenantID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.TenantID
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+
+	return r.record(req)
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] {
+			continue
+		}
+
+		recorded, err := url.Parse(interaction.Request.URL)
+		if err != nil || matchKey(interaction.Request.Method, recorded) != key {
+			continue
+		}
+
+		r.used[i] = true
+
+		return interaction.Response.toHTTP(req), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, redact.URL(req.URL))
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read request body: %w", err)
+		}
+
+		_ = req.Body.Close()
+		reqBody = body
+
+		// a round tripper must not modify the request it was given
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	tenantID := tenantFromAuthorization(req.Header.Get("Authorization"))
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent transport
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     redact.URL(req.URL),
+			Headers: redact.Header(req.Header),
+			Body:    string(redact.JSON(reqBody)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    redact.Header(resp.Header),
+			Body:       string(redact.JSON(respBody)),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cassette.TenantID == "" {
+		r.cassette.TenantID = tenantID
+	}
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+
+	// the cassette is saved after every interaction, as commands may exit
+	// without a chance to flush it
+	if err = r.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) save() error {
+	data, err := yaml.Marshal(r.cassette)
+	if err != nil {
+		return fmt.Errorf("could not encode cassette: %w", err)
+	}
+
+	if err = os.WriteFile(r.path, data, filePermissions); err != nil {
+		return fmt.Errorf("could not write cassette: %w", err)
+	}
+
+	return nil
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := r.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// matchKey identifies a request independently of the host it was sent to,
+// so that a cassette recorded against one environment can be replayed by a
+// build that targets another.
+func matchKey(method string, u *url.URL) string {
+	return method + " " + u.EscapedPath() + "?" + redact.Query(u.Query()).Encode()
+}
+
+// tenantFromAuthorization extracts the tenant ID from the claims of a bearer
+// token. The token is not validated, as it has already been accepted by the
+// identity provider.
+func tenantFromAuthorization(value string) string {
+	token, ok := strings.CutPrefix(value, "Bearer ")
+	if !ok {
+		return ""
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd // header, payload and signature
+		return ""
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	var claims struct {
+		TenantID string `json:"tid"`
+	}
+
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+
+	return claims.TenantID
+}
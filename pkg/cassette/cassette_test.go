@@ -0,0 +1,168 @@
+package cassette
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeJWT(payload string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(payload)) + ".signature"
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+fakeJWT(`{"tid":"tenant-1"}`))
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set("Set-Cookie", "session=secret")
+
+		switch r.URL.Path {
+		case "/api/v1/keys":
+			_, _ = w.Write([]byte(`{"name":"my-key","key":"sk-12345","size":12345678901234567}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`not found`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "nested", "cassette.yaml")
+
+	recorder, err := New(path, ModeRecord, nil)
+	require.NoError(t, err)
+
+	recordClient := &http.Client{Transport: recorder}
+
+	status, body := get(t, recordClient, server.URL+"/api/v1/keys")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "sk-12345", "the caller receives the real response")
+
+	status, _ = get(t, recordClient, server.URL+"/api/v1/missing?token=abc")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	recorded := string(data)
+	assert.NotContains(t, recorded, "sk-12345")
+	assert.NotContains(t, recorded, "session=secret")
+	assert.NotContains(t, recorded, "Bearer")
+	assert.NotContains(t, recorded, "abc")
+	assert.Contains(t, recorded, "12345678901234567")
+	assert.Contains(t, recorded, "tenantId: tenant-1")
+
+	t.Run("replays interactions without network access", func(t *testing.T) {
+		replayer, err := New(path, ModeReplay, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-1", replayer.TenantID())
+
+		replayClient := &http.Client{Transport: replayer}
+
+		// the host is ignored, so that cassettes can be replayed against any
+		// environment
+		status, body := get(t, replayClient, "http://replay.invalid/api/v1/keys")
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"name":"my-key","key":"REDACTED","size":12345678901234567}`, body)
+
+		status, body = get(t, replayClient, "http://replay.invalid/api/v1/missing?token=other")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "not found", body)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("each interaction is replayed once", func(t *testing.T) {
+		replayer, err := New(path, ModeReplay, nil)
+		require.NoError(t, err)
+
+		replayClient := &http.Client{Transport: replayer}
+
+		get(t, replayClient, "http://replay.invalid/api/v1/keys")
+
+		_, err = replayClient.Get("http://replay.invalid/api/v1/keys")
+		require.ErrorIs(t, err, ErrInteractionNotFound)
+	})
+}
+
+func TestRecordRequestBody(t *testing.T) {
+	var received string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+
+	recorder, err := New(path, ModeRecord, nil)
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: recorder}).Post(
+		server.URL, "application/json", strings.NewReader(`{"name":"x","password":"hunter2"}`),
+	)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	assert.JSONEq(t, `{"name":"x","password":"hunter2"}`, received, "the server receives the real body")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+}
+
+func TestNewFromSpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+
+	recorder, err := NewFromSpec("record:"+path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecord, recorder.Mode())
+
+	_, err = NewFromSpec(path, nil)
+	require.ErrorIs(t, err, ErrInvalidSpec)
+
+	_, err = NewFromSpec("rewind:"+path, nil)
+	require.ErrorIs(t, err, ErrInvalidMode)
+
+	_, err = NewFromSpec("replay:"+filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	require.Error(t, err)
+}
+
+func TestTenantFromAuthorization(t *testing.T) {
+	assert.Equal(t, "tenant-1", tenantFromAuthorization("Bearer "+fakeJWT(`{"tid":"tenant-1"}`)))
+	assert.Empty(t, tenantFromAuthorization("Bearer opaque"))
+	assert.Empty(t, tenantFromAuthorization("Basic dXNlcjpwYXNz"))
+	assert.Empty(t, tenantFromAuthorization(""))
+}
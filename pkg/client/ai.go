@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -67,7 +68,7 @@ func (c *RestClient) CreateAIDeployment(ctx context.Context, request NewAIDeploy
 	}
 
 	var deploy AIDeployment
-	if err = doRequest(c.httpClient, req, &deploy); err != nil {
+	if err = doRequest(c.requester(), req, &deploy); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -75,19 +76,20 @@ func (c *RestClient) CreateAIDeployment(ctx context.Context, request NewAIDeploy
 }
 
 func (c *RestClient) ListAIDeployments(ctx context.Context) ([]AIDeployment, error) {
-	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURIBlurite+"/api/v1/blurite/llm-deployments", nil)
+	deployments, err := Collect(c.IterAIDeployments(ctx, ListOptions{}))
 	if err != nil {
 		return nil, err
 	}
 
-	var deployments []AIDeployment
-	if err = doRequest(c.httpClient, req, &deployments); err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
 	return deployments, nil
 }
 
+// IterAIDeployments returns an iterator over all AI deployments, fetching
+// them one page at a time.
+func (c *RestClient) IterAIDeployments(ctx context.Context, opts ListOptions) iter.Seq2[AIDeployment, error] {
+	return paginate[AIDeployment](ctx, c, c.baseURIBlurite+"/api/v1/blurite/llm-deployments", opts)
+}
+
 func (c *RestClient) GetAIDeployment(ctx context.Context, name string) (*AIDeployment, error) {
 	endpoint := c.baseURIBlurite + "/api/v1/blurite/llm-deployments/by-name/" + url.PathEscape(name)
 
@@ -97,7 +99,7 @@ func (c *RestClient) GetAIDeployment(ctx context.Context, name string) (*AIDeplo
 	}
 
 	var deploy AIDeployment
-	if err = doRequest(c.httpClient, req, &deploy); err != nil {
+	if err = doRequest(c.requester(), req, &deploy); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -110,7 +112,7 @@ func (c *RestClient) DeleteAIDeployment(ctx context.Context, id string) error {
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -118,21 +120,24 @@ func (c *RestClient) DeleteAIDeployment(ctx context.Context, id string) error {
 }
 
 func (c *RestClient) ListAIAPIKeys(ctx context.Context, deploymentID string) ([]AIAPIKey, error) {
-	endpoint := c.baseURIBlurite + "/api/v1/blurite/llm-deployments/" + deploymentID + "/api-keys"
-
-	req, err := c.createAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	keys, err := Collect(c.IterAIAPIKeys(ctx, deploymentID, ListOptions{}))
 	if err != nil {
 		return nil, err
 	}
 
-	var keys []AIAPIKey
-	if err = doRequest(c.httpClient, req, &keys); err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
 	return keys, nil
 }
 
+// IterAIAPIKeys returns an iterator over the API keys of an AI deployment,
+// fetching them one page at a time.
+func (c *RestClient) IterAIAPIKeys(
+	ctx context.Context, deploymentID string, opts ListOptions,
+) iter.Seq2[AIAPIKey, error] {
+	endpoint := c.baseURIBlurite + "/api/v1/blurite/llm-deployments/" + deploymentID + "/api-keys"
+
+	return paginate[AIAPIKey](ctx, c, endpoint, opts)
+}
+
 func (c *RestClient) CreateAIAPIKey(
 	ctx context.Context, deploymentID string, request NewAIAPIKeyRequest,
 ) (*AIAPIKeyWithSecret, error) {
@@ -149,7 +154,7 @@ func (c *RestClient) CreateAIAPIKey(
 	}
 
 	var key AIAPIKeyWithSecret
-	if err = doRequest(c.httpClient, req, &key); err != nil {
+	if err = doRequest(c.requester(), req, &key); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -166,7 +171,7 @@ func (c *RestClient) GetAIAPIKey(ctx context.Context, deploymentID string, name
 	}
 
 	var key AIAPIKey
-	if err = doRequest(c.httpClient, req, &key); err != nil {
+	if err = doRequest(c.requester(), req, &key); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -181,7 +186,7 @@ func (c *RestClient) DeleteAIAPIKey(ctx context.Context, deploymentID string, ke
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -195,7 +200,7 @@ func (c *RestClient) ListAIModels(ctx context.Context) ([]AIModel, error) {
 	}
 
 	var models []AIModel
-	if err = doRequest(c.httpClient, req, &models); err != nil {
+	if err = doRequest(c.requester(), req, &models); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
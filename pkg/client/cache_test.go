@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/httpcache"
+)
+
+// tenantToken signs in the same user in one of their tenants.
+type tenantToken string
+
+func (t tenantToken) Token(context.Context) (public.AuthResult, error) {
+	return public.AuthResult{
+		AccessToken: string(t),
+		Account:     public.Account{HomeAccountID: "oid.home-tid", Realm: string(t)},
+	}, nil
+}
+
+func TestCachePartition(t *testing.T) {
+	home := public.Account{HomeAccountID: "oid.home-tid", Realm: "home-tid"}
+	guest := public.Account{HomeAccountID: "oid.home-tid", Realm: "guest-tid"}
+
+	assert.NotEqual(t, CachePartition(home), CachePartition(guest))
+	assert.Empty(t, CachePartition(public.Account{}), "responses without an account are not cached")
+}
+
+func TestCacheKeepsTenantsApart(t *testing.T) {
+	// the clusters of each tenant are fresh for a minute
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"` + tenant + `-cluster","name":"` + tenant + `"}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	httpClient := &http.Client{Transport: httpcache.New(t.TempDir(), http.DefaultTransport)}
+
+	for _, tenant := range []string{"home-tid", "guest-tid", "home-tid"} {
+		c := New(WithBaseURI(server.URL), WithHTTPClient(httpClient), WithTokenProvider(tenantToken(tenant)))
+
+		clusters, err := c.ListClusters(context.Background())
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		assert.Equal(t, tenant, clusters[0].Name, "the clusters of another tenant are never served")
+	}
+}
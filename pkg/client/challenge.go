@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+
+	"github.com/intility/indev/pkg/authenticator"
+)
+
+const headerWWWAuthenticate = "WWW-Authenticate"
+
+// ErrReauthenticationRequired is matched by errors of requests that the
+// platform rejected with a claims challenge that could not be satisfied
+// without the user, such as in scripts. The user has to sign in again.
+var ErrReauthenticationRequired = authenticator.ErrReauthenticationRequired
+
+// ClaimsChallenger is implemented by token providers that can acquire a
+// token satisfying a claims challenge, such as [authenticator.TokenSource].
+// Requests rejected with a claims challenge are sent once more with the new
+// token.
+type ClaimsChallenger interface {
+	TokenWithClaims(ctx context.Context, claims string) (public.AuthResult, error)
+}
+
+// claimsParameter matches the claims parameter of a Bearer challenge.
+var claimsParameter = regexp.MustCompile(`(?i)\bclaims="([^"]*)"`)
+
+// claimsChallenge returns the decoded claims of the claims challenge in the
+// WWW-Authenticate header of a 401 response, as sent by resources protected
+// by conditional access. The claims are base64-encoded JSON.
+func claimsChallenge(resp *http.Response) (string, bool) {
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		return "", false
+	}
+
+	for _, challenge := range resp.Header.Values(headerWWWAuthenticate) {
+		match := claimsParameter.FindStringSubmatch(challenge)
+		if match == nil || match[1] == "" {
+			continue
+		}
+
+		if strings.HasPrefix(match[1], "{") {
+			return match[1], true
+		}
+
+		for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+			if claims, err := encoding.DecodeString(match[1]); err == nil {
+				return string(claims), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// answerChallenge acquires a token that satisfies the claims challenge of a
+// rejected request, and sends the request once more with it. Responses
+// without a claims challenge are returned as is.
+func (r retryingRequester) answerChallenge(req *http.Request, resp *http.Response) (*http.Response, error) {
+	claims, ok := claimsChallenge(resp)
+	if !ok || r.challenger == nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	discard(resp)
+
+	authContext, cancel := context.WithTimeout(req.Context(), defaultAuthTimeout)
+	defer cancel()
+
+	token, err := r.challenger.TokenWithClaims(authContext, claims)
+	if err != nil {
+		if !errors.Is(err, ErrReauthenticationRequired) {
+			err = fmt.Errorf("%w: %w", ErrReauthenticationRequired, err)
+		}
+
+		return nil, err
+	}
+
+	if err = rewindBody(req); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	return r.doWithRetries(req)
+}
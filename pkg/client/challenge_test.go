@@ -0,0 +1,116 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClaims = `{"access_token":{"acrs":{"essential":true,"value":"c1"}}}`
+
+type fakeChallenger struct {
+	claims []string
+	err    error
+}
+
+func (c *fakeChallenger) TokenWithClaims(_ context.Context, claims string) (public.AuthResult, error) {
+	c.claims = append(c.claims, claims)
+
+	return public.AuthResult{AccessToken: "stepped-up"}, c.err
+}
+
+func claimsHeader() http.Header {
+	encoded := base64.StdEncoding.EncodeToString([]byte(testClaims))
+
+	header := http.Header{}
+	header.Set(headerWWWAuthenticate,
+		`Bearer realm="", authorization_uri="https://login.microsoftonline.com/common/oauth2/authorize", `+
+			`error="insufficient_claims", claims="`+encoded+`"`)
+
+	return header
+}
+
+func TestClaimsChallenge(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: claimsHeader()}
+
+	claims, ok := claimsChallenge(resp)
+	require.True(t, ok)
+	assert.JSONEq(t, testClaims, claims)
+
+	invalidToken := http.Header{}
+	invalidToken.Set(headerWWWAuthenticate, `Bearer error="invalid_token"`)
+
+	_, ok = claimsChallenge(&http.Response{StatusCode: http.StatusUnauthorized, Header: invalidToken})
+	assert.False(t, ok)
+
+	_, ok = claimsChallenge(&http.Response{StatusCode: http.StatusForbidden, Header: claimsHeader()})
+	assert.False(t, ok)
+}
+
+func TestRequesterAnswersClaimsChallenge(t *testing.T) {
+	server, calls, bodies := flakyServer(t, 1, http.StatusUnauthorized, claimsHeader())
+
+	challenger := &fakeChallenger{}
+	requester := testRequester(server.Client(), 3)
+	requester.challenger = challenger
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, bytes.NewReader([]byte("payload")))
+	require.NoError(t, err)
+
+	var result struct{ Name string }
+	require.NoError(t, doRequest(requester, req, &result))
+
+	assert.Equal(t, "ok", result.Name)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []string{"payload", "payload"}, *bodies)
+	assert.Equal(t, []string{testClaims}, challenger.claims)
+	assert.Equal(t, "Bearer stepped-up", req.Header.Get("Authorization"))
+}
+
+func TestRequesterReauthenticationRequired(t *testing.T) {
+	t.Run("challenger fails", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 1, http.StatusUnauthorized, claimsHeader())
+
+		requester := testRequester(server.Client(), 3)
+		requester.challenger = &fakeChallenger{err: errors.New("user cancelled")}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](requester, req, nil)
+		require.ErrorIs(t, err, ErrReauthenticationRequired)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("challenge repeated", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 2, http.StatusUnauthorized, claimsHeader())
+
+		requester := testRequester(server.Client(), 3)
+		requester.challenger = &fakeChallenger{}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](requester, req, nil)
+		require.ErrorIs(t, err, ErrReauthenticationRequired)
+		require.ErrorIs(t, err, ErrUnauthorized)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("no challenger", func(t *testing.T) {
+		server, _, _ := flakyServer(t, 1, http.StatusUnauthorized, claimsHeader())
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](testRequester(server.Client(), 3), req, nil)
+		require.ErrorIs(t, err, ErrReauthenticationRequired)
+	})
+}
@@ -4,31 +4,44 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"github.com/intility/indev/internal/build"
 	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/httpcache"
 )
 
-var ErrClusterNotFound = errors.New("cluster not found")
+var ErrClusterNotFound = fmt.Errorf("cluster %w", ErrNotFound)
 
 const (
-	defaultHTTPTimeout = 10 * time.Second
+	// DefaultHTTPTimeout is the default timeout of a single request attempt.
+	DefaultHTTPTimeout = 10 * time.Second
+
 	defaultAuthTimeout = 5 * time.Minute
 )
 
 type ClusterClient interface {
 	ListClusters(ctx context.Context) (ClusterList, error)
+	IterClusters(ctx context.Context, opts ListOptions) iter.Seq2[Cluster, error]
 	GetCluster(ctx context.Context, name string) (*Cluster, error)
 	GetClusterStatus(ctx context.Context, clusterID string) (*Cluster, error)
 	CreateCluster(ctx context.Context, request NewClusterRequest) (*Cluster, error)
+	UpdateCluster(ctx context.Context, clusterID string, request UpdateClusterRequest) (*Cluster, error)
+	ListClusterVersions(ctx context.Context) ([]ClusterVersion, error)
 	DeleteCluster(ctx context.Context, name string) error
+	ListNodePools(ctx context.Context, clusterID string) (NodePools, error)
+	AddNodePool(ctx context.Context, clusterID string, pool NodePool) (*NodePool, error)
+	UpdateNodePool(ctx context.Context, clusterID string, pool NodePool) (*NodePool, error)
+	DeleteNodePool(ctx context.Context, clusterID string, poolID string) error
 	GetClusterMembers(ctx context.Context, clusterID string) ([]ClusterMember, error)
 	AddClusterMember(ctx context.Context, clusterID string, request []AddClusterMemberRequest) error
 	RemoveClusterMember(ctx context.Context, clusterID string, memberID string) error
@@ -44,6 +57,7 @@ type MeClient interface {
 
 type TeamsClient interface {
 	ListTeams(ctx context.Context) ([]Team, error)
+	IterTeams(ctx context.Context, opts ListOptions) iter.Seq2[Team, error]
 	GetTeam(ctx context.Context, name string) (*Team, error)
 	GetTeamMembers(ctx context.Context, teamID string) ([]TeamMember, error)
 	CreateTeam(ctx context.Context, request NewTeamRequest) (*Team, error)
@@ -57,12 +71,14 @@ type MemberClient interface {
 
 type UserClient interface {
 	ListUsers(ctx context.Context) ([]User, error)
+	IterUsers(ctx context.Context, opts ListOptions) iter.Seq2[User, error]
 	GetUser(ctx context.Context, upn string) (*User, error)
 }
 
 type AIClient interface {
 	ListAIModels(ctx context.Context) ([]AIModel, error)
 	ListAIDeployments(ctx context.Context) ([]AIDeployment, error)
+	IterAIDeployments(ctx context.Context, opts ListOptions) iter.Seq2[AIDeployment, error]
 	GetAIDeployment(ctx context.Context, name string) (*AIDeployment, error)
 	CreateAIDeployment(ctx context.Context, request NewAIDeploymentRequest) (*AIDeployment, error)
 	DeleteAIDeployment(ctx context.Context, id string) error
@@ -70,11 +86,20 @@ type AIClient interface {
 
 type AIAPIKeyClient interface {
 	ListAIAPIKeys(ctx context.Context, deploymentID string) ([]AIAPIKey, error)
+	IterAIAPIKeys(ctx context.Context, deploymentID string, opts ListOptions) iter.Seq2[AIAPIKey, error]
 	GetAIAPIKey(ctx context.Context, deploymentID string, name string) (*AIAPIKey, error)
 	CreateAIAPIKey(ctx context.Context, deploymentID string, request NewAIAPIKeyRequest) (*AIAPIKeyWithSecret, error)
 	DeleteAIAPIKey(ctx context.Context, deploymentID string, keyID string) error
 }
 
+type PullSecretClient interface {
+	ListPullSecrets(ctx context.Context) ([]PullSecret, error)
+	IterPullSecrets(ctx context.Context, opts ListOptions) iter.Seq2[PullSecret, error]
+	GetPullSecret(ctx context.Context, name string) (*PullSecret, error)
+	CreatePullSecret(ctx context.Context, request NewPullSecretRequest) (*PullSecret, error)
+	DeletePullSecret(ctx context.Context, id string) error
+}
+
 type Client interface {
 	ClusterClient
 	IntegrationClient
@@ -84,6 +109,7 @@ type Client interface {
 	MemberClient
 	AIClient
 	AIAPIKeyClient
+	PullSecretClient
 }
 
 type RestClientOption func(*RestClient)
@@ -92,34 +118,100 @@ type RestClient struct {
 	baseURI        string
 	baseURIBlurite string
 	httpClient     *http.Client
-	authenticator  *authenticator.Authenticator
+	tokenProvider  TokenProvider
+	product        string
+	retryPolicy    RetryPolicy
+	timeout        time.Duration
+	debug          io.Writer
 }
 
 var _ Client = New()
 
 func New(options ...RestClientOption) *RestClient {
+	// the timeout is applied to each attempt by the retrying requester,
+	// so the http client itself has none
 	client := &http.Client{
 		Transport: otelhttp.NewTransport(http.DefaultTransport),
-		Timeout:   defaultHTTPTimeout,
 	}
 	restClient := &RestClient{
 		baseURI:        build.PlatformAPIHost(),
 		baseURIBlurite: build.PlatformAPIHostBlurite(),
 		httpClient:     client,
-		authenticator:  authenticator.NewAuthenticator(authenticator.ConfigFromBuildProps()),
+		tokenProvider: authenticator.NewTokenSource(
+			authenticator.NewAuthenticator(authenticator.ConfigFromBuildProps()),
+		),
+		product:     "",
+		retryPolicy: DefaultRetryPolicy(),
+		timeout:     DefaultHTTPTimeout,
+		debug:       nil,
 	}
 
-	for _, opt := range options {
-		opt(restClient)
-	}
+	restClient.Configure(options...)
 
 	return restClient
 }
 
+// Configure applies the given options to an existing client. It allows
+// settings that are only known after flag parsing to be applied to a client
+// that has already been handed out.
+func (c *RestClient) Configure(options ...RestClientOption) {
+	for _, opt := range options {
+		opt(c)
+	}
+}
+
 //goland:noinspection GoUnusedExportedFunction
-func WithAuthenticator(authenticator *authenticator.Authenticator) RestClientOption {
+func WithAuthenticator(auth *authenticator.Authenticator) RestClientOption {
+	return func(client *RestClient) {
+		client.tokenProvider = authenticator.NewTokenSource(auth)
+	}
+}
+
+// WithTokenSource configures the client to take its access tokens from the
+// given token source. Sharing a token source between the client and the
+// pre-run hooks lets a command acquire its token only once.
+func WithTokenSource(tokenSource *authenticator.TokenSource) RestClientOption {
+	return WithTokenProvider(tokenSource)
+}
+
+// WithTokenProvider configures the client to authenticate its requests with
+// tokens from the given provider.
+func WithTokenProvider(provider TokenProvider) RestClientOption {
+	return func(client *RestClient) {
+		client.tokenProvider = provider
+	}
+}
+
+// WithBaseURIs overrides the addresses of the platform and Blurite APIs, for
+// instance to target a sandbox.
+func WithBaseURIs(platform, blurite string) RestClientOption {
+	return func(client *RestClient) {
+		WithBaseURI(platform)(client)
+		WithBluriteBaseURI(blurite)(client)
+	}
+}
+
+// WithBaseURI overrides the address of the platform API.
+func WithBaseURI(uri string) RestClientOption {
+	return func(client *RestClient) {
+		client.baseURI = strings.TrimSuffix(uri, "/")
+	}
+}
+
+// WithBluriteBaseURI overrides the address of the Blurite API, which serves
+// the AI endpoints.
+func WithBluriteBaseURI(uri string) RestClientOption {
+	return func(client *RestClient) {
+		client.baseURIBlurite = strings.TrimSuffix(uri, "/")
+	}
+}
+
+// WithUserAgent identifies the application that uses the client, such as
+// "my-operator/1.2". It is sent in front of the indev version in the
+// User-Agent header of every request.
+func WithUserAgent(product string) RestClientOption {
 	return func(client *RestClient) {
-		client.authenticator = authenticator
+		client.product = product
 	}
 }
 
@@ -144,31 +236,57 @@ func (c *RestClient) createAuthenticatedRequest(
 	authContext, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
 	defer cancel()
 
-	authResult, err := c.authenticator.Authenticate(authContext)
+	authResult, err := c.tokenProvider.Token(authContext)
 	if err != nil {
 		return nil, fmt.Errorf("could not authenticate: %w", err)
 	}
 
+	// responses are cached per account and tenant, so that switching
+	// accounts or tenants never shows data fetched by another one
+	req = req.WithContext(httpcache.WithPartition(ctx, CachePartition(authResult.Account)))
+
 	req.Header.Set("Authorization", "Bearer "+authResult.AccessToken)
+	req.Header.Set(headerUserAgent, strings.TrimSpace(c.product+" "+userAgent()))
+
+	// the request ID lets the platform team find the request in their logs
+	req.Header.Set(headerRequestID, uuid.NewString())
+
+	// POST requests carry an idempotency key so that they can be retried
+	// without creating the resource twice
+	if method == http.MethodPost {
+		req.Header.Set(headerIdempotencyKey, uuid.NewString())
+	}
 
 	return req, nil
 }
 
-func (c *RestClient) ListClusters(ctx context.Context) (ClusterList, error) {
-	var clusters ClusterList
-
-	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/clusters", nil)
-	if err != nil {
-		return clusters, err
+// CachePartition returns the cache partition of the responses fetched by the
+// account. The home account ID is the same in every tenant of a user, so the
+// realm, the tenant the token was issued for, is part of the partition. It is
+// empty for tokens without an account, whose responses are not cached.
+func CachePartition(account public.Account) string {
+	if account.HomeAccountID == "" {
+		return ""
 	}
 
-	if err = doRequest(c.httpClient, req, &clusters); err != nil {
-		return clusters, fmt.Errorf("request failed: %w", err)
+	return account.HomeAccountID + "/" + account.Realm
+}
+
+func (c *RestClient) ListClusters(ctx context.Context) (ClusterList, error) {
+	clusters, err := Collect(c.IterClusters(ctx, ListOptions{}))
+	if err != nil {
+		return nil, err
 	}
 
 	return clusters, nil
 }
 
+// IterClusters returns an iterator over all clusters, fetching them one page
+// at a time.
+func (c *RestClient) IterClusters(ctx context.Context, opts ListOptions) iter.Seq2[Cluster, error] {
+	return paginate[Cluster](ctx, c, c.baseURI+"/api/v1/clusters", opts)
+}
+
 func (c *RestClient) CreateCluster(ctx context.Context, request NewClusterRequest) (*Cluster, error) {
 	body, err := json.Marshal(request)
 	if err != nil {
@@ -181,7 +299,7 @@ func (c *RestClient) CreateCluster(ctx context.Context, request NewClusterReques
 	}
 
 	var result Cluster
-	if err = doRequest(c.httpClient, req, &result); err != nil {
+	if err = doRequest(c.requester(), req, &result); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -195,7 +313,7 @@ func (c *RestClient) GetCluster(ctx context.Context, name string) (*Cluster, err
 	}
 
 	var cluster Cluster
-	if err = doRequest(c.httpClient, req, &cluster); err != nil {
+	if err = doRequest(c.requester(), req, &cluster); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -213,20 +331,56 @@ func (c *RestClient) GetClusterStatus(ctx context.Context, clusterID string) (*C
 	}
 
 	var cluster Cluster
-	if err = doRequest(c.httpClient, req, &cluster); err != nil {
+	if err = doRequest(c.requester(), req, &cluster); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	return &cluster, nil
 }
 
+func (c *RestClient) UpdateCluster(ctx context.Context, clusterID string, request UpdateClusterRequest) (*Cluster, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal request: %w", err)
+	}
+
+	endpoint := c.baseURI + "/api/v1/clusters/" + clusterID
+
+	req, err := c.createAuthenticatedRequest(ctx, "PATCH", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var result Cluster
+	if err = doRequest(c.requester(), req, &result); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *RestClient) ListClusterVersions(ctx context.Context) ([]ClusterVersion, error) {
+	var versions []ClusterVersion
+
+	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/clusters/versions", nil)
+	if err != nil {
+		return versions, err
+	}
+
+	if err = doRequest(c.requester(), req, &versions); err != nil {
+		return versions, fmt.Errorf("request failed: %w", err)
+	}
+
+	return versions, nil
+}
+
 func (c *RestClient) DeleteCluster(ctx context.Context, id string) error {
 	req, err := c.createAuthenticatedRequest(ctx, "DELETE", c.baseURI+"/api/v1/clusters/"+id, nil)
 	if err != nil {
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -241,7 +395,7 @@ func (c *RestClient) GetClusterMembers(ctx context.Context, clusterID string) ([
 		return members, err
 	}
 
-	if err = doRequest(c.httpClient, req, &members); err != nil {
+	if err = doRequest(c.requester(), req, &members); err != nil {
 		return members, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -263,7 +417,7 @@ func (c *RestClient) AddClusterMember(ctx context.Context, clusterID string, req
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -278,7 +432,80 @@ func (c *RestClient) RemoveClusterMember(ctx context.Context, clusterID string,
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	return nil
+}
+
+func (c *RestClient) ListNodePools(ctx context.Context, clusterID string) (NodePools, error) {
+	var pools NodePools
+
+	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/clusters/"+clusterID+"/nodepools", nil)
+	if err != nil {
+		return pools, err
+	}
+
+	if err = doRequest(c.requester(), req, &pools); err != nil {
+		return pools, fmt.Errorf("request failed: %w", err)
+	}
+
+	return pools, nil
+}
+
+func (c *RestClient) AddNodePool(ctx context.Context, clusterID string, pool NodePool) (*NodePool, error) {
+	body, err := json.Marshal(pool)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal request: %w", err)
+	}
+
+	endpoint := c.baseURI + "/api/v1/clusters/" + clusterID + "/nodepools"
+
+	req, err := c.createAuthenticatedRequest(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var result NodePool
+	if err = doRequest(c.requester(), req, &result); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// UpdateNodePool replaces the settings of the node pool with the ID of pool.
+func (c *RestClient) UpdateNodePool(ctx context.Context, clusterID string, pool NodePool) (*NodePool, error) {
+	body, err := json.Marshal(pool)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal request: %w", err)
+	}
+
+	endpoint := c.baseURI + "/api/v1/clusters/" + clusterID + "/nodepools/" + pool.ID
+
+	req, err := c.createAuthenticatedRequest(ctx, "PUT", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var result NodePool
+	if err = doRequest(c.requester(), req, &result); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *RestClient) DeleteNodePool(ctx context.Context, clusterID string, poolID string) error {
+	endpoint := c.baseURI + "/api/v1/clusters/" + clusterID + "/nodepools/" + poolID
+
+	req, err := c.createAuthenticatedRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
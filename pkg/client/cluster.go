@@ -1,6 +1,10 @@
 package client
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Cluster struct {
 	ID         string        `json:"id"`
@@ -12,6 +16,8 @@ type Cluster struct {
 	NodePools  NodePools     `json:"nodePools"`
 	Status     ClusterStatus `json:"status"`
 	Roles      []string      `json:"roles"`
+	// PullSecretRef is the ID of the pull secret of the cluster, if any.
+	PullSecretRef string `json:"pullSecretRef,omitempty"`
 }
 
 type ClusterStatus struct {
@@ -41,6 +47,22 @@ type NewClusterRequest struct {
 	PullSecretRef  *string   `json:"pullSecretRef,omitempty"`
 }
 
+// ClusterVersion is an OpenShift version that the platform offers.
+type ClusterVersion struct {
+	Version string `json:"version" yaml:"version"`
+	// Default is set for the version of new clusters that do not ask for one.
+	Default bool `json:"default" yaml:"default"`
+	// Supported is cleared once the version has reached its end of life.
+	Supported bool       `json:"supported"           yaml:"supported"`
+	EndOfLife *time.Time `json:"endOfLife,omitempty" yaml:"endOfLife,omitempty"`
+}
+
+// UpdateClusterRequest changes the settings of a cluster. Empty fields are
+// left unchanged.
+type UpdateClusterRequest struct {
+	Version string `json:"version,omitempty"`
+}
+
 type NodePools []NodePool
 
 type NodePool struct {
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/intility/indev/internal/redact"
+)
+
+// maxDebugBodySize limits how much of a body is printed by the debug dump.
+const maxDebugBodySize = 16 * 1024
+
+// WithDebugWriter makes the client print every request attempt and its
+// response to w, with secrets redacted. A nil writer disables the dump.
+func WithDebugWriter(w io.Writer) RestClientOption {
+	return func(client *RestClient) {
+		client.debug = w
+	}
+}
+
+// dumpRequest prints the request line, headers and body of req. The body is
+// read from GetBody, so that req itself can still be sent.
+func dumpRequest(w io.Writer, req *http.Request) {
+	_, _ = fmt.Fprintf(w, "> %s %s\n", req.Method, redact.URL(req.URL))
+	dumpHeader(w, "> ", req.Header)
+
+	if req.GetBody == nil {
+		return
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	data, _ := io.ReadAll(body)
+	dumpBody(w, "> ", data)
+}
+
+// dumpResponse prints the outcome of a request attempt. The response body is
+// read and replaced, so that the caller can still consume it.
+func dumpResponse(w io.Writer, resp *http.Response, err error, elapsed time.Duration) {
+	elapsed = elapsed.Round(time.Millisecond)
+
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "< request failed after %s: %v\n\n", elapsed, err)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "< %s %s (%s)\n", resp.Proto, resp.Status, elapsed)
+	dumpHeader(w, "< ", resp.Header)
+
+	data, readErr := io.ReadAll(resp.Body)
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), errReader{readErr}), Closer: resp.Body}
+
+	dumpBody(w, "< ", data)
+	_, _ = fmt.Fprintln(w)
+}
+
+func dumpHeader(w io.Writer, prefix string, header http.Header) {
+	redacted := redact.Header(header)
+
+	keys := make([]string, 0, len(redacted))
+	for key := range redacted {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range redacted[key] {
+			_, _ = fmt.Fprintf(w, "%s%s: %s\n", prefix, key, value)
+		}
+	}
+}
+
+func dumpBody(w io.Writer, prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	redacted := redact.JSON(body)
+	truncated := len(redacted) - maxDebugBodySize
+
+	if truncated > 0 {
+		redacted = redacted[:maxDebugBodySize]
+	}
+
+	_, _ = fmt.Fprintf(w, "%s\n%s%s\n", strings.TrimSpace(prefix), prefix, redacted)
+
+	if truncated > 0 {
+		_, _ = fmt.Fprintf(w, "%s... %d more bytes\n", prefix, truncated)
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader returns err, or io.EOF when err is nil. It reports a failure to
+// read a body during the dump to the caller that reads it afterwards.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return 0, io.EOF
+}
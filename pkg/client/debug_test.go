@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/authenticator"
+)
+
+func TestDebugWriter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1","name":"ci","prefix":"sk-abc","key":"sk-abcdef"}`))
+	}))
+	defer server.Close()
+
+	var debug bytes.Buffer
+
+	restClient := testClient(server.URL)
+	restClient.Configure(WithDebugWriter(&debug))
+
+	key, err := restClient.CreateAIAPIKey(context.Background(), "deployment", NewAIAPIKeyRequest{Name: "ci", TTLDays: 7})
+	require.NoError(t, err)
+
+	assert.Equal(t, "sk-abcdef", key.Key, "the caller still receives the secret")
+
+	dump := debug.String()
+	assert.Contains(t, dump, "> POST "+server.URL+"/api/v1/blurite/llm-deployments/deployment/api-keys")
+	assert.Contains(t, dump, `> {"name":"ci","ttlDays":7}`)
+	assert.Contains(t, dump, "> Authorization: REDACTED")
+	assert.Contains(t, dump, "< HTTP/1.1 201 Created")
+	assert.Contains(t, dump, `"key":"REDACTED"`)
+	assert.NotContains(t, dump, authenticator.PlaceholderToken)
+	assert.NotContains(t, dump, "sk-abcdef")
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var requestIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get(headerRequestID))
+
+		assert.Contains(t, r.Header.Get(headerUserAgent), "indev")
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := testClient(server.URL).GetMe(context.Background())
+	require.Error(t, err)
+
+	require.Len(t, requestIDs, 1)
+	assert.NotEmpty(t, requestIDs[0])
+	assert.Contains(t, err.Error(), "(request ID: "+requestIDs[0]+")")
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgents []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get(headerUserAgent))
+
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	restClient := New(
+		WithBaseURI(server.URL),
+		WithTokenProvider(StaticToken("token")),
+		WithUserAgent("operator/1.2"),
+	)
+
+	_, err := restClient.GetMe(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, userAgents, 1)
+	assert.Equal(t, "operator/1.2 "+userAgent(), userAgents[0])
+}
@@ -0,0 +1,30 @@
+// Package client is a Go client for the Intility Developer Platform and
+// Blurite APIs. It is used by the indev CLI, and can be used on its own by
+// services and operators that manage platform resources:
+//
+//	platformClient := client.New(
+//		client.WithTokenProvider(tokenProvider),
+//		client.WithUserAgent("my-operator/1.0"),
+//	)
+//
+//	clusters, err := platformClient.ListClusters(ctx)
+//
+// Requests are authenticated with tokens from a [TokenProvider]. Use
+// [StaticToken] for a token acquired elsewhere, an [authenticator.TokenSource]
+// with an [authenticator.Authenticator] to sign in users, or one with
+// [authenticator.ClientCredentials] to sign in a service principal.
+//
+// Operations on members, statuses and API keys take resource IDs. Use
+// [ResolveClusterID], [ResolveTeamID] and [ResolveAIDeploymentID] to look
+// them up by name. Errors wrap sentinel errors such as [ErrNotFound] and
+// [ErrConflict], and failed requests are reported as a [*RequestError].
+//
+// # Stability
+//
+// The exported API of this package and of package authenticator follows
+// semantic versioning together with the indev releases: within a major
+// version, exported identifiers are not removed or changed in incompatible
+// ways. New methods may be added to the [Client] interface and its parts in
+// minor releases, so code outside this module should not implement them.
+// Identifiers marked as deprecated are kept until the next major version.
+package client
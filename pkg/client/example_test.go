@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/sandbox"
+)
+
+// newExampleClient creates a client for a sandbox, which stands in for the
+// platform. Outside of examples, the default base URIs target the platform.
+func newExampleClient() (*client.RestClient, func()) {
+	server := httptest.NewServer(sandbox.New(sandbox.WithProvisioningDelay(0)))
+
+	platformClient := client.New(
+		client.WithBaseURI(server.URL),
+		client.WithBluriteBaseURI(server.URL),
+		client.WithTokenProvider(client.StaticToken("access-token")),
+		client.WithUserAgent("example/1.0"),
+	)
+
+	return platformClient, server.Close
+}
+
+func Example() {
+	platformClient, closeServer := newExampleClient()
+	defer closeServer()
+
+	ctx := context.Background()
+	replicas := 2
+
+	cluster, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{
+		Name: "demo",
+		NodePools: client.NodePools{
+			{Name: "default", Preset: "balanced", Replicas: &replicas},
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	fmt.Println("created", cluster.Name)
+
+	for cluster, err := range platformClient.IterClusters(ctx, client.ListOptions{}) {
+		if err != nil {
+			fmt.Println(err)
+
+			return
+		}
+
+		fmt.Println("listed", cluster.Name)
+	}
+
+	// Output:
+	// created demo
+	// listed demo
+}
+
+func ExampleResolveTeamID() {
+	platformClient, closeServer := newExampleClient()
+	defer closeServer()
+
+	ctx := context.Background()
+
+	teamID, err := client.ResolveTeamID(ctx, platformClient, "platform")
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	members, err := platformClient.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	for _, member := range members {
+		fmt.Println(member.Subject.Name, member.Roles)
+	}
+
+	_, err = client.ResolveTeamID(ctx, platformClient, "missing")
+	fmt.Println(errors.Is(err, client.ErrNotFound))
+
+	// Output:
+	// Sandbox User [owner]
+	// true
+}
+
+func ExampleStaticToken() {
+	// a token acquired elsewhere, for instance by a workload identity
+	platformClient := client.New(client.WithTokenProvider(client.StaticToken("access-token")))
+
+	_ = platformClient
+}
@@ -21,7 +21,7 @@ func (c *RestClient) ListIntegrationInstances(ctx context.Context) ([]Integratio
 		return instances, err
 	}
 
-	if err = doRequest(c.httpClient, req, &instances); err != nil {
+	if err = doRequest(c.requester(), req, &instances); err != nil {
 		return instances, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -20,7 +20,7 @@ func (c *RestClient) GetMe(ctx context.Context) (Me, error) {
 		return me, err
 	}
 
-	if err = doRequest(c.httpClient, req, &me); err != nil {
+	if err = doRequest(c.requester(), req, &me); err != nil {
 		return me, fmt.Errorf("request failed: %w", err)
 	}
 
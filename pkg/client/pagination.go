@@ -0,0 +1,221 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerLink              = "Link"
+	headerContinuationToken = "X-Continuation-Token"
+
+	queryPageSize          = "pageSize"
+	queryContinuationToken = "continuationToken"
+)
+
+var (
+	errPaginationLoop    = errors.New("pagination did not advance")
+	errForeignPageOrigin = errors.New("next page is hosted on a different origin")
+)
+
+// ListOptions controls how list endpoints are paginated.
+type ListOptions struct {
+	// PageSize is the number of items requested per page. Zero lets the
+	// server decide.
+	PageSize int
+	// Limit stops the iteration after this many items. Zero means no limit.
+	Limit int
+}
+
+// pageEnvelope is the paginated response body. Endpoints that do not support
+// pagination return a plain JSON array instead.
+type pageEnvelope[T any] struct {
+	Items             []T    `json:"items"`
+	ContinuationToken string `json:"continuationToken,omitempty"`
+}
+
+// paginate returns an iterator over all items of a list endpoint. The next
+// page is located using the "next" relation of the Link header, or a
+// continuation token in either the response body or the
+// X-Continuation-Token header. Pages are only requested as the iteration
+// progresses, and an error stops the iteration after being yielded.
+func paginate[T any](ctx context.Context, c *RestClient, endpoint string, opts ListOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		next, err := url.Parse(endpoint)
+		if err != nil {
+			yield(zero, fmt.Errorf("could not parse endpoint: %w", err))
+			return
+		}
+
+		if pageSize := opts.pageSize(); pageSize > 0 {
+			query := next.Query()
+			query.Set(queryPageSize, strconv.Itoa(pageSize))
+			next.RawQuery = query.Encode()
+		}
+
+		seen := 0
+		visited := make(map[string]struct{})
+
+		for next != nil {
+			if _, ok := visited[next.String()]; ok {
+				yield(zero, errPaginationLoop)
+				return
+			}
+
+			visited[next.String()] = struct{}{}
+
+			var items []T
+
+			items, next, err = fetchPage[T](ctx, c, next)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+
+				seen++
+				if opts.Limit > 0 && seen >= opts.Limit {
+					return
+				}
+			}
+		}
+	}
+}
+
+// pageSize returns the page size to request, which never exceeds the limit.
+func (o ListOptions) pageSize() int {
+	if o.Limit > 0 && (o.PageSize <= 0 || o.PageSize > o.Limit) {
+		return o.Limit
+	}
+
+	return max(o.PageSize, 0)
+}
+
+// fetchPage requests a single page and returns its items along with the URL
+// of the next page, which is nil on the last page.
+func fetchPage[T any](ctx context.Context, c *RestClient, pageURL *url.URL) ([]T, *url.URL, error) {
+	req, err := c.createAuthenticatedRequest(ctx, "GET", pageURL.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var body json.RawMessage
+
+	header, err := doRequestHeader(c.requester(), req, &body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	var page pageEnvelope[T]
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &page.Items)
+	} else {
+		err = json.Unmarshal(body, &page)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not decode response: %w", err)
+	}
+
+	next, err := nextPageURL(pageURL, header, page.ContinuationToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return page.Items, next, nil
+}
+
+func nextPageURL(current *url.URL, header http.Header, bodyToken string) (*url.URL, error) {
+	if link := nextLink(header.Values(headerLink)); link != "" {
+		next, err := current.Parse(link)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse next page link: %w", err)
+		}
+
+		// the bearer token must not be sent to another host
+		if next.Scheme != current.Scheme || next.Host != current.Host {
+			return nil, errForeignPageOrigin
+		}
+
+		return next, nil
+	}
+
+	token := bodyToken
+	if token == "" {
+		token = header.Get(headerContinuationToken)
+	}
+
+	if token == "" {
+		return nil, nil
+	}
+
+	next := *current
+	query := next.Query()
+	query.Set(queryContinuationToken, token)
+	next.RawQuery = query.Encode()
+
+	return &next, nil
+}
+
+// nextLink returns the target of the "next" relation in RFC 8288 Link
+// header values, or an empty string if there is none.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+
+				for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// Collect drains an iterator into a slice, stopping at the first error.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	items := make([]T, 0)
+
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
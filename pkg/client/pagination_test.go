@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/authenticator"
+)
+
+func testClient(baseURI string) *RestClient {
+	return New(
+		WithTokenSource(authenticator.NewTokenSource(authenticator.StaticAcquirer{})),
+		WithRetries(0),
+		WithBaseURIs(baseURI, baseURI),
+	)
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("plain array is a single page", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer "+authenticator.PlaceholderToken, r.Header.Get("Authorization"))
+			assert.Empty(t, r.URL.Query().Get(queryPageSize))
+			_, _ = w.Write([]byte(`[{"name":"alice"},{"name":"bob"}]`))
+		}))
+		defer server.Close()
+
+		users, err := testClient(server.URL).ListUsers(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("follows continuation tokens", func(t *testing.T) {
+		var tokens []string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get(queryContinuationToken)
+			tokens = append(tokens, token)
+
+			assert.Equal(t, "2", r.URL.Query().Get(queryPageSize))
+
+			switch token {
+			case "":
+				_, _ = w.Write([]byte(`{"items":[{"name":"a"},{"name":"b"}],"continuationToken":"page2"}`))
+			case "page2":
+				w.Header().Set(headerContinuationToken, "page3")
+				_, _ = w.Write([]byte(`{"items":[{"name":"c"},{"name":"d"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"items":[{"name":"e"}]}`))
+			}
+		}))
+		defer server.Close()
+
+		teams, err := Collect(testClient(server.URL).IterTeams(context.Background(), ListOptions{PageSize: 2}))
+
+		require.NoError(t, err)
+		assert.Len(t, teams, 5)
+		assert.Equal(t, []string{"", "page2", "page3"}, tokens)
+	})
+
+	t.Run("follows Link headers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set(headerLink, `</api/v1/clusters?page=2>; rel="next", </api/v1/clusters>; rel="first"`)
+				_, _ = w.Write([]byte(`[{"name":"one"}]`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`[{"name":"two"}]`))
+		}))
+		defer server.Close()
+
+		clusters, err := testClient(server.URL).ListClusters(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, clusters, 2)
+		assert.Equal(t, "two", clusters[1].Name)
+	})
+
+	t.Run("stops fetching pages at the limit", func(t *testing.T) {
+		requests := 0
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+
+			assert.Equal(t, "3", r.URL.Query().Get(queryPageSize))
+			_, _ = fmt.Fprintf(w, `{"items":[{"name":"a"},{"name":"b"},{"name":"c"}],"continuationToken":"%d"}`, requests)
+		}))
+		defer server.Close()
+
+		users, err := Collect(testClient(server.URL).IterUsers(
+			context.Background(), ListOptions{PageSize: 10, Limit: 3},
+		))
+
+		require.NoError(t, err)
+		assert.Len(t, users, 3)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("stops when the next page does not advance", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"items":[{"name":"a"}],"continuationToken":"same"}`))
+		}))
+		defer server.Close()
+
+		_, err := testClient(server.URL).ListUsers(context.Background())
+
+		require.ErrorIs(t, err, errPaginationLoop)
+	})
+
+	t.Run("refuses to follow links to another host", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(headerLink, `<https://example.com/api/v1/users?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"name":"a"}]`))
+		}))
+		defer server.Close()
+
+		_, err := testClient(server.URL).ListUsers(context.Background())
+
+		require.ErrorIs(t, err, errForeignPageOrigin)
+	})
+
+	t.Run("yields request errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		_, err := testClient(server.URL).ListAIDeployments(context.Background())
+
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{name: "no header", values: nil, want: ""},
+		{name: "next only", values: []string{`<https://a/b?page=2>; rel="next"`}, want: "https://a/b?page=2"},
+		{name: "multiple relations", values: []string{`<https://a/1>; rel="prev", <https://a/3>; rel="next"`}, want: "https://a/3"},
+		{name: "unquoted rel", values: []string{`</b?page=2>; rel=next`}, want: "/b?page=2"},
+		{name: "space separated rels", values: []string{`</b>; rel="last next"`}, want: "/b"},
+		{name: "no next", values: []string{`</b>; rel="prev"`}, want: ""},
+		{name: "multiple header values", values: []string{`</a>; rel="prev"`, `</c>; rel="next"`}, want: "/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextLink(tt.values))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"time"
+)
+
+// PullSecret holds the credentials that clusters use to pull images from
+// private registries. The credentials themselves are never returned by the
+// platform.
+type PullSecret struct {
+	ID         string    `json:"id"         yaml:"id"`
+	Name       string    `json:"name"       yaml:"name"`
+	Registries []string  `json:"registries" yaml:"registries"`
+	CreatedAt  time.Time `json:"createdAt"  yaml:"createdAt"`
+}
+
+type NewPullSecretRequest struct {
+	Name string `json:"name"`
+	// DockerConfigJSON is a .dockerconfigjson document with the credentials
+	// of each registry. It is base64 encoded on the wire.
+	DockerConfigJSON []byte `json:"dockerConfigJson"`
+}
+
+func (c *RestClient) ListPullSecrets(ctx context.Context) ([]PullSecret, error) {
+	secrets, err := Collect(c.IterPullSecrets(ctx, ListOptions{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// IterPullSecrets returns an iterator over all pull secrets, fetching them
+// one page at a time.
+func (c *RestClient) IterPullSecrets(ctx context.Context, opts ListOptions) iter.Seq2[PullSecret, error] {
+	return paginate[PullSecret](ctx, c, c.baseURI+"/api/v1/pullsecrets", opts)
+}
+
+func (c *RestClient) GetPullSecret(ctx context.Context, name string) (*PullSecret, error) {
+	endpoint := c.baseURI + "/api/v1/pullsecrets/by-name/" + url.PathEscape(name)
+
+	req, err := c.createAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret PullSecret
+	if err = doRequest(c.requester(), req, &secret); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	return &secret, nil
+}
+
+func (c *RestClient) CreatePullSecret(ctx context.Context, request NewPullSecretRequest) (*PullSecret, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal request: %w", err)
+	}
+
+	req, err := c.createAuthenticatedRequest(ctx, "POST", c.baseURI+"/api/v1/pullsecrets", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var secret PullSecret
+	if err = doRequest(c.requester(), req, &secret); err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	return &secret, nil
+}
+
+func (c *RestClient) DeletePullSecret(ctx context.Context, id string) error {
+	req, err := c.createAuthenticatedRequest(ctx, "DELETE", c.baseURI+"/api/v1/pullsecrets/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	return nil
+}
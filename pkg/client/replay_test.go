@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/cassette"
+)
+
+func replayClient(t *testing.T, name string) *RestClient {
+	t.Helper()
+
+	recorder, err := cassette.New("testdata/cassettes/"+name, cassette.ModeReplay, nil)
+	require.NoError(t, err)
+
+	return New(
+		WithHTTPClient(&http.Client{Transport: recorder}),
+		WithTokenSource(authenticator.NewTokenSource(authenticator.NewStaticAcquirer(recorder.TenantID(), "replay"))),
+		WithRetries(0),
+	)
+}
+
+func TestReplayClusters(t *testing.T) {
+	c := replayClient(t, "clusters.yaml")
+	ctx := context.Background()
+
+	clusters, err := c.ListClusters(ctx)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, "dev", clusters[0].Name)
+	assert.True(t, clusters[0].Status.Ready.Status)
+	require.Len(t, clusters[0].NodePools, 1)
+	assert.Equal(t, 2, *clusters[0].NodePools[0].Replicas)
+
+	cluster, err := c.GetCluster(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", cluster.ID)
+
+	_, err = c.GetCluster(ctx, "prod")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	assert.Equal(t, "req-42", reqErr.RequestID)
+	assert.Equal(t, "cluster prod does not exist", reqErr.Problem.Detail)
+}
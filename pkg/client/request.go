@@ -2,23 +2,132 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/intility/indev/internal/build"
+)
+
+// maxErrorBodySize limits how much of an error response body is read into
+// memory when building a RequestError.
+const maxErrorBodySize = 64 * 1024
+
+const contentTypeProblemJSON = "application/problem+json"
+
+// Sentinel errors describing the kind of failure reported by the platform.
+// They can be matched against any error returned by the client using
+// errors.Is, regardless of how the error has been wrapped.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+const (
+	headerRequestID = "X-Request-ID"
+	headerUserAgent = "User-Agent"
 )
 
+// requestIDHeaders lists the response headers that may carry the server
+// assigned request ID, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Correlation-Id"}
+
+// ProblemDetails is an RFC 9457 problem details object as returned by the
+// platform API for failed requests.
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// RequestError is returned when the platform responds with a non-2xx status
+// code. Use errors.As to inspect the details, or errors.Is with one of the
+// sentinel errors (ErrNotFound, ErrConflict, ...) to branch on the kind of
+// failure.
 type RequestError struct {
-	Message string
+	Message    string
+	StatusCode int
+	Method     string
+	URL        string
+	RequestID  string
+	Problem    *ProblemDetails
+	// Claims are the claims of the challenge of a 401 response, which a new
+	// token must satisfy. They are empty for other responses.
+	Claims string
+	// Err is the error that occurred while reading the response body, if
+	// any. The error still describes the response without its body.
+	Err error
 }
 
 func (e *RequestError) Error() string {
-	return e.Message
+	if e.RequestID == "" {
+		return e.Message
+	}
+
+	return e.Message + " (request ID: " + e.RequestID + ")"
+}
+
+// Unwrap returns the error that occurred while reading the response body.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches the sentinel error for its status
+// code, or ErrReauthenticationRequired for an unanswered claims challenge.
+func (e *RequestError) Is(target error) bool {
+	if e.Claims != "" && target == ErrReauthenticationRequired {
+		return true
+	}
+
+	sentinel := sentinelForStatus(e.StatusCode)
+
+	return sentinel != nil && sentinel == target
 }
 
-func doRequest[T any](client *http.Client, req *http.Request, result *T) error {
-	resp, err := client.Do(req) //nolint:gosec // G704 - request URL is constructed internally
+func sentinelForStatus(status int) error {
+	switch {
+	case status == http.StatusBadRequest:
+		return ErrBadRequest
+	case status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case status == http.StatusForbidden:
+		return ErrForbidden
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+func doRequest[T any](client requester, req *http.Request, result *T) error {
+	_, err := doRequestHeader(client, req, result)
+
+	return err
+}
+
+// doRequestHeader behaves like doRequest, and additionally returns the
+// headers of the successful response.
+func doRequestHeader[T any](client requester, req *http.Request, result *T) (http.Header, error) {
+	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("could not perform request: %w", err)
+		return nil, fmt.Errorf("could not perform request: %w", err)
 	}
 
 	defer func() {
@@ -26,35 +135,104 @@ func doRequest[T any](client *http.Client, req *http.Request, result *T) error {
 	}()
 
 	if !isSuccessfulStatusCode(resp.StatusCode) {
-		// read body as plain text
-		if resp.ContentLength > 0 {
-			var body []byte
-
-			body, err = io.ReadAll(resp.Body)
-			if err != nil {
-				return fmt.Errorf("could not read response body: %w", err)
-			}
-
-			return &RequestError{
-				Message: resp.Status + ": " + string(body),
-			}
-		}
-
-		return &RequestError{
-			Message: resp.Status,
-		}
+		return nil, newRequestError(req, resp)
 	}
 
 	if result != nil {
 		err = json.NewDecoder(resp.Body).Decode(result)
 		if err != nil {
-			return fmt.Errorf("could not decode response: %w", err)
+			return nil, fmt.Errorf("could not decode response: %w", err)
 		}
 	}
 
-	return nil
+	return resp.Header, nil
 }
 
+// newRequestError builds a RequestError from a failed response. The body is
+// parsed as problem details when the server says so, and is otherwise
+// included as plain text in the message.
+func newRequestError(req *http.Request, resp *http.Response) error {
+	reqErr := &RequestError{
+		Message:    resp.Status,
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.Redacted(),
+		RequestID:  requestIDFromHeader(resp.Header),
+		Problem:    nil,
+		Claims:     "",
+		Err:        nil,
+	}
+
+	if claims, ok := claimsChallenge(resp); ok {
+		reqErr.Claims = claims
+	}
+
+	// fall back to the ID sent by the client when the server does not
+	// return one, as it is still logged by the platform
+	if reqErr.RequestID == "" {
+		reqErr.RequestID = req.Header.Get(headerRequestID)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		reqErr.Message = resp.Status + ": could not read response body: " + err.Error()
+		reqErr.Err = err
+
+		return reqErr
+	}
+
+	if len(body) == 0 {
+		return reqErr
+	}
+
+	if isProblemJSON(resp.Header.Get("Content-Type")) {
+		var problem ProblemDetails
+		if err = json.Unmarshal(body, &problem); err == nil {
+			reqErr.Problem = &problem
+			reqErr.Message = resp.Status + ": " + problem.summary()
+
+			return reqErr
+		}
+	}
+
+	reqErr.Message = resp.Status + ": " + string(body)
+
+	return reqErr
+}
+
+func (p *ProblemDetails) summary() string {
+	switch {
+	case p.Title != "" && p.Detail != "":
+		return p.Title + ": " + p.Detail
+	case p.Detail != "":
+		return p.Detail
+	default:
+		return p.Title
+	}
+}
+
+func isProblemJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && mediaType == contentTypeProblemJSON
+}
+
+func requestIDFromHeader(header http.Header) string {
+	for _, key := range requestIDHeaders {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// userAgent identifies the version of indev and the operating system it runs
+// on.
+var userAgent = sync.OnceValue(func() string {
+	return build.NameVersionString() + " " + build.OS().Name + "/" + runtime.GOARCH
+})
+
 func isSuccessfulStatusCode(status int) bool {
 	return status >= 200 && status <= 299
 }
@@ -2,6 +2,8 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -125,6 +127,11 @@ func TestRequestError(t *testing.T) {
 		assert.Equal(t, "404 Not Found: resource not found", err.Error())
 	})
 
+	t.Run("Error includes the request ID", func(t *testing.T) {
+		err := &RequestError{Message: "500 Internal Server Error", RequestID: "abc-123"}
+		assert.Equal(t, "500 Internal Server Error (request ID: abc-123)", err.Error())
+	})
+
 	t.Run("implements error interface", func(t *testing.T) {
 		var err error = &RequestError{Message: "test"}
 		assert.NotNil(t, err)
@@ -294,3 +301,138 @@ func TestDoRequest(t *testing.T) {
 		assert.Contains(t, err.Error(), "could not perform request")
 	})
 }
+
+func TestRequestErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{name: "400 matches ErrBadRequest", status: http.StatusBadRequest, want: ErrBadRequest},
+		{name: "401 matches ErrUnauthorized", status: http.StatusUnauthorized, want: ErrUnauthorized},
+		{name: "403 matches ErrForbidden", status: http.StatusForbidden, want: ErrForbidden},
+		{name: "404 matches ErrNotFound", status: http.StatusNotFound, want: ErrNotFound},
+		{name: "409 matches ErrConflict", status: http.StatusConflict, want: ErrConflict},
+		{name: "429 matches ErrRateLimited", status: http.StatusTooManyRequests, want: ErrRateLimited},
+		{name: "500 matches ErrServer", status: http.StatusInternalServerError, want: ErrServer},
+		{name: "503 matches ErrServer", status: http.StatusServiceUnavailable, want: ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("request failed: %w", &RequestError{StatusCode: tt.status})
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	t.Run("does not match other sentinels", func(t *testing.T) {
+		err := &RequestError{StatusCode: http.StatusNotFound}
+
+		assert.NotErrorIs(t, err, ErrConflict)
+		assert.NotErrorIs(t, err, ErrServer)
+	})
+
+	t.Run("unmapped status matches no sentinel", func(t *testing.T) {
+		err := &RequestError{StatusCode: http.StatusTeapot}
+
+		assert.NotErrorIs(t, err, ErrBadRequest)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("resource not found errors match ErrNotFound", func(t *testing.T) {
+		assert.ErrorIs(t, ErrClusterNotFound, ErrNotFound)
+		assert.ErrorIs(t, ErrTeamNotFound, ErrNotFound)
+		assert.ErrorIs(t, ErrUserNotFound, ErrNotFound)
+	})
+}
+
+func TestDoRequestErrorDetails(t *testing.T) {
+	t.Run("captures method, URL, status and request ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", "req-123")
+			w.WriteHeader(http.StatusConflict)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest("POST", server.URL+"/api/v1/teams", nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](server.Client(), req, nil)
+
+		var reqErr *RequestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusConflict, reqErr.StatusCode)
+		assert.Equal(t, "POST", reqErr.Method)
+		assert.Equal(t, server.URL+"/api/v1/teams", reqErr.URL)
+		assert.Equal(t, "req-123", reqErr.RequestID)
+		assert.Nil(t, reqErr.Problem)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("parses problem details body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"type":"https://example.com/validation","title":"Validation failed",` +
+				`"status":400,"detail":"name is too long"}`))
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest("POST", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](server.Client(), req, nil)
+
+		var reqErr *RequestError
+		require.ErrorAs(t, err, &reqErr)
+		require.NotNil(t, reqErr.Problem)
+		assert.Equal(t, "Validation failed", reqErr.Problem.Title)
+		assert.Equal(t, "name is too long", reqErr.Problem.Detail)
+		assert.Equal(t, http.StatusBadRequest, reqErr.Problem.Status)
+		assert.Equal(t, "400 Bad Request: Validation failed: name is too long", reqErr.Message)
+	})
+
+	t.Run("falls back to plain text for malformed problem details", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest("POST", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](server.Client(), req, nil)
+
+		var reqErr *RequestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Nil(t, reqErr.Problem)
+		assert.Equal(t, "400 Bad Request: not json", reqErr.Message)
+	})
+
+	t.Run("keeps the status when the body cannot be read", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the body is cut short of its declared length
+			w.Header().Set("Content-Length", "100")
+			w.Header().Set("X-Request-Id", "req-123")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not"))
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](server.Client(), req, nil)
+
+		var reqErr *RequestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusNotFound, reqErr.StatusCode)
+		assert.Equal(t, "req-123", reqErr.RequestID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Contains(t, err.Error(), "404 Not Found: could not read response body")
+	})
+}
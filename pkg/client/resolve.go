@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrAIDeploymentNotFound = fmt.Errorf("AI deployment %w", ErrNotFound)
+
+// ResolveClusterID returns the ID of the cluster with the given name, which
+// the cluster status and member operations take.
+func ResolveClusterID(ctx context.Context, c ClusterClient, name string) (string, error) {
+	cluster, err := c.GetCluster(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve cluster %q: %w", name, err)
+	}
+
+	return cluster.ID, nil
+}
+
+// ResolveTeamID returns the ID of the team with the given name, which the
+// team member operations take.
+func ResolveTeamID(ctx context.Context, c TeamsClient, name string) (string, error) {
+	team, err := c.GetTeam(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve team %q: %w", name, err)
+	}
+
+	return team.ID, nil
+}
+
+// ResolveAIDeploymentID returns the ID of the AI deployment with the given
+// name, which the deployment and API key operations take.
+func ResolveAIDeploymentID(ctx context.Context, c AIClient, name string) (string, error) {
+	deployment, err := c.GetAIDeployment(ctx, name)
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "", fmt.Errorf("could not resolve AI deployment %q: %w", name, ErrAIDeploymentNotFound)
+	case err != nil:
+		return "", fmt.Errorf("could not resolve AI deployment %q: %w", name, err)
+	case deployment.Name != name:
+		return "", fmt.Errorf("could not resolve AI deployment %q: %w", name, ErrAIDeploymentNotFound)
+	}
+
+	return deployment.ID, nil
+}
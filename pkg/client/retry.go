@@ -0,0 +1,319 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+	headerRetryAfter     = "Retry-After"
+
+	defaultMaxRetries    = 3
+	defaultMinBackoff    = 500 * time.Millisecond
+	defaultMaxBackoff    = 10 * time.Second
+	defaultMaxRetryAfter = time.Minute
+
+	// maxDrainSize limits how much of a discarded response body is read
+	// before the connection is reused for the next attempt.
+	maxDrainSize = 4 * 1024
+)
+
+// idempotentMethods are the HTTP methods that can be retried without the
+// risk of applying the same change twice.
+var idempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+// retryableStatusCodes are the response status codes that indicate a
+// transient failure worth retrying.
+var retryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how failed requests are retried. Requests are only
+// retried when they are idempotent, either because of their method or because
+// they carry an Idempotency-Key header.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero
+	// disables retries.
+	MaxRetries int
+	// MinBackoff is the base delay of the exponential backoff.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// MaxRetryAfter is the longest Retry-After delay the client is willing to
+	// wait for. Longer delays cause the response to be returned as is.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by clients created with New.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:    defaultMaxRetries,
+		MinBackoff:    defaultMinBackoff,
+		MaxBackoff:    defaultMaxBackoff,
+		MaxRetryAfter: defaultMaxRetryAfter,
+	}
+}
+
+// WithRetryPolicy configures how the client retries failed requests.
+func WithRetryPolicy(policy RetryPolicy) RestClientOption {
+	return func(client *RestClient) {
+		client.retryPolicy = policy
+	}
+}
+
+// WithRetries sets the maximum number of retries while keeping the remaining
+// settings of the current retry policy.
+func WithRetries(retries int) RestClientOption {
+	return func(client *RestClient) {
+		client.retryPolicy.MaxRetries = retries
+	}
+}
+
+// WithTimeout sets the timeout of each individual request attempt. A zero
+// timeout disables the per-attempt timeout.
+func WithTimeout(timeout time.Duration) RestClientOption {
+	return func(client *RestClient) {
+		client.timeout = timeout
+	}
+}
+
+// requester performs HTTP requests on behalf of doRequest.
+type requester interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// retryingRequester sends requests through an http.Client, applying a
+// per-attempt timeout and retrying transient failures.
+type retryingRequester struct {
+	client  *http.Client
+	policy  RetryPolicy
+	timeout time.Duration
+	debug   io.Writer
+	// challenger answers claims challenges, or is nil when the token
+	// provider cannot
+	challenger ClaimsChallenger
+}
+
+func (c *RestClient) requester() retryingRequester {
+	challenger, _ := c.tokenProvider.(ClaimsChallenger)
+
+	return retryingRequester{
+		client:     c.httpClient,
+		policy:     c.retryPolicy,
+		timeout:    c.timeout,
+		debug:      c.debug,
+		challenger: challenger,
+	}
+}
+
+// Do sends the request, retrying transient failures, and answers a claims
+// challenge of the platform by sending it once more with a new token.
+func (r retryingRequester) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.doWithRetries(req)
+	if err != nil {
+		return resp, err
+	}
+
+	return r.answerChallenge(req, resp)
+}
+
+func (r retryingRequester) doWithRetries(req *http.Request) (*http.Response, error) {
+	retryable := isRetryableRequest(req)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := r.attempt(req)
+
+		if !retryable || attempt >= r.policy.MaxRetries || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay, ok := r.policy.delay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+
+		discard(resp)
+
+		if err = sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (r retryingRequester) attempt(req *http.Request) (*http.Response, error) {
+	if r.timeout <= 0 {
+		return r.send(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), r.timeout)
+
+	resp, err := r.send(req.Clone(ctx))
+	if err != nil {
+		cancel()
+
+		return nil, err //nolint:wrapcheck // wrapped by doRequest
+	}
+
+	// the attempt context must outlive this function, as the body is read
+	// by the caller
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// send performs a single request, and prints it to the debug writer if
+// there is one.
+func (r retryingRequester) send(req *http.Request) (*http.Response, error) {
+	if r.debug == nil {
+		return r.client.Do(req) //nolint:wrapcheck,gosec // wrapped by doRequest; URL is constructed internally
+	}
+
+	dumpRequest(r.debug, req)
+
+	start := time.Now()
+	resp, err := r.client.Do(req) //nolint:gosec // G704 - request URL is constructed internally
+
+	dumpResponse(r.debug, resp, err, time.Since(start))
+
+	return resp, err //nolint:wrapcheck // wrapped by doRequest
+}
+
+// delay returns how long to wait before the next attempt. It honours the
+// Retry-After header of the response, and otherwise applies exponential
+// backoff with full jitter. The second return value is false when the
+// server asks for a longer delay than the policy allows.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get(headerRetryAfter), time.Now()); ok {
+			return retryAfter, retryAfter <= p.MaxRetryAfter
+		}
+	}
+
+	backoff := p.MinBackoff << attempt
+	if backoff <= 0 || backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	if backoff <= 0 {
+		return 0, true
+	}
+
+	return rand.N(backoff), true //nolint:gosec // jitter does not need a secure random source
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+func isRetryableRequest(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body cannot be replayed
+		return false
+	}
+
+	return slices.Contains(idempotentMethods, req.Method) || req.Header.Get(headerIdempotencyKey) != ""
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return slices.Contains(retryableStatusCodes, resp.StatusCode)
+}
+
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("could not rewind request body: %w", err)
+	}
+
+	req.Body = body
+
+	return nil
+}
+
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainSize)
+	_ = resp.Body.Close()
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("retry aborted: %w", context.Cause(ctx))
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnCloseBody releases the context of a request attempt once the
+// response body has been consumed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err //nolint:wrapcheck // transparent wrapper
+}
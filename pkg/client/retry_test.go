@@ -0,0 +1,267 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRequester(client *http.Client, retries int) retryingRequester {
+	return retryingRequester{
+		client: client,
+		policy: RetryPolicy{
+			MaxRetries:    retries,
+			MinBackoff:    time.Millisecond,
+			MaxBackoff:    5 * time.Millisecond,
+			MaxRetryAfter: time.Second,
+		},
+		timeout: time.Second,
+	}
+}
+
+// flakyServer fails the first n requests with the given status code and
+// succeeds afterwards. It records the bodies it receives.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32, *[]string) {
+	t.Helper()
+
+	var (
+		calls  atomic.Int32
+		bodies []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+
+			w.WriteHeader(status)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls, &bodies
+}
+
+func TestRetryingRequester(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("retries idempotent request on 503", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		var result response
+		err = doRequest(testRequester(server.Client(), 3), req, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result.Name)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 10, http.StatusBadGateway, nil)
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](testRequester(server.Client(), 2), req, nil)
+
+		require.ErrorIs(t, err, ErrServer)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("does not retry non-transient errors", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 10, http.StatusNotFound, nil)
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](testRequester(server.Client(), 3), req, nil)
+
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does not retry POST without idempotency key", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 10, http.StatusServiceUnavailable, nil)
+
+		req, err := http.NewRequest("POST", server.URL, bytes.NewReader([]byte("payload")))
+		require.NoError(t, err)
+
+		err = doRequest[any](testRequester(server.Client(), 3), req, nil)
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("retries POST with idempotency key and replays body", func(t *testing.T) {
+		server, calls, bodies := flakyServer(t, 1, http.StatusServiceUnavailable, nil)
+
+		req, err := http.NewRequest("POST", server.URL, bytes.NewReader([]byte("payload")))
+		require.NoError(t, err)
+		req.Header.Set(headerIdempotencyKey, "key-1")
+
+		err = doRequest[any](testRequester(server.Client(), 3), req, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, []string{"payload", "payload"}, *bodies)
+	})
+
+	t.Run("honours Retry-After on 429", func(t *testing.T) {
+		header := http.Header{headerRetryAfter: []string{"0"}}
+		server, calls, _ := flakyServer(t, 1, http.StatusTooManyRequests, header)
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](testRequester(server.Client(), 3), req, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("returns response when Retry-After exceeds the policy", func(t *testing.T) {
+		header := http.Header{headerRetryAfter: []string{"3600"}}
+		server, calls, _ := flakyServer(t, 1, http.StatusTooManyRequests, header)
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		err = doRequest[any](testRequester(server.Client(), 3), req, nil)
+
+		require.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("stops retrying when context is cancelled", func(t *testing.T) {
+		server, calls, _ := flakyServer(t, 10, http.StatusServiceUnavailable, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		require.NoError(t, err)
+
+		requester := testRequester(server.Client(), 5)
+		requester.policy.MinBackoff = time.Hour
+		requester.policy.MaxBackoff = time.Hour
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		err = doRequest[any](requester, req, nil)
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("applies per-attempt timeout", func(t *testing.T) {
+		var calls atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				<-r.Context().Done()
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+
+		requester := testRequester(server.Client(), 1)
+		requester.timeout = 50 * time.Millisecond
+
+		err = doRequest[any](requester, req, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "empty", value: "", want: 0, wantOk: false},
+		{name: "seconds", value: "5", want: 5 * time.Second, wantOk: true},
+		{name: "zero seconds", value: "0", want: 0, wantOk: true},
+		{name: "negative seconds", value: "-1", want: 0, wantOk: false},
+		{name: "http date", value: "Mon, 01 Jan 2024 12:00:30 GMT", want: 30 * time.Second, wantOk: true},
+		{name: "http date in the past", value: "Mon, 01 Jan 2024 11:00:00 GMT", want: 0, wantOk: true},
+		{name: "garbage", value: "soon", want: 0, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries:    5,
+		MinBackoff:    100 * time.Millisecond,
+		MaxBackoff:    time.Second,
+		MaxRetryAfter: time.Minute,
+	}
+
+	t.Run("backoff is bounded by max backoff", func(t *testing.T) {
+		for attempt := range 10 {
+			delay, ok := policy.delay(attempt, nil)
+
+			assert.True(t, ok)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.Less(t, delay, policy.MaxBackoff)
+		}
+	})
+
+	t.Run("retry after takes precedence", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{headerRetryAfter: []string{"7"}}}
+
+		delay, ok := policy.delay(0, resp)
+
+		assert.True(t, ok)
+		assert.Equal(t, 7*time.Second, delay)
+	})
+}
+
+func TestIsRetryableRequest(t *testing.T) {
+	assert.True(t, isRetryableRequest(&http.Request{Method: http.MethodGet, Header: http.Header{}}))
+	assert.False(t, isRetryableRequest(&http.Request{Method: http.MethodPost, Header: http.Header{}}))
+	assert.True(t, isRetryableRequest(&http.Request{
+		Method: http.MethodPost,
+		Header: http.Header{headerIdempotencyKey: []string{"key"}},
+	}))
+}
@@ -4,13 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/google/uuid"
 )
 
-var ErrTeamNotFound = errors.New("team not found")
+var ErrTeamNotFound = fmt.Errorf("team %w", ErrNotFound)
 
 type Team struct {
 	ID          string   `json:"id"          yaml:"id"`
@@ -83,20 +83,20 @@ type AddTeamMemberRequest struct {
 }
 
 func (c *RestClient) ListTeams(ctx context.Context) ([]Team, error) {
-	var teams TeamList
-
-	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/teams", nil)
+	teams, err := Collect(c.IterTeams(ctx, ListOptions{}))
 	if err != nil {
-		return teams, err
-	}
-
-	if err = doRequest(c.httpClient, req, &teams); err != nil {
-		return teams, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 
 	return teams, nil
 }
 
+// IterTeams returns an iterator over all teams, fetching them one page at a
+// time.
+func (c *RestClient) IterTeams(ctx context.Context, opts ListOptions) iter.Seq2[Team, error] {
+	return paginate[Team](ctx, c, c.baseURI+"/api/v1/teams", opts)
+}
+
 func (c *RestClient) GetTeam(ctx context.Context, name string) (*Team, error) {
 	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/teams/by-name/"+name, nil)
 	if err != nil {
@@ -104,7 +104,7 @@ func (c *RestClient) GetTeam(ctx context.Context, name string) (*Team, error) {
 	}
 
 	var team Team
-	if err = doRequest(c.httpClient, req, &team); err != nil {
+	if err = doRequest(c.requester(), req, &team); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -123,7 +123,7 @@ func (c *RestClient) GetTeamMembers(ctx context.Context, teamID string) ([]TeamM
 		return members, err
 	}
 
-	if err = doRequest(c.httpClient, req, &members); err != nil {
+	if err = doRequest(c.requester(), req, &members); err != nil {
 		return members, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -142,7 +142,7 @@ func (c *RestClient) CreateTeam(ctx context.Context, request NewTeamRequest) (*T
 	}
 
 	var team Team
-	if err = doRequest(c.httpClient, req, &team); err != nil {
+	if err = doRequest(c.requester(), req, &team); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -155,7 +155,7 @@ func (c *RestClient) DeleteTeam(ctx context.Context, request DeleteTeamRequest)
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -175,7 +175,7 @@ func (c *RestClient) AddTeamMember(ctx context.Context, teamID string, request [
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -188,7 +188,7 @@ func (c *RestClient) RemoveTeamMember(ctx context.Context, teamID string, member
 		return err
 	}
 
-	if err = doRequest[any](c.httpClient, req, nil); err != nil {
+	if err = doRequest[any](c.requester(), req, nil); err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 
@@ -2,11 +2,11 @@ package client
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"iter"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var ErrUserNotFound = fmt.Errorf("user %w", ErrNotFound)
 
 type User struct {
 	ID    string   `json:"id"    yaml:"id"`
@@ -18,20 +18,20 @@ type User struct {
 type UserList []User
 
 func (c *RestClient) ListUsers(ctx context.Context) ([]User, error) {
-	var users UserList
-
-	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/users", nil)
+	users, err := Collect(c.IterUsers(ctx, ListOptions{}))
 	if err != nil {
-		return users, err
-	}
-
-	if err = doRequest(c.httpClient, req, &users); err != nil {
-		return users, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 
 	return users, nil
 }
 
+// IterUsers returns an iterator over all users, fetching them one page at a
+// time.
+func (c *RestClient) IterUsers(ctx context.Context, opts ListOptions) iter.Seq2[User, error] {
+	return paginate[User](ctx, c, c.baseURI+"/api/v1/users", opts)
+}
+
 func (c *RestClient) GetUser(ctx context.Context, upn string) (*User, error) {
 	req, err := c.createAuthenticatedRequest(ctx, "GET", c.baseURI+"/api/v1/users/by-upn/"+upn, nil)
 	if err != nil {
@@ -39,7 +39,7 @@ func (c *RestClient) GetUser(ctx context.Context, upn string) (*User, error) {
 	}
 
 	var user User
-	if err = doRequest(c.httpClient, req, &user); err != nil {
+	if err = doRequest(c.requester(), req, &user); err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
@@ -12,6 +13,7 @@ import (
 	"github.com/intility/indev/internal/build"
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/tokencache"
 )
 
 // homeAccountIDParts is the expected number of parts when splitting
@@ -27,11 +29,21 @@ var (
 type Authenticator interface {
 	IsAuthenticated(ctx context.Context) (bool, error)
 	GetCurrentAccount(ctx context.Context) (public.Account, error)
+	Accounts(ctx context.Context) ([]public.Account, error)
+	// Source describes where tokens come from, such as "interactive
+	// sign-in" or "client credentials <client ID>".
+	Source() string
 }
 
 type ClientSet struct {
 	Authenticator  Authenticator
 	PlatformClient client.Client
+	// HTTPClient sends the requests that do not go to the platform, such as
+	// sign-in requests to Entra ID. It is nil when the default client is used.
+	HTTPClient *http.Client
+	// TokenCache stores the tokens of signed-in accounts, as selected in the
+	// settings. It is nil when the default cache is used.
+	TokenCache *tokencache.TokenCache
 }
 
 func (c *ClientSet) EnsureSignedIn(cmd *cobra.Command, _ []string) error {
@@ -43,6 +55,12 @@ func (c *ClientSet) EnsureSignedIn(cmd *cobra.Command, _ []string) error {
 		cmd.SilenceUsage = true
 		cmd.SilenceErrors = true
 
+		// a corrupt cache is reset, which the user should know about before
+		// signing in again
+		if errors.Is(err, tokencache.ErrCorruptCache) {
+			return err
+		}
+
 		return errNotAuthenticatedPreHook
 	}
 
@@ -135,14 +153,19 @@ func (c *ClientSet) ensureAITenant(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
-// GetTenantID extracts the tenant ID from the current account's HomeAccountID.
-// The HomeAccountID format is "<oid>.<tid>" where tid is the tenant ID.
+// GetTenantID returns the tenant of the current account, which is the tenant
+// that the account signed in to. Accounts without a realm fall back to the
+// home tenant in HomeAccountID, whose format is "<oid>.<tid>".
 func (c *ClientSet) GetTenantID(ctx context.Context) (string, error) {
 	account, err := c.Authenticator.GetCurrentAccount(ctx)
 	if err != nil {
 		return "", fmt.Errorf("could not get current account: %w", err)
 	}
 
+	if account.Realm != "" {
+		return account.Realm, nil
+	}
+
 	parts := strings.Split(account.HomeAccountID, ".")
 	if len(parts) < homeAccountIDParts {
 		return "", fmt.Errorf("%w: %s", errInvalidHomeAccountID, account.HomeAccountID)
@@ -0,0 +1,354 @@
+// Package clusterspec reads and writes cluster specs, which declare a cluster
+// in a versioned YAML or JSON file that can be kept in git, so that the same
+// cluster can be created again elsewhere.
+package clusterspec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/pkg/client"
+)
+
+const (
+	// APIVersion is the version of the spec format.
+	APIVersion = "indev.intility.com/v1"
+	// Kind is the kind of resource that a spec declares.
+	Kind = "Cluster"
+)
+
+// Limits of the node count of a node pool.
+const (
+	MinNodes = 2
+	MaxNodes = 8
+)
+
+// Presets are the node presets that a node pool can use.
+var Presets = []string{"minimal", "balanced", "performance"}
+
+var (
+	ErrInvalidSpec = errors.New("invalid cluster spec")
+
+	// memoryPattern matches memory quantities such as "16Gi".
+	memoryPattern = regexp.MustCompile(`^[1-9][0-9]*(Mi|Gi|Ti)$`)
+	// namePattern matches DNS labels, as cluster and node pool names become
+	// part of host names.
+	namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+)
+
+// Spec declares a cluster.
+type Spec struct {
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind"       yaml:"kind"`
+	Name       string `json:"name"       yaml:"name"`
+	// Version is the OpenShift version, or empty for the default version.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Environment is the environment to create the cluster in, or empty
+	// for the default environment.
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+	// SSOProvisioner is the ID of the integration that signs users in, or
+	// empty for the one configured for the organization.
+	SSOProvisioner string `json:"ssoProvisioner,omitempty" yaml:"ssoProvisioner,omitempty"`
+	// PullSecretRef is the name of the pull secret of the cluster, which is
+	// resolved to its ID when the cluster is created, so that the spec can
+	// be used in other environments.
+	PullSecretRef string     `json:"pullSecretRef,omitempty" yaml:"pullSecretRef,omitempty"`
+	NodePools     []NodePool `json:"nodePools"               yaml:"nodePools"`
+}
+
+// NodePool declares a node pool of a cluster. Its nodes are sized either by
+// a preset or by custom compute resources, and their number is either fixed
+// by Replicas or left to the autoscaler within MinCount and MaxCount.
+type NodePool struct {
+	Name               string   `json:"name,omitempty"               yaml:"name,omitempty"`
+	Preset             string   `json:"preset,omitempty"             yaml:"preset,omitempty"`
+	Compute            *Compute `json:"compute,omitempty"            yaml:"compute,omitempty"`
+	Replicas           *int     `json:"replicas,omitempty"           yaml:"replicas,omitempty"`
+	AutoscalingEnabled bool     `json:"autoscalingEnabled,omitempty" yaml:"autoscalingEnabled,omitempty"`
+	MinCount           *int     `json:"minCount,omitempty"           yaml:"minCount,omitempty"`
+	MaxCount           *int     `json:"maxCount,omitempty"           yaml:"maxCount,omitempty"`
+}
+
+// Compute declares the resources of each node of a node pool.
+type Compute struct {
+	Cores  int    `json:"cores"  yaml:"cores"`
+	Memory string `json:"memory" yaml:"memory"`
+}
+
+// FieldError is a problem with one field of a spec.
+type FieldError struct {
+	// Path locates the field, such as "nodePools[1].maxCount".
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError lists every problem of a spec.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	problems := make([]string, 0, len(e))
+	for _, problem := range e {
+		problems = append(problems, problem.Error())
+	}
+
+	return ErrInvalidSpec.Error() + ":\n  " + strings.Join(problems, "\n  ")
+}
+
+func (e ValidationError) Is(target error) bool {
+	return target == ErrInvalidSpec
+}
+
+// Read decodes a spec from YAML or JSON. Unknown fields are rejected, so that
+// typos do not go unnoticed.
+func Read(r io.Reader) (Spec, error) {
+	var spec Spec
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return spec, fmt.Errorf("could not read cluster spec: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(&spec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return spec, fmt.Errorf("%w: the file is empty", ErrInvalidSpec)
+		}
+
+		return spec, fmt.Errorf("%w: %w", ErrInvalidSpec, err)
+	}
+
+	return spec, nil
+}
+
+// Write encodes the spec as YAML.
+func Write(w io.Writer, spec Spec) error {
+	indent := 2
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(indent)
+
+	if err := encoder.Encode(spec); err != nil {
+		return fmt.Errorf("could not encode cluster spec: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("could not encode cluster spec: %w", err)
+	}
+
+	return nil
+}
+
+// Validate reports every problem of the spec, or nil if there are none.
+func (s Spec) Validate() error {
+	var problems ValidationError
+
+	report := func(path, format string, a ...any) {
+		problems = append(problems, FieldError{Path: path, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if s.APIVersion != APIVersion {
+		report("apiVersion", "must be %q", APIVersion)
+	}
+
+	if s.Kind != Kind {
+		report("kind", "must be %q", Kind)
+	}
+
+	switch {
+	case s.Name == "":
+		report("name", "is required")
+	case !namePattern.MatchString(s.Name):
+		report("name", "must consist of lowercase letters, digits and '-', and be at most 63 characters")
+	}
+
+	if len(s.NodePools) == 0 {
+		report("nodePools", "at least one node pool is required")
+	}
+
+	names := make(map[string]int, len(s.NodePools))
+
+	for i, pool := range s.NodePools {
+		path := fmt.Sprintf("nodePools[%d]", i)
+
+		if pool.Name != "" {
+			if first, ok := names[pool.Name]; ok {
+				report(path+".name", "%q is already used by nodePools[%d]", pool.Name, first)
+			} else {
+				names[pool.Name] = i
+			}
+		}
+
+		pool.validate(path, len(s.NodePools), report)
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
+
+//nolint:cyclop // validation logic is inherently sequential
+func (p NodePool) validate(path string, pools int, report func(path, format string, a ...any)) {
+	switch {
+	case p.Name == "" && pools > 1:
+		report(path+".name", "is required when there are several node pools")
+	case p.Name != "" && !namePattern.MatchString(p.Name):
+		report(path+".name", "must consist of lowercase letters, digits and '-'")
+	}
+
+	switch {
+	case p.Preset == "" && p.Compute == nil:
+		report(path, "either preset or compute is required")
+	case p.Preset != "" && p.Compute != nil:
+		report(path, "preset and compute cannot both be set")
+	case p.Preset != "" && !slices.Contains(Presets, p.Preset):
+		report(path+".preset", "must be one of %s", strings.Join(Presets, ", "))
+	case p.Compute != nil:
+		if p.Compute.Cores < 1 {
+			report(path+".compute.cores", "must be at least 1")
+		}
+
+		if !memoryPattern.MatchString(p.Compute.Memory) {
+			report(path+".compute.memory", "must be a quantity such as 16Gi")
+		}
+	}
+
+	if !p.AutoscalingEnabled {
+		switch {
+		case p.Replicas == nil:
+			report(path+".replicas", "is required unless autoscaling is enabled")
+		case *p.Replicas < MinNodes || *p.Replicas > MaxNodes:
+			report(path+".replicas", "must be between %d and %d", MinNodes, MaxNodes)
+		}
+
+		if p.MinCount != nil || p.MaxCount != nil {
+			report(path, "minCount and maxCount require autoscalingEnabled")
+		}
+
+		return
+	}
+
+	if p.Replicas != nil {
+		report(path+".replicas", "cannot be set when autoscaling is enabled")
+	}
+
+	validCount := func(field string, count *int) bool {
+		switch {
+		case count == nil:
+			report(path+"."+field, "is required when autoscaling is enabled")
+		case *count < MinNodes || *count > MaxNodes:
+			report(path+"."+field, "must be between %d and %d", MinNodes, MaxNodes)
+		default:
+			return true
+		}
+
+		return false
+	}
+
+	minValid := validCount("minCount", p.MinCount)
+	maxValid := validCount("maxCount", p.MaxCount)
+
+	if minValid && maxValid && *p.MinCount > *p.MaxCount {
+		report(path+".maxCount", "must be at least minCount (%d)", *p.MinCount)
+	}
+}
+
+// Request returns the request that creates the cluster of the spec. The SSO
+// provisioner of the spec takes precedence over the given one. pullSecretID
+// is the ID of the pull secret named by the spec, or nil if it names none.
+func (s Spec) Request(ssoProvisioner string, pullSecretID *string) client.NewClusterRequest {
+	if s.SSOProvisioner != "" {
+		ssoProvisioner = s.SSOProvisioner
+	}
+
+	pools := make(client.NodePools, 0, len(s.NodePools))
+	for _, pool := range s.NodePools {
+		pools = append(pools, pool.nodePool())
+	}
+
+	return client.NewClusterRequest{
+		Name:           s.Name,
+		SSOProvisioner: ssoProvisioner,
+		NodePools:      pools,
+		Version:        s.Version,
+		Environment:    s.Environment,
+		PullSecretRef:  pullSecretID,
+	}
+}
+
+func (p NodePool) nodePool() client.NodePool {
+	var compute *client.ComputeResources
+	if p.Compute != nil {
+		compute = &client.ComputeResources{Cores: p.Compute.Cores, Memory: p.Compute.Memory}
+	}
+
+	return client.NodePool{
+		ID:                 "",
+		Name:               p.Name,
+		Preset:             p.Preset,
+		Replicas:           p.Replicas,
+		Compute:            compute,
+		AutoscalingEnabled: p.AutoscalingEnabled,
+		MinCount:           p.MinCount,
+		MaxCount:           p.MaxCount,
+	}
+}
+
+// FromCluster returns the spec of an existing cluster, with the name of its
+// pull secret, if any. The platform does not report the environment and SSO
+// provisioner of a cluster, so they are left for the defaults.
+func FromCluster(cluster client.Cluster, pullSecret string) Spec {
+	pools := make([]NodePool, 0, len(cluster.NodePools))
+
+	for _, pool := range cluster.NodePools {
+		spec := NodePool{
+			Name:               pool.Name,
+			Preset:             pool.Preset,
+			Compute:            nil,
+			Replicas:           pool.Replicas,
+			AutoscalingEnabled: pool.AutoscalingEnabled,
+			MinCount:           pool.MinCount,
+			MaxCount:           pool.MaxCount,
+		}
+
+		// the compute resources of preset pools follow from the preset
+		if pool.Preset == "" && pool.Compute != nil {
+			spec.Compute = &Compute{Cores: pool.Compute.Cores, Memory: pool.Compute.Memory}
+		}
+
+		// autoscaled pools report their current size, which is not part of
+		// the spec
+		if pool.AutoscalingEnabled {
+			spec.Replicas = nil
+		} else {
+			spec.MinCount, spec.MaxCount = nil, nil
+		}
+
+		pools = append(pools, spec)
+	}
+
+	return Spec{
+		APIVersion:     APIVersion,
+		Kind:           Kind,
+		Name:           cluster.Name,
+		Version:        cluster.Version,
+		Environment:    "",
+		SSOProvisioner: "",
+		PullSecretRef:  pullSecret,
+		NodePools:      pools,
+	}
+}
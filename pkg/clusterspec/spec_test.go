@@ -0,0 +1,212 @@
+package clusterspec_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clusterspec"
+)
+
+const validSpec = `apiVersion: indev.intility.com/v1
+kind: Cluster
+name: payments
+version: "4.17"
+pullSecretRef: quay
+nodePools:
+  - name: system
+    preset: balanced
+    replicas: 3
+  - name: workers
+    compute:
+      cores: 16
+      memory: 64Gi
+    autoscalingEnabled: true
+    minCount: 2
+    maxCount: 6
+`
+
+func intPtr(i int) *int { return &i }
+
+func TestRead(t *testing.T) {
+	spec, err := clusterspec.Read(strings.NewReader(validSpec))
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate())
+
+	assert.Equal(t, "quay", spec.PullSecretRef)
+
+	pullSecretID := "secret-id"
+	request := spec.Request("sso-id", &pullSecretID)
+	assert.Equal(t, "payments", request.Name)
+	assert.Equal(t, "4.17", request.Version)
+	assert.Equal(t, "sso-id", request.SSOProvisioner)
+	require.NotNil(t, request.PullSecretRef)
+	assert.Equal(t, "secret-id", *request.PullSecretRef)
+	assert.Equal(t, client.NodePools{
+		{Name: "system", Preset: "balanced", Replicas: intPtr(3)},
+		{
+			Name:               "workers",
+			Compute:            &client.ComputeResources{Cores: 16, Memory: "64Gi"},
+			AutoscalingEnabled: true,
+			MinCount:           intPtr(2),
+			MaxCount:           intPtr(6),
+		},
+	}, request.NodePools)
+}
+
+func TestReadJSON(t *testing.T) {
+	spec, err := clusterspec.Read(strings.NewReader(`{
+		"apiVersion": "indev.intility.com/v1",
+		"kind": "Cluster",
+		"name": "payments",
+		"ssoProvisioner": "from-spec",
+		"nodePools": [{"preset": "minimal", "replicas": 2}]
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate())
+	assert.Equal(t, "from-spec", spec.Request("default", nil).SSOProvisioner)
+}
+
+func TestReadRejectsUnknownFields(t *testing.T) {
+	_, err := clusterspec.Read(strings.NewReader("apiVersion: indev.intility.com/v1\nnodepools: []\n"))
+	require.ErrorIs(t, err, clusterspec.ErrInvalidSpec)
+	assert.ErrorContains(t, err, "nodepools")
+
+	_, err = clusterspec.Read(strings.NewReader(""))
+	require.ErrorIs(t, err, clusterspec.ErrInvalidSpec)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(spec *clusterspec.Spec)
+		paths  []string
+	}{
+		{
+			name:   "wrong version",
+			modify: func(spec *clusterspec.Spec) { spec.APIVersion = "v0" },
+			paths:  []string{"apiVersion"},
+		},
+		{
+			name:   "invalid name",
+			modify: func(spec *clusterspec.Spec) { spec.Name = "Payments_1" },
+			paths:  []string{"name"},
+		},
+		{
+			name:   "no node pools",
+			modify: func(spec *clusterspec.Spec) { spec.NodePools = nil },
+			paths:  []string{"nodePools"},
+		},
+		{
+			name:   "max below min",
+			modify: func(spec *clusterspec.Spec) { spec.NodePools[1].MaxCount = intPtr(1) },
+			paths:  []string{"nodePools[1].maxCount"},
+		},
+		{
+			name:   "max smaller than min",
+			modify: func(spec *clusterspec.Spec) { spec.NodePools[1].MinCount = intPtr(7) },
+			paths:  []string{"nodePools[1].maxCount"},
+		},
+		{
+			name: "replicas of an autoscaled pool",
+			modify: func(spec *clusterspec.Spec) {
+				spec.NodePools[1].Replicas = intPtr(3)
+			},
+			paths: []string{"nodePools[1].replicas"},
+		},
+		{
+			name: "preset and compute",
+			modify: func(spec *clusterspec.Spec) {
+				spec.NodePools[0].Compute = &clusterspec.Compute{Cores: 4, Memory: "16Gi"}
+			},
+			paths: []string{"nodePools[0]"},
+		},
+		{
+			name: "invalid compute",
+			modify: func(spec *clusterspec.Spec) {
+				spec.NodePools[1].Compute = &clusterspec.Compute{Cores: 0, Memory: "16GB"}
+			},
+			paths: []string{"nodePools[1].compute.cores", "nodePools[1].compute.memory"},
+		},
+		{
+			name: "unknown preset and missing replicas",
+			modify: func(spec *clusterspec.Spec) {
+				spec.NodePools[0].Preset = "huge"
+				spec.NodePools[0].Replicas = nil
+			},
+			paths: []string{"nodePools[0].preset", "nodePools[0].replicas"},
+		},
+		{
+			name: "duplicate and missing pool names",
+			modify: func(spec *clusterspec.Spec) {
+				spec.NodePools[1].Name = "system"
+				spec.NodePools = append(spec.NodePools, clusterspec.NodePool{Preset: "minimal", Replicas: intPtr(2)})
+			},
+			paths: []string{"nodePools[1].name", "nodePools[2].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := clusterspec.Read(strings.NewReader(validSpec))
+			require.NoError(t, err)
+
+			tt.modify(&spec)
+
+			err = spec.Validate()
+			require.ErrorIs(t, err, clusterspec.ErrInvalidSpec)
+
+			var problems clusterspec.ValidationError
+			require.ErrorAs(t, err, &problems)
+
+			paths := make([]string, 0, len(problems))
+			for _, problem := range problems {
+				paths = append(paths, problem.Path)
+			}
+
+			assert.Equal(t, tt.paths, paths)
+		})
+	}
+}
+
+func TestFromCluster(t *testing.T) {
+	cluster := client.Cluster{
+		ID:            "cluster-id",
+		Name:          "payments",
+		Version:       "4.17",
+		PullSecretRef: "secret-id",
+		NodePools: client.NodePools{
+			{
+				ID:       "pool-1",
+				Name:     "system",
+				Preset:   "balanced",
+				Replicas: intPtr(3),
+				Compute:  &client.ComputeResources{Cores: 8, Memory: "32Gi"},
+			},
+			{
+				ID:                 "pool-2",
+				Name:               "workers",
+				Compute:            &client.ComputeResources{Cores: 16, Memory: "64Gi"},
+				Replicas:           intPtr(4),
+				AutoscalingEnabled: true,
+				MinCount:           intPtr(2),
+				MaxCount:           intPtr(6),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	// the pull secret is named rather than referenced by its ID
+	require.NoError(t, clusterspec.Write(&buf, clusterspec.FromCluster(cluster, "quay")))
+	assert.Equal(t, validSpec, buf.String())
+
+	// the printed spec creates the same cluster
+	spec, err := clusterspec.Read(&buf)
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate())
+}
@@ -0,0 +1,94 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/cli"
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/config"
+	"github.com/intility/indev/pkg/tokencache"
+)
+
+var (
+	errAlreadyEncrypted = redact.Errorf("the token cache is already encrypted")
+	errHelperCache      = redact.Errorf("the token cache is kept by a credential helper")
+)
+
+func NewEncryptCacheCommand(set clientset.ClientSet) *cobra.Command {
+	var keyFile string
+
+	cmd := &cobra.Command{
+		Use:   "encrypt-cache",
+		Short: "Encrypt the token cache",
+		Long: `Encrypt the token cache, which holds the refresh tokens of signed-in accounts.
+The key is derived from a passphrase, which is asked for by every command that
+signs in, unless INDEV_CACHE_PASSPHRASE is set. Use --key-file to encrypt the
+cache with a key from a file instead, such as one created with
+'openssl rand -base64 32'.
+
+Tokens in the plaintext cache are moved to the encrypted cache, and the
+plaintext cache is wiped. The choice is saved as tokenCache.encryption in the
+settings.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, span := telemetry.StartSpan(cmd.Context(), "account.encryptCache")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			path := config.DefaultPath()
+
+			settings, err := config.Load(path)
+			if err != nil {
+				return redact.Errorf("could not read settings: %w", redact.Safe(err))
+			}
+
+			if settings.TokenCache.Helper != "" {
+				return errHelperCache
+			}
+
+			if settings.TokenCache.Encryption != config.EncryptionNone {
+				return errAlreadyEncrypted
+			}
+
+			settings.TokenCache = config.TokenCache{Encryption: config.EncryptionPassphrase}
+			if keyFile != "" {
+				settings.TokenCache = config.TokenCache{Encryption: config.EncryptionKeyFile, KeyFile: keyFile}
+			}
+
+			encrypted, err := tokencache.Open(settings.TokenCache, cli.CreatePassphrasePrompter(cmd.ErrOrStderr(), true))
+			if err != nil {
+				return redact.Errorf("could not open encrypted token cache: %w", redact.Safe(err))
+			}
+
+			plaintext := set.TokenCache
+			if plaintext == nil {
+				plaintext = tokencache.New()
+			}
+
+			migrated, err := encrypted.Migrate(plaintext)
+			if err != nil {
+				return redact.Errorf("could not encrypt token cache: %w", redact.Safe(err))
+			}
+
+			if err = settings.Save(path); err != nil {
+				return redact.Errorf("could not save settings: %w", redact.Safe(err))
+			}
+
+			if migrated {
+				ux.Fsuccessf(cmd.OutOrStdout(), "encrypted the token cache\n")
+			} else {
+				ux.Fsuccessf(cmd.OutOrStdout(), "the token cache will be encrypted from the next sign-in\n")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "File with a 32-byte key, raw or base64-encoded")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+func NewListCommand(set clientset.ClientSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List signed-in accounts",
+		Long:  `List the accounts that are signed in, and mark the one that commands use.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "account.list")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			accounts, err := set.Authenticator.Accounts(ctx)
+			if err != nil {
+				return redact.Errorf("could not list accounts: %w", redact.Safe(err))
+			}
+
+			if len(accounts) == 0 {
+				ux.Fprintf(cmd.OutOrStdout(), "You are not signed in to any accounts\n")
+
+				return nil
+			}
+
+			// the current account is missing if the selected one has signed
+			// out, in which case no account is marked
+			current, err := set.Authenticator.GetCurrentAccount(ctx)
+			if err != nil && !errors.Is(err, authenticator.ErrAccountNotFound) {
+				return redact.Errorf("could not get current account: %w", redact.Safe(err))
+			}
+
+			table := ux.TableFromObjects(accounts, accountColumns(current))
+			ux.Fprintf(cmd.OutOrStdout(), "%s", table.String())
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func accountColumns(current public.Account) ux.ColFactory[public.Account] {
+	return func(account public.Account) []ux.Row {
+		active := ""
+		if isSameAccount(account, current) {
+			active = "*"
+		}
+
+		return []ux.Row{
+			ux.NewRow("Active", active),
+			ux.NewRow("Username", account.PreferredUsername),
+			ux.NewRow("Tenant", account.Realm),
+		}
+	}
+}
+
+func isSameAccount(a, b public.Account) bool {
+	return a.HomeAccountID == b.HomeAccountID && a.Realm == b.Realm
+}
@@ -0,0 +1,26 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intility/indev/internal/ux"
+)
+
+func TestAccountColumns(t *testing.T) {
+	current := public.Account{HomeAccountID: "ada.home", Realm: "customer", PreferredUsername: "ada@example.com"}
+	accounts := []public.Account{
+		{HomeAccountID: "ada.home", Realm: "home", PreferredUsername: "ada@example.com"},
+		current,
+	}
+
+	table := ux.TableFromObjects(accounts, accountColumns(current))
+
+	assert.Equal(t,
+		"Active \tUsername        \tTenant   \t\n"+
+			"       \tada@example.com \thome     \t\n"+
+			"*      \tada@example.com \tcustomer \t\n",
+		table.String())
+}
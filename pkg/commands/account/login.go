@@ -14,6 +14,7 @@ import (
 	"github.com/intility/indev/internal/ux"
 	"github.com/intility/indev/pkg/authenticator"
 	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/config"
 )
 
 const (
@@ -21,31 +22,38 @@ const (
 )
 
 func NewLoginCommand(set clientset.ClientSet) *cobra.Command {
-	var useDeviceCodeFlow bool
+	var (
+		useDeviceCodeFlow bool
+		tenant            string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Sign in to the Intility Developer Platform",
-		Long:  `Sign in to the Intility Developer Platform using your Intility credentials.`,
+		Long: `Sign in to the Intility Developer Platform using your Intility credentials.
+Use --tenant to sign in to another tenant, which adds an account next to the
+ones that are already signed in. The account that signs in becomes the one
+that commands use.
+
+A browser is opened to sign in. In SSH sessions and on machines without a
+display, a device code is shown instead, which can be entered in a browser on
+any other device. Use --device-code to always sign in with a device code.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "account.login")
 			defer span.End()
 
 			span.SetAttributes(attribute.Bool("device_code_flow", useDeviceCodeFlow))
 
-			cfg := authenticator.Config{
-				ClientID:    build.ClientID(),
-				Authority:   build.Authority(),
-				Scopes:      build.Scopes(),
-				RedirectURI: build.SuccessRedirect(),
-			}
-
 			var options []authenticator.Option
 			if useDeviceCodeFlow {
 				options = append(options, authenticator.WithDeviceCodeFlow(cli.CreatePrinter(cmd)))
+			} else {
+				options = append(options, authenticator.WithAutomaticFlow(cli.CreatePrinter(cmd)))
 			}
 
-			auth := authenticator.NewAuthenticator(cfg, options...)
+			options = append(options, loginSelection(cmd, tenant)...)
+
+			auth := newAuthenticator(set, options...)
 
 			ctx, cancel := context.WithTimeout(ctx, authTimeout)
 			defer cancel()
@@ -55,13 +63,65 @@ func NewLoginCommand(set clientset.ClientSet) *cobra.Command {
 				return redact.Errorf("could not authenticate: %w", err)
 			}
 
+			if err = selectAccount(result.Account.PreferredUsername, result.Account.Realm); err != nil {
+				return err
+			}
+
 			ux.Fsuccessf(cmd.OutOrStdout(), "authenticated as %s\n", result.Account.PreferredUsername)
 
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&useDeviceCodeFlow, "device", false, "Use device code flow for authentication")
+	cmd.Flags().BoolVar(&useDeviceCodeFlow, "device-code", false, "Sign in with a device code instead of a browser on this machine")
+	cmd.Flags().BoolVar(&useDeviceCodeFlow, "device", false, "Sign in with a device code instead of a browser on this machine")
+	_ = cmd.Flags().MarkDeprecated("device", "use --device-code instead")
+	cmd.Flags().StringVar(&tenant, "tenant", "", "Tenant ID or domain to sign in to")
 
 	return cmd
 }
+
+// newAuthenticator creates an authenticator for the accounts that sign in
+// with indev, using the token cache and HTTP client of the client set.
+func newAuthenticator(set clientset.ClientSet, options ...authenticator.Option) *authenticator.Authenticator {
+	cfg := authenticator.Config{
+		ClientID:    build.ClientID(),
+		Authority:   build.Authority(),
+		Scopes:      build.Scopes(),
+		RedirectURI: build.SuccessRedirect(),
+	}
+
+	var defaults []authenticator.Option
+	if set.HTTPClient != nil {
+		defaults = append(defaults, authenticator.WithHTTPClient(set.HTTPClient))
+	}
+
+	if set.TokenCache != nil {
+		defaults = append(defaults, authenticator.WithTokenCache(set.TokenCache))
+	}
+
+	return authenticator.NewAuthenticator(cfg, append(defaults, options...)...)
+}
+
+// loginSelection selects the account to sign in to: an account of the given
+// tenant, the one given with the global --account flag, or else the one that
+// commands currently use.
+func loginSelection(cmd *cobra.Command, tenant string) []authenticator.Option {
+	if tenant != "" {
+		return []authenticator.Option{authenticator.WithTenant(tenant)}
+	}
+
+	if username, _ := cmd.Flags().GetString("account"); username != "" {
+		return []authenticator.Option{authenticator.WithAccount(username)}
+	}
+
+	settings, err := config.Load(config.DefaultPath())
+	if err != nil {
+		return nil
+	}
+
+	return []authenticator.Option{
+		authenticator.WithAccount(settings.Account),
+		authenticator.WithTenant(settings.Tenant),
+	}
+}
@@ -1,32 +1,220 @@
 package account
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/adrg/xdg"
 	"github.com/spf13/cobra"
 
+	"github.com/intility/indev/internal/cli"
+	"github.com/intility/indev/internal/redact"
 	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/config"
+	"github.com/intility/indev/pkg/httpcache"
 	"github.com/intility/indev/pkg/tokencache"
 )
 
 func NewLogoutCommand(set clientset.ClientSet) *cobra.Command {
+	var all, purge bool
+
 	cmd := &cobra.Command{
 		Use:   "logout",
-		Short: "Logout the current account",
-		Long:  `Logout the current account.`,
+		Short: "Sign out of the current account",
+		Long: `Sign out of the current account, or of the one given with --account, by
+removing its tokens from the token cache. Use --all to sign out of every
+account.
+
+--purge signs out of every account and also removes everything else indev
+keeps for you: settings, cached responses and traces. Use it to clean up a
+shared machine.
+
+Signing out only removes tokens from this machine. Nothing is revoked in
+Entra ID or on the platform, so access tokens that were handed out before
+signing out stay valid until they expire, which takes at most an hour.`,
+		Args: cobra.NoArgs,
+		// purging cleans up settings that could not be loaded
+		Annotations: map[string]string{
+			cli.AnnotationWithoutSetup: "purge",
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, span := telemetry.StartSpan(cmd.Context(), "account.logout")
+			ctx, span := telemetry.StartSpan(cmd.Context(), "account.logout")
 			defer span.End()
 
-			err := tokencache.New().Clear()
-			if err != nil {
+			cmd.SilenceUsage = true
+
+			auth := newAuthenticator(set, loginSelection(cmd, "")...)
+
+			// a cache that cannot be read is still wiped when purging
+			accounts, err := auth.Accounts(ctx)
+			if err != nil && !purge {
+				return redact.Errorf("logout failed: %w", redact.Safe(err))
+			}
+
+			removed := accounts
+
+			if !all && !purge {
+				account, err := auth.GetCurrentAccount(ctx)
+				if errors.Is(err, authenticator.ErrNoAccounts) {
+					ux.Fprintf(cmd.OutOrStdout(), "You are not signed in to any accounts\n")
+
+					return nil
+				}
+
+				if err != nil {
+					return redact.Errorf("logout failed: %w", redact.Safe(err))
+				}
+
+				if err = auth.RemoveAccount(ctx, account); err != nil {
+					return redact.Errorf("logout failed: %w", redact.Safe(err))
+				}
+
+				removed = sameUser(accounts, account)
+			}
+
+			// the caches are wiped once no account is left, in case the
+			// encryption setting was changed since signing in, along with a
+			// cache that is kept by a credential helper
+			if len(removed) == len(accounts) {
+				err = tokencache.Wipe()
+				if set.TokenCache != nil {
+					err = errors.Join(err, set.TokenCache.Clear())
+				}
+
+				if err != nil {
+					return fmt.Errorf("logout failed: %w", err)
+				}
+			}
+
+			// responses fetched by the accounts are not shown to whoever
+			// signs in next
+			if err = forgetResponses(httpcache.DefaultDir(), removed, all || purge); err != nil {
 				return fmt.Errorf("logout failed: %w", err)
 			}
 
-			return nil
+			if len(removed) == 0 && !purge {
+				ux.Fprintf(cmd.OutOrStdout(), "You are not signed in to any accounts\n")
+			}
+
+			for _, account := range removed {
+				ux.Fsuccessf(cmd.OutOrStdout(), "signed out %s (tenant %s)\n", account.PreferredUsername, account.Realm)
+			}
+
+			if purge {
+				return purgeDirs(cmd.OutOrStdout(), stateDirs())
+			}
+
+			return deselectAccounts(removed)
 		},
 	}
 
+	cmd.Flags().BoolVar(&all, "all", false, "Sign out of every account")
+	cmd.Flags().BoolVar(&purge, "purge", false,
+		"Sign out of every account and remove all settings, cached responses and traces")
+
 	return cmd
 }
+
+// sameUser returns the accounts of the user of account in every tenant,
+// which are signed out together.
+func sameUser(accounts []public.Account, account public.Account) []public.Account {
+	same := make([]public.Account, 0, 1)
+
+	for _, candidate := range accounts {
+		if candidate.HomeAccountID == account.HomeAccountID {
+			same = append(same, candidate)
+		}
+	}
+
+	if len(same) == 0 {
+		same = append(same, account)
+	}
+
+	return same
+}
+
+// forgetResponses removes the responses cached for the accounts, or every
+// cached response when signing out of all of them.
+func forgetResponses(dir string, removed []public.Account, all bool) error {
+	if all {
+		return httpcache.Clear(dir) //nolint:wrapcheck // wrapped by the caller
+	}
+
+	var errs []error
+
+	for _, account := range removed {
+		if partition := client.CachePartition(account); partition != "" {
+			errs = append(errs, httpcache.RemovePartition(dir, partition))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deselectAccounts forgets the account that commands use if it was signed
+// out.
+func deselectAccounts(removed []public.Account) error {
+	path := config.DefaultPath()
+
+	settings, err := config.Load(path)
+	if err != nil {
+		return redact.Errorf("could not read settings: %w", redact.Safe(err))
+	}
+
+	if settings.Account == "" {
+		return nil
+	}
+
+	for _, account := range removed {
+		if strings.EqualFold(account.PreferredUsername, settings.Account) || account.HomeAccountID == settings.Account {
+			return selectAccount("", "")
+		}
+	}
+
+	return nil
+}
+
+// stateDirs returns the directories where indev keeps data of the user.
+func stateDirs() []string {
+	return []string{
+		filepath.Join(xdg.ConfigHome, "indev"),
+		filepath.Join(xdg.DataHome, "indev"),
+		filepath.Join(xdg.CacheHome, "indev"),
+		filepath.Join(xdg.StateHome, "indev"),
+	}
+}
+
+// purgeDirs removes the directories and reports the ones that existed.
+func purgeDirs(w io.Writer, dirs []string) error {
+	var errs []error
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		ux.Fsuccessf(w, "removed %s\n", dir)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("could not remove all data: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/httpcache"
+)
+
+func TestSameUser(t *testing.T) {
+	home := public.Account{HomeAccountID: "oid.home", Realm: "home", PreferredUsername: "ada@example.com"}
+	guest := public.Account{HomeAccountID: "oid.home", Realm: "contoso", PreferredUsername: "ada@example.com"}
+	other := public.Account{HomeAccountID: "other.home", Realm: "home", PreferredUsername: "grace@example.com"}
+
+	assert.Equal(t, []public.Account{home, guest}, sameUser([]public.Account{home, other, guest}, guest))
+	assert.Equal(t, []public.Account{other}, sameUser(nil, other))
+}
+
+func TestPurgeDirs(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "cache", "indev")
+	missing := filepath.Join(root, "state", "indev")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(existing, "http"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(existing, "http", "entry"), []byte("{}"), 0o600))
+
+	var buf bytes.Buffer
+
+	require.NoError(t, purgeDirs(&buf, []string{existing, missing}))
+
+	assert.NoDirExists(t, existing)
+	assert.Contains(t, buf.String(), "removed "+existing)
+	assert.NotContains(t, buf.String(), missing)
+}
+
+func TestForgetResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	}))
+	t.Cleanup(server.Close)
+
+	ada := public.Account{HomeAccountID: "ada.home", Realm: "home"}
+	grace := public.Account{HomeAccountID: "grace.home", Realm: "home"}
+	dir := t.TempDir()
+	transport := httpcache.New(dir, http.DefaultTransport)
+
+	for _, account := range []public.Account{ada, grace} {
+		ctx := httpcache.WithPartition(context.Background(), client.CachePartition(account))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	partitions, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, partitions, 2)
+
+	require.NoError(t, forgetResponses(dir, []public.Account{ada}, false))
+
+	partitions, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, partitions, 1, "the responses of other accounts are kept")
+
+	require.NoError(t, forgetResponses(dir, nil, true))
+	assert.NoDirExists(t, dir)
+}
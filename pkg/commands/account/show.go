@@ -44,6 +44,7 @@ func NewShowCommand(set clientset.ClientSet) *cobra.Command {
 			ux.Fprintf(cmd.OutOrStdout(), "Account information\n")
 			ux.Fprintf(cmd.OutOrStdout(), "Username: %s\n", account.PreferredUsername)
 			ux.Fprintf(cmd.OutOrStdout(), "Realm: %s\n", account.Realm)
+			ux.Fprintf(cmd.OutOrStdout(), "Source: %s\n", set.Authenticator.Source())
 			ux.Fprintf(cmd.OutOrStdout(), "Organization: %s\n", me.OrganizationName)
 			ux.Fprintf(cmd.OutOrStdout(), "Organization Role: %s\n", role)
 
@@ -0,0 +1,72 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/config"
+)
+
+func NewSwitchCommand(set clientset.ClientSet) *cobra.Command {
+	var tenant string
+
+	cmd := &cobra.Command{
+		Use:   "switch <username>",
+		Short: "Switch to another signed-in account",
+		Long: `Switch the account that commands use to another signed-in account.
+Use --tenant to pick between accounts with the same username in different tenants.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "account.switch")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			accounts, err := set.Authenticator.Accounts(ctx)
+			if err != nil {
+				return redact.Errorf("could not list accounts: %w", redact.Safe(err))
+			}
+
+			account, err := authenticator.FindAccount(accounts, args[0], tenant)
+			if err != nil {
+				return redact.Errorf("could not switch account: %w (use `%s login` to sign in)",
+					err, cmd.Root().Name())
+			}
+
+			if err = selectAccount(account.PreferredUsername, account.Realm); err != nil {
+				return err
+			}
+
+			ux.Fsuccessf(cmd.OutOrStdout(), "switched to %s (tenant %s)\n", account.PreferredUsername, account.Realm)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tenant, "tenant", "", "Tenant ID of the account")
+
+	return cmd
+}
+
+// selectAccount persists the account that commands use.
+func selectAccount(username, tenant string) error {
+	path := config.DefaultPath()
+
+	settings, err := config.Load(path)
+	if err != nil {
+		return redact.Errorf("could not read settings: %w", redact.Safe(err))
+	}
+
+	settings.Account = username
+	settings.Tenant = tenant
+
+	if err = settings.Save(path); err != nil {
+		return redact.Errorf("could not save selected account: %w", redact.Safe(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/internal/build"
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+var errTokenNotSupported = errors.New("access tokens cannot be printed with this credential")
+
+// tokenProvider is implemented by authenticators that hand out access
+// tokens, such as authenticator.TokenSource.
+type tokenProvider interface {
+	TokenFor(ctx context.Context, scopes []string, minValidity time.Duration) (public.AuthResult, error)
+}
+
+// accessToken is the structured output of the token command.
+type accessToken struct {
+	Token     string    `json:"token"     yaml:"token"`
+	ExpiresOn time.Time `json:"expiresOn" yaml:"expiresOn"`
+	Tenant    string    `json:"tenant"    yaml:"tenant"`
+	UPN       string    `json:"upn"       yaml:"upn"`
+	Scopes    []string  `json:"scopes"    yaml:"scopes"`
+}
+
+func NewTokenCommand(set clientset.ClientSet) *cobra.Command {
+	var (
+		output      outputformat.Format
+		scopes      []string
+		minValidity time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print an access token for the platform API",
+		Long: `Print an access token of the current account for the platform API, for
+scripts and tools that call the API directly.
+
+The token comes from the same cache and account as the tokens of other
+commands. Use --scope to get a token for another audience, and --min-validity
+to get a new token when the cached one expires sooner.`,
+		Args:    cobra.NoArgs,
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "account.token")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			provider, ok := set.Authenticator.(tokenProvider)
+			if !ok {
+				return errTokenNotSupported
+			}
+
+			result, err := provider.TokenFor(ctx, scopes, minValidity)
+			if err != nil {
+				return redact.Errorf("could not get access token: %w", redact.Safe(err))
+			}
+
+			token := accessToken{
+				Token:     result.AccessToken,
+				ExpiresOn: result.ExpiresOn,
+				Tenant:    result.Account.Realm,
+				UPN:       result.Account.PreferredUsername,
+				Scopes:    tokenScopes(result, scopes),
+			}
+
+			return printAccessToken(cmd.OutOrStdout(), output, token)
+		},
+	}
+
+	cmd.Flags().VarP(&output, "output", "o", "Output format (json, yaml)")
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "Scope to request the token for, instead of the platform API")
+	cmd.Flags().DurationVar(&minValidity, "min-validity", 0,
+		"Get a new token if the cached one expires within this duration, such as 30m")
+
+	return cmd
+}
+
+// tokenScopes returns the scopes that were granted, or the requested ones if
+// the identity provider did not say.
+func tokenScopes(result public.AuthResult, requested []string) []string {
+	if len(result.GrantedScopes) > 0 {
+		return result.GrantedScopes
+	}
+
+	if len(requested) > 0 {
+		return requested
+	}
+
+	return build.Scopes()
+}
+
+func printAccessToken(writer io.Writer, format outputformat.Format, token accessToken) error {
+	var err error
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(token)
+	case "yaml":
+		indent := 2
+		enc := yaml.NewEncoder(writer)
+		enc.SetIndent(indent)
+		err = enc.Encode(token)
+	default:
+		ux.Fprintf(writer, "%s\n", token.Token)
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package account
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/internal/build"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+func TestPrintAccessToken(t *testing.T) {
+	token := accessToken{
+		Token:     "eyJ0eXAi",
+		ExpiresOn: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tenant:    "tid",
+		UPN:       "ada@example.com",
+		Scopes:    []string{"api://platform/user_impersonation"},
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, printAccessToken(&buf, "", token))
+		assert.Equal(t, "eyJ0eXAi\n", buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, printAccessToken(&buf, outputformat.Format("json"), token))
+		assert.JSONEq(t, `{
+			"token": "eyJ0eXAi",
+			"expiresOn": "2026-01-02T03:04:05Z",
+			"tenant": "tid",
+			"upn": "ada@example.com",
+			"scopes": ["api://platform/user_impersonation"]
+		}`, buf.String())
+	})
+}
+
+func TestTokenScopes(t *testing.T) {
+	granted := public.AuthResult{GrantedScopes: []string{"granted"}}
+
+	assert.Equal(t, []string{"granted"}, tokenScopes(granted, []string{"requested"}))
+	assert.Equal(t, []string{"requested"}, tokenScopes(public.AuthResult{}, []string{"requested"}))
+	assert.Equal(t, build.Scopes(), tokenScopes(public.AuthResult{}, nil))
+}
@@ -13,12 +13,14 @@ import (
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/outputformat"
+	"github.com/intility/indev/pkg/pagination"
 )
 
 func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	var deployment string
 
 	output := outputformat.Format("")
+	options := client.ListOptions{}
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -29,6 +31,10 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "apikey.list")
 			defer span.End()
 
+			if err := pagination.Validate(options); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
 			if deployment == "" {
@@ -40,17 +46,32 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 				return redact.Errorf("could not find AI deployment: %w", redact.Safe(err))
 			}
 
-			keys, err := set.PlatformClient.ListAIAPIKeys(ctx, deploy.ID)
+			keys := set.PlatformClient.IterAIAPIKeys(ctx, deploy.ID, options)
+
+			if !output.IsStructured() {
+				count, err := pagination.StreamTable(cmd.OutOrStdout(), keys, options.PageSize, apiKeyColumns(output))
+				if err != nil {
+					return redact.Errorf("could not list API keys: %w", redact.Safe(err))
+				}
+
+				if count == 0 {
+					ux.Fprintf(cmd.OutOrStdout(), "No API keys found\n")
+				}
+
+				return nil
+			}
+
+			list, err := client.Collect(keys)
 			if err != nil {
 				return redact.Errorf("could not list API keys: %w", redact.Safe(err))
 			}
 
-			if len(keys) == 0 {
+			if len(list) == 0 {
 				ux.Fprintf(cmd.OutOrStdout(), "No API keys found\n")
 				return nil
 			}
 
-			if err = printAPIKeyList(cmd.OutOrStdout(), output, keys); err != nil {
+			if err = printAPIKeyList(cmd.OutOrStdout(), output, list); err != nil {
 				return redact.Errorf("could not print API keys: %w", redact.Safe(err))
 			}
 
@@ -60,6 +81,7 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 
 	cmd.Flags().StringVarP(&deployment, "deployment", "d", "", "Name of the AI deployment")
 	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+	pagination.AddFlags(cmd, &options)
 
 	return cmd
 }
@@ -77,8 +99,21 @@ func printAPIKeyList(writer io.Writer, format outputformat.Format, keys []client
 		enc := yaml.NewEncoder(writer)
 		enc.SetIndent(indent)
 		err = enc.Encode(keys)
-	case "wide":
-		table := ux.TableFromObjects(keys, func(k client.AIAPIKey) []ux.Row {
+	default:
+		table := ux.TableFromObjects(keys, apiKeyColumns(format))
+		ux.Fprintf(writer, "%s", table.String())
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+func apiKeyColumns(format outputformat.Format) ux.ColFactory[client.AIAPIKey] {
+	if format == "wide" {
+		return func(k client.AIAPIKey) []ux.Row {
 			return []ux.Row{
 				ux.NewRow("Name", k.Name),
 				ux.NewRow("Prefix", k.Prefix),
@@ -87,25 +122,15 @@ func printAPIKeyList(writer io.Writer, format outputformat.Format, keys []client
 				ux.NewRow("Expires At", k.ExpiresAt),
 				ux.NewRow("ID", k.ID),
 			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
-	default:
-		table := ux.TableFromObjects(keys, func(k client.AIAPIKey) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Name", k.Name),
-				ux.NewRow("Prefix", k.Prefix),
-				ux.NewRow("Created At", k.CreatedAt),
-				ux.NewRow("Expires At", k.ExpiresAt),
-			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
+		}
 	}
 
-	if err != nil {
-		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	return func(k client.AIAPIKey) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", k.Name),
+			ux.NewRow("Prefix", k.Prefix),
+			ux.NewRow("Created At", k.CreatedAt),
+			ux.NewRow("Expires At", k.ExpiresAt),
+		}
 	}
-
-	return nil
 }
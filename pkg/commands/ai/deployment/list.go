@@ -13,10 +13,12 @@ import (
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/outputformat"
+	"github.com/intility/indev/pkg/pagination"
 )
 
 func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	output := outputformat.Format("")
+	options := client.ListOptions{}
 	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List AI deployments",
@@ -26,19 +28,38 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "aideployment.list")
 			defer span.End()
 
+			if err := pagination.Validate(options); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
-			deployments, err := set.PlatformClient.ListAIDeployments(ctx)
+			deployments := set.PlatformClient.IterAIDeployments(ctx, options)
+
+			if !output.IsStructured() {
+				count, err := pagination.StreamTable(cmd.OutOrStdout(), deployments, options.PageSize, deploymentColumns(output))
+				if err != nil {
+					return redact.Errorf("could not list AI deployments: %w", redact.Safe(err))
+				}
+
+				if count == 0 {
+					ux.Fprintf(cmd.OutOrStdout(), "No AI deployments found\n")
+				}
+
+				return nil
+			}
+
+			list, err := client.Collect(deployments)
 			if err != nil {
 				return redact.Errorf("could not list AI deployments: %w", redact.Safe(err))
 			}
 
-			if len(deployments) == 0 {
+			if len(list) == 0 {
 				ux.Fprintf(cmd.OutOrStdout(), "No AI deployments found\n")
 				return nil
 			}
 
-			if err = printDeploymentList(cmd.OutOrStdout(), output, deployments); err != nil {
+			if err = printDeploymentList(cmd.OutOrStdout(), output, list); err != nil {
 				return redact.Errorf("could not print AI deployments: %w", redact.Safe(err))
 			}
 
@@ -47,6 +68,7 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	}
 
 	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+	pagination.AddFlags(cmd, &options)
 
 	return cmd
 }
@@ -64,8 +86,21 @@ func printDeploymentList(writer io.Writer, format outputformat.Format, deploymen
 		enc := yaml.NewEncoder(writer)
 		enc.SetIndent(indent)
 		err = enc.Encode(deployments)
-	case "wide":
-		table := ux.TableFromObjects(deployments, func(d client.AIDeployment) []ux.Row {
+	default:
+		table := ux.TableFromObjects(deployments, deploymentColumns(format))
+		ux.Fprintf(writer, "%s", table.String())
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+func deploymentColumns(format outputformat.Format) ux.ColFactory[client.AIDeployment] {
+	if format == "wide" {
+		return func(d client.AIDeployment) []ux.Row {
 			return []ux.Row{
 				ux.NewRow("Name", d.Name),
 				ux.NewRow("Model", d.Model),
@@ -74,24 +109,14 @@ func printDeploymentList(writer io.Writer, format outputformat.Format, deploymen
 				ux.NewRow("Created By UPN", d.CreatedBy.UPN),
 				ux.NewRow("ID", d.ID),
 			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
-	default:
-		table := ux.TableFromObjects(deployments, func(d client.AIDeployment) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Name", d.Name),
-				ux.NewRow("Model", d.Model),
-				ux.NewRow("Endpoint", d.Endpoint),
-			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
+		}
 	}
 
-	if err != nil {
-		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	return func(d client.AIDeployment) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", d.Name),
+			ux.NewRow("Model", d.Model),
+			ux.NewRow("Endpoint", d.Endpoint),
+		}
 	}
-
-	return nil
 }
@@ -2,6 +2,7 @@ package access
 
 import (
 	"context"
+	"errors"
 	"io"
 	"strings"
 
@@ -100,7 +101,7 @@ func runGrantCommand(ctx context.Context, out io.Writer, set clientset.ClientSet
 		},
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "409 Conflict") {
+		if errors.Is(err, client.ErrConflict) {
 			return redact.Errorf("%s %s already has access to cluster %s", subject.Type, subject.Name, options.Cluster)
 		}
 
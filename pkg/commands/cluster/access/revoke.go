@@ -2,14 +2,15 @@ package access
 
 import (
 	"context"
+	"errors"
 	"io"
-	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/intility/indev/internal/redact"
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 )
 
@@ -78,7 +79,7 @@ func runRevokeCommand(ctx context.Context, out io.Writer, set clientset.ClientSe
 
 	err = set.PlatformClient.RemoveClusterMember(ctx, options.ClusterID, memberID)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
+		if errors.Is(err, client.ErrNotFound) {
 			return redact.Errorf("%s %s does not have access to cluster %s", subject.Type, subject.Name, options.Cluster)
 		}
 
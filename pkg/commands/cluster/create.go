@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"slices"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,11 +18,12 @@ import (
 	"github.com/intility/indev/internal/wizard"
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/clusterspec"
 )
 
 const (
-	maxCount  = 8
-	minCount  = 2
+	maxCount  = clusterspec.MaxNodes
+	minCount  = clusterspec.MinNodes
 	answerYes = "yes"
 	answerNo  = "no"
 )
@@ -32,6 +36,7 @@ var (
 	errInvalidMinNodes   = redact.Errorf("invalid minimum node count: count must be between %d and %d", minCount, maxCount)
 	errInvalidMaxNodes   = redact.Errorf("invalid maximum node count: count must be between %d and %d", minCount, maxCount)
 	errMinGreaterThanMax = redact.Errorf("minimum node count cannot be greater than maximum node count")
+	errInvalidTimeout    = redact.Errorf("invalid wait timeout: timeout must be positive")
 )
 
 type CreateOptions struct {
@@ -41,15 +46,35 @@ type CreateOptions struct {
 	EnableAutoscaling bool
 	MinNodes          int // Used when autoscaling is enabled
 	MaxNodes          int // Used when autoscaling is enabled
+	Wait              bool
+	WaitTimeout       time.Duration
+	Login             bool   // Implies Wait
+	File              string // Cluster spec to create the cluster from, "-" for stdin
+	Version           string // OpenShift version, or empty for the default version
+	PullSecret        string // Name of the pull secret of the cluster, if any
 }
 
 func NewCreateCommand(set clientset.ClientSet) *cobra.Command {
 	var options CreateOptions
 
 	cmd := &cobra.Command{
-		Use:     "create",
-		Short:   "Create a new cluster",
-		Long:    `Create a new cluster with the specified configuration.`,
+		Use:   "create",
+		Short: "Create a new cluster",
+		Long: `Create a new cluster with the specified configuration.
+
+Use --file to create a cluster from a spec, which declares the node pools,
+version and other settings of the cluster in YAML or JSON. The spec of an
+existing cluster is printed by "indev cluster get -o spec".
+
+Use --wait to wait until the cluster is ready, while the progress of its
+deployment is shown. The command fails with the reason given by the platform
+if the deployment fails, and gives up after --wait-timeout. The global
+--timeout flag is not the wait timeout: it limits each request to the
+platform. Use --login to log in to the cluster with oc once it is ready.
+
+Use --pull-secret to pull images from private registries with the credentials
+of a pull secret, see "indev pullsecret create". A spec names its pull
+secret in pullSecretRef.`,
 		PreRunE: set.EnsureSignedInPreHook,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.create")
@@ -77,14 +102,47 @@ func NewCreateCommand(set clientset.ClientSet) *cobra.Command {
 	cmd.Flags().IntVar(&options.MaxNodes,
 		"max-nodes", maxCount, fmt.Sprintf("Maximum number of nodes when autoscaling is enabled (%d-%d)", minCount, maxCount))
 
+	cmd.Flags().StringVar(&options.Version,
+		"version", "", "OpenShift version of the cluster, defaults to the default version (see 'indev cluster versions')")
+
+	cmd.Flags().StringVar(&options.PullSecret,
+		"pull-secret", "", "Name of the pull secret used to pull images from private registries")
+
+	cmd.Flags().BoolVar(&options.Wait,
+		"wait", false, "Wait until the cluster is ready")
+
+	// --timeout is the global timeout of each request
+	cmd.Flags().DurationVar(&options.WaitTimeout, "wait-timeout", defaultWaitTimeout,
+		"How long to wait for the cluster to become ready (--timeout limits each request)")
+
+	cmd.Flags().BoolVar(&options.Login,
+		"login", false, "Wait until the cluster is ready and log in to it with oc")
+
+	cmd.Flags().StringVarP(&options.File,
+		"file", "f", "", "Cluster spec to create the cluster from (YAML or JSON, - for stdin)")
+
+	// a spec declares the whole cluster
+	specFlags := []string{
+		"name", "preset", "nodes", "enable-autoscaling", "min-nodes", "max-nodes", "version", "pull-secret",
+	}
+	for _, flag := range specFlags {
+		cmd.MarkFlagsMutuallyExclusive("file", flag)
+	}
+
 	return cmd
 }
 
 func runCreateCommand(ctx context.Context, cmd *cobra.Command, set clientset.ClientSet, options CreateOptions) error {
 	var err error
 
+	if options.File != "" {
+		return runCreateFromSpec(ctx, cmd, set, options)
+	}
+
 	if options.Name == "" {
-		options, err = optionsFromWizard()
+		var answers CreateOptions
+
+		answers, err = optionsFromWizard()
 		if err != nil {
 			if errors.Is(err, errCancelledByUser) {
 				return nil
@@ -92,6 +150,11 @@ func runCreateCommand(ctx context.Context, cmd *cobra.Command, set clientset.Cli
 
 			return redact.Errorf("could not get options from wizard: %w", redact.Safe(err))
 		}
+
+		// the wizard asks for the cluster, not for what happens after
+		answers.Wait, answers.WaitTimeout, answers.Login = options.Wait, options.WaitTimeout, options.Login
+		answers.Version, answers.PullSecret = options.Version, options.PullSecret
+		options = answers
 	}
 
 	err = validateOptions(options)
@@ -102,6 +165,17 @@ func runCreateCommand(ctx context.Context, cmd *cobra.Command, set clientset.Cli
 	// inputs validated, assume correct usage
 	cmd.SilenceUsage = true
 
+	if options.Version != "" {
+		if err = checkVersion(ctx, set, options.Version); err != nil {
+			return err
+		}
+	}
+
+	pullSecretRef, err := resolvePullSecret(ctx, set, options.PullSecret)
+	if err != nil {
+		return err
+	}
+
 	// Fetch SSO provisioner
 	ssoProvisioner, err := selectSSOProvisioner(ctx, set.PlatformClient, cmd.OutOrStdout())
 	if err != nil {
@@ -110,22 +184,125 @@ func runCreateCommand(ctx context.Context, cmd *cobra.Command, set clientset.Cli
 
 	nodePool := buildNodePool(options)
 
-	var cluster *client.Cluster
-
-	cluster, err = set.PlatformClient.CreateCluster(ctx, client.NewClusterRequest{
+	return createCluster(ctx, cmd, set, client.NewClusterRequest{
 		Name:           options.Name,
 		SSOProvisioner: ssoProvisioner,
 		NodePools:      []client.NodePool{nodePool},
-		Version:        "",
+		Version:        options.Version,
 		Environment:    "",
-		PullSecretRef:  nil,
-	})
+		PullSecretRef:  pullSecretRef,
+	}, options)
+}
+
+// resolvePullSecret returns the ID of the pull secret with the name, or nil
+// if no pull secret is named.
+func resolvePullSecret(ctx context.Context, set clientset.ClientSet, name string) (*string, error) {
+	if name == "" {
+		return nil, nil //nolint:nilnil // the cluster has no pull secret
+	}
+
+	secret, err := set.PlatformClient.GetPullSecret(ctx, name)
+	if err != nil {
+		return nil, redact.Errorf("could not find pull secret %s: %w", name, redact.Safe(err))
+	}
+
+	return &secret.ID, nil
+}
+
+// runCreateFromSpec creates the cluster declared in the spec file of the
+// options.
+func runCreateFromSpec(ctx context.Context, cmd *cobra.Command, set clientset.ClientSet, options CreateOptions) error {
+	if options.WaitTimeout <= 0 && (options.Wait || options.Login) {
+		return errInvalidTimeout
+	}
+
+	cmd.SilenceUsage = true
+
+	spec, err := readSpec(cmd.InOrStdin(), options.File)
+	if err != nil {
+		return err
+	}
+
+	if spec.Version != "" {
+		if err = checkVersion(ctx, set, spec.Version); err != nil {
+			return err
+		}
+	}
+
+	// the SSO provisioner of the spec is used as is
+	ssoProvisioner := spec.SSOProvisioner
+	if ssoProvisioner == "" {
+		ssoProvisioner, err = selectSSOProvisioner(ctx, set.PlatformClient, cmd.OutOrStdout())
+		if err != nil {
+			return redact.Errorf("could not select SSO provisioner: %w", redact.Safe(err))
+		}
+	}
+
+	pullSecretRef, err := resolvePullSecret(ctx, set, spec.PullSecretRef)
+	if err != nil {
+		return err
+	}
+
+	return createCluster(ctx, cmd, set, spec.Request(ssoProvisioner, pullSecretRef), options)
+}
+
+// readSpec reads and validates the cluster spec in the file at path, or in
+// stdin if the path is "-".
+func readSpec(stdin io.Reader, path string) (clusterspec.Spec, error) {
+	input := stdin
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return clusterspec.Spec{}, redact.Errorf("could not open cluster spec: %w", redact.Safe(err))
+		}
+
+		defer file.Close()
+
+		input = file
+	}
+
+	spec, err := clusterspec.Read(input)
+	if err != nil {
+		return spec, redact.Errorf("%w", redact.Safe(err))
+	}
+
+	if err = spec.Validate(); err != nil {
+		return spec, redact.Errorf("%s: %w", redact.Safe(path), redact.Safe(err))
+	}
+
+	return spec, nil
+}
+
+// createCluster sends the request, and waits for the cluster if asked to.
+func createCluster(
+	ctx context.Context,
+	cmd *cobra.Command,
+	set clientset.ClientSet,
+	request client.NewClusterRequest,
+	options CreateOptions,
+) error {
+	cluster, err := set.PlatformClient.CreateCluster(ctx, request)
 	if err != nil {
 		return redact.Errorf("could not create cluster: %w", redact.Safe(err))
 	}
 
 	ux.Fsuccessf(cmd.OutOrStdout(), "created cluster: %s\n", cluster.Name)
 
+	if !options.Wait && !options.Login {
+		return nil
+	}
+
+	if _, err = newWaiter(set.PlatformClient, cmd.ErrOrStderr()).wait(ctx, cluster, options.WaitTimeout); err != nil {
+		return err
+	}
+
+	ux.Fsuccessf(cmd.OutOrStdout(), "cluster %s is ready\n", cluster.Name)
+
+	if options.Login {
+		return runLoginCommand(ctx, cmd, set, cluster.Name)
+	}
+
 	return nil
 }
 
@@ -276,32 +453,45 @@ func getClusterWizardInputs() []wizard.Input {
 	}
 }
 
-//nolint:cyclop // validation logic is inherently sequential
 func validateOptions(options CreateOptions) error {
 	if options.Name == "" {
 		return errEmptyName
 	}
 
-	if !slices.Contains([]string{"minimal", "balanced", "performance"}, options.Preset) {
+	if (options.Wait || options.Login) && options.WaitTimeout <= 0 {
+		return errInvalidTimeout
+	}
+
+	return validateNodePool(buildNodePool(options))
+}
+
+// validateNodePool checks the preset and node counts of a node pool. Pools
+// with custom compute resources have no preset.
+//
+//nolint:cyclop // validation logic is inherently sequential
+func validateNodePool(pool client.NodePool) error {
+	if pool.Compute == nil && !slices.Contains(clusterspec.Presets, pool.Preset) {
 		return errInvalidPreset
 	}
 
-	if options.EnableAutoscaling {
-		// Validate autoscaling parameters
-		if options.MinNodes < minCount || options.MinNodes > maxCount {
-			return errInvalidMinNodes
+	if !pool.AutoscalingEnabled {
+		if pool.Replicas == nil || *pool.Replicas < minCount || *pool.Replicas > maxCount {
+			return errInvalidNodeCount
 		}
 
-		if options.MaxNodes < minCount || options.MaxNodes > maxCount {
-			return errInvalidMaxNodes
-		}
+		return nil
+	}
 
-		if options.MinNodes > options.MaxNodes {
-			return errMinGreaterThanMax
-		}
-	} else if options.NodeCount < minCount || options.NodeCount > maxCount {
-		// Validate fixed node count
-		return errInvalidNodeCount
+	if pool.MinCount == nil || *pool.MinCount < minCount || *pool.MinCount > maxCount {
+		return errInvalidMinNodes
+	}
+
+	if pool.MaxCount == nil || *pool.MaxCount < minCount || *pool.MaxCount > maxCount {
+		return errInvalidMaxNodes
+	}
+
+	if *pool.MinCount > *pool.MaxCount {
+		return errMinGreaterThanMax
 	}
 
 	return nil
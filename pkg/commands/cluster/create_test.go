@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/intility/indev/mocks"
 	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/clusterspec"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -316,3 +320,164 @@ func TestSelectSSOProvisioner(t *testing.T) {
 		})
 	}
 }
+
+func TestRunCreateFromSpec(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	spec := `apiVersion: indev.intility.com/v1
+kind: Cluster
+name: my-cluster
+version: "4.15"
+nodePools:
+  - name: workers
+    preset: balanced
+    replicas: 3
+  - name: gpu
+    compute:
+      cores: 16
+      memory: 64Gi
+    autoscalingEnabled: true
+    minCount: 2
+    maxCount: 4
+`
+
+	mc := mocks.NewClient(t)
+	mc.EXPECT().ListIntegrationInstances(mock.Anything).Return([]client.IntegrationInstance{
+		{ID: "prov-123", Type: "EntraID", Name: "My SSO"},
+	}, nil)
+	mc.EXPECT().ListClusterVersions(mock.Anything).Return([]client.ClusterVersion{
+		{Version: "4.15", Supported: true},
+	}, nil)
+	mc.EXPECT().CreateCluster(mock.Anything, client.NewClusterRequest{
+		Name:           "my-cluster",
+		SSOProvisioner: "prov-123",
+		NodePools: client.NodePools{
+			{Name: "workers", Preset: "balanced", Replicas: intPtr(3)},
+			{
+				Name:               "gpu",
+				Compute:            &client.ComputeResources{Cores: 16, Memory: "64Gi"},
+				AutoscalingEnabled: true,
+				MinCount:           intPtr(2),
+				MaxCount:           intPtr(4),
+			},
+		},
+		Version: "4.15",
+	}).Return(&client.Cluster{ID: "cluster-1", Name: "my-cluster"}, nil)
+
+	cmd := NewCreateCommand(clientset.ClientSet{PlatformClient: mc})
+
+	var out bytes.Buffer
+	cmd.SetIn(strings.NewReader(spec))
+	cmd.SetOut(&out)
+
+	err := runCreateFromSpec(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, CreateOptions{File: "-"})
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "created cluster: my-cluster")
+}
+
+func TestRunCreateFromSpec_Invalid(t *testing.T) {
+	spec := `apiVersion: indev.intility.com/v1
+kind: Cluster
+name: my-cluster
+nodePools:
+  - name: workers
+    preset: balanced
+    replicas: 3
+  - name: spare
+    preset: minimal
+    autoscalingEnabled: true
+    minCount: 2
+    maxCount: 12
+`
+
+	// no request is sent for an invalid spec
+	mc := mocks.NewClient(t)
+	cmd := NewCreateCommand(clientset.ClientSet{PlatformClient: mc})
+	cmd.SetIn(strings.NewReader(spec))
+
+	err := runCreateFromSpec(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, CreateOptions{File: "-"})
+	assert.ErrorIs(t, err, clusterspec.ErrInvalidSpec)
+	assert.Contains(t, err.Error(), "nodePools[1].maxCount")
+}
+
+func TestRunCreateFromSpec_PullSecret(t *testing.T) {
+	spec := `apiVersion: indev.intility.com/v1
+kind: Cluster
+name: my-cluster
+ssoProvisioner: prov-123
+pullSecretRef: quay
+nodePools:
+  - name: workers
+    preset: minimal
+    replicas: 2
+`
+
+	t.Run("references the named pull secret by its ID", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetPullSecret(mock.Anything, "quay").Return(&client.PullSecret{ID: "secret-1", Name: "quay"}, nil)
+		mc.EXPECT().CreateCluster(mock.Anything, mock.MatchedBy(func(request client.NewClusterRequest) bool {
+			return request.PullSecretRef != nil && *request.PullSecretRef == "secret-1"
+		})).Return(&client.Cluster{ID: "cluster-1", Name: "my-cluster"}, nil)
+
+		var out bytes.Buffer
+
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(spec))
+		cmd.SetOut(&out)
+
+		err := runCreateFromSpec(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc},
+			CreateOptions{File: "-"})
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "created cluster: my-cluster")
+	})
+
+	t.Run("fails for an unknown pull secret", func(t *testing.T) {
+		// the cluster is not created
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetPullSecret(mock.Anything, "quay").Return(nil, client.ErrNotFound)
+
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(spec))
+
+		err := runCreateFromSpec(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc},
+			CreateOptions{File: "-"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		assert.Contains(t, err.Error(), "could not find pull secret quay")
+	})
+}
+
+func TestRunCreateCommand_PullSecret(t *testing.T) {
+	options := CreateOptions{Name: "my-cluster", Preset: "minimal", NodeCount: 2, PullSecret: "quay"}
+
+	t.Run("references the pull secret by its ID", func(t *testing.T) {
+		pullSecretRef := "secret-1"
+
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetPullSecret(mock.Anything, "quay").Return(&client.PullSecret{ID: "secret-1", Name: "quay"}, nil)
+		mc.EXPECT().ListIntegrationInstances(mock.Anything).Return([]client.IntegrationInstance{
+			{ID: "prov-123", Type: "EntraID", Name: "My SSO"},
+		}, nil)
+		mc.EXPECT().CreateCluster(mock.Anything, mock.MatchedBy(func(request client.NewClusterRequest) bool {
+			return request.PullSecretRef != nil && *request.PullSecretRef == pullSecretRef
+		})).Return(&client.Cluster{ID: "cluster-1", Name: "my-cluster"}, nil)
+
+		var out bytes.Buffer
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+
+		err := runCreateCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, options)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "created cluster: my-cluster")
+	})
+
+	t.Run("fails for an unknown pull secret", func(t *testing.T) {
+		// the cluster is not created
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetPullSecret(mock.Anything, "quay").Return(nil, client.ErrNotFound)
+
+		err := runCreateCommand(context.Background(), &cobra.Command{}, clientset.ClientSet{PlatformClient: mc}, options)
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		assert.Contains(t, err.Error(), "could not find pull secret quay")
+	})
+}
@@ -1,45 +1,161 @@
 package cluster
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
+	"github.com/intility/indev/internal/redact"
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/internal/ux"
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/clusterspec"
 )
 
+var (
+	errInvalidGetFormat   = errors.New(`must be one of "json", "yaml", "spec"`)
+	errPullSecretNotFound = errors.New("pull secret not found")
+)
+
+// getFormat is the output format of the get command, which can also print a
+// cluster as a spec that creates it again.
+type getFormat string
+
+func (o *getFormat) String() string {
+	return string(*o)
+}
+
+func (o *getFormat) Set(value string) error {
+	switch value {
+	case "json", "yaml", "spec":
+		*o = getFormat(value)
+		return nil
+	default:
+		return errInvalidGetFormat
+	}
+}
+
+func (o *getFormat) Type() string {
+	return "outputFormat"
+}
+
 func NewGetCommand(set clientset.ClientSet) *cobra.Command {
-	var clusterName string
+	var (
+		clusterName string
+		output      getFormat
+	)
 
 	cmd := &cobra.Command{
-		Use:     "get [name]",
-		Short:   "Get detailed information about a cluster",
-		Long:    `Display comprehensive cluster information.`,
+		Use:   "get [name]",
+		Short: "Get detailed information about a cluster",
+		Long: `Display comprehensive cluster information.
+
+Use -o spec to print the cluster as a spec, which "indev cluster create -f"
+creates the same cluster from.`,
 		Args:    cobra.MaximumNArgs(1),
 		PreRunE: set.EnsureSignedInPreHook,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.get")
 			defer span.End()
 
-			return runClusterLookupCommand(ctx, lookupParams{
+			// the printer cannot fail, so encoding errors are kept aside
+			var printErr error
+
+			err := runClusterLookupCommand(ctx, lookupParams{
 				cmd:         cmd,
 				set:         set,
 				args:        args,
 				clusterName: clusterName,
-				printer:     printClusterDetails,
+				printer: func(writer io.Writer, cluster *client.Cluster) {
+					if output == "spec" {
+						printErr = printClusterSpec(ctx, writer, set, cluster)
+						return
+					}
+
+					printErr = printCluster(writer, output, cluster)
+				},
 			})
+			if err != nil {
+				return err
+			}
+
+			return printErr
 		},
 	}
 
 	cmd.Flags().StringVarP(&clusterName, "name", "n", "", "Name of the cluster")
+	cmd.Flags().VarP(&output, "output", "o", "Output format (json, yaml, spec)")
 
 	return cmd
 }
 
+func printCluster(writer io.Writer, format getFormat, cluster *client.Cluster) error {
+	var err error
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(cluster)
+	case "yaml":
+		indent := 2
+		enc := yaml.NewEncoder(writer)
+		enc.SetIndent(indent)
+		err = enc.Encode(cluster)
+	default:
+		printClusterDetails(writer, cluster)
+
+		return nil
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+// printClusterSpec prints the cluster as a spec. The pull secret of the
+// cluster is named, so that the spec can be used in other environments.
+func printClusterSpec(ctx context.Context, writer io.Writer, set clientset.ClientSet, cluster *client.Cluster) error {
+	pullSecret, err := pullSecretName(ctx, set, cluster.PullSecretRef)
+	if err != nil {
+		return err
+	}
+
+	if err = clusterspec.Write(writer, clusterspec.FromCluster(*cluster, pullSecret)); err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+// pullSecretName returns the name of the pull secret with the ID, or an empty
+// name if no ID is given.
+func pullSecretName(ctx context.Context, set clientset.ClientSet, id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+
+	for secret, err := range set.PlatformClient.IterPullSecrets(ctx, client.ListOptions{}) {
+		if err != nil {
+			return "", redact.Errorf("could not list pull secrets: %w", redact.Safe(err))
+		}
+
+		if secret.ID == id {
+			return secret.Name, nil
+		}
+	}
+
+	return "", redact.Errorf("could not find pull secret %s: %w", id, errPullSecretNotFound)
+}
+
 func printClusterDetails(writer io.Writer, cluster *client.Cluster) {
 	// Basic cluster information
 	ux.Fprintf(writer, "Cluster Information:\n")
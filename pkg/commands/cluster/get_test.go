@@ -2,11 +2,17 @@ package cluster
 
 import (
 	"bytes"
+	"context"
+	"iter"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
+	"github.com/intility/indev/mocks"
 	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/clusterspec"
 )
 
 func TestPrintClusterDetails(t *testing.T) {
@@ -218,3 +224,96 @@ func TestPrintClusterDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintClusterSpec(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	cluster := &client.Cluster{
+		ID:      "cluster-1",
+		Name:    "my-cluster",
+		Version: "4.15",
+		NodePools: client.NodePools{
+			{
+				ID:       "pool-1",
+				Name:     "workers",
+				Preset:   "balanced",
+				Replicas: intPtr(3),
+				Compute:  &client.ComputeResources{Cores: 8, Memory: "32Gi"},
+			},
+			{
+				ID:                 "pool-2",
+				Name:               "gpu",
+				Replicas:           intPtr(3),
+				Compute:            &client.ComputeResources{Cores: 16, Memory: "64Gi"},
+				AutoscalingEnabled: true,
+				MinCount:           intPtr(2),
+				MaxCount:           intPtr(4),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	// no pull secret is looked up for a cluster without one
+	err := printClusterSpec(context.Background(), &buf, clientset.ClientSet{PlatformClient: mocks.NewClient(t)}, cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: indev.intility.com/v1
+kind: Cluster
+name: my-cluster
+version: "4.15"
+nodePools:
+  - name: workers
+    preset: balanced
+    replicas: 3
+  - name: gpu
+    compute:
+      cores: 16
+      memory: 64Gi
+    autoscalingEnabled: true
+    minCount: 2
+    maxCount: 4
+`, buf.String())
+
+	// the spec creates the same cluster again
+	spec, err := clusterspec.Read(&buf)
+	assert.NoError(t, err)
+	assert.NoError(t, spec.Validate())
+}
+
+func TestPrintClusterSpec_PullSecret(t *testing.T) {
+	cluster := &client.Cluster{ID: "cluster-1", Name: "my-cluster", PullSecretRef: "secret-2"}
+	secrets := []client.PullSecret{{ID: "secret-1", Name: "ghcr"}, {ID: "secret-2", Name: "quay"}}
+
+	t.Run("names the pull secret", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().IterPullSecrets(mock.Anything, client.ListOptions{}).Return(pullSecretSeq(secrets))
+
+		var buf bytes.Buffer
+
+		err := printClusterSpec(context.Background(), &buf, clientset.ClientSet{PlatformClient: mc}, cluster)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "pullSecretRef: quay\n")
+		assert.NotContains(t, buf.String(), "secret-2")
+	})
+
+	t.Run("fails for a pull secret that is gone", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().IterPullSecrets(mock.Anything, client.ListOptions{}).Return(pullSecretSeq(secrets[:1]))
+
+		var buf bytes.Buffer
+
+		err := printClusterSpec(context.Background(), &buf, clientset.ClientSet{PlatformClient: mc}, cluster)
+		assert.ErrorIs(t, err, errPullSecretNotFound)
+		assert.Empty(t, buf.String())
+	})
+}
+
+func pullSecretSeq(secrets []client.PullSecret) iter.Seq2[client.PullSecret, error] {
+	return func(yield func(client.PullSecret, error) bool) {
+		for _, secret := range secrets {
+			if !yield(secret, nil) {
+				return
+			}
+		}
+	}
+}
@@ -42,16 +42,26 @@ func runClusterLookupCommand(ctx context.Context, params lookupParams) error {
 		return redact.Errorf("cluster name cannot be empty")
 	}
 
+	cluster, err := getCluster(ctx, set, clusterName)
+	if err != nil {
+		return err
+	}
+
+	printer(cmd.OutOrStdout(), cluster)
+
+	return nil
+}
+
+// getCluster returns the cluster with the given name.
+func getCluster(ctx context.Context, set clientset.ClientSet, clusterName string) (*client.Cluster, error) {
 	cluster, err := set.PlatformClient.GetCluster(ctx, clusterName)
 	if err != nil {
-		return redact.Errorf("could not get cluster: %w", redact.Safe(err))
+		return nil, redact.Errorf("could not get cluster: %w", redact.Safe(err))
 	}
 
 	if cluster == nil {
-		return redact.Errorf("cluster not found: %s", clusterName)
+		return nil, redact.Errorf("cluster not found: %s", clusterName)
 	}
 
-	printer(cmd.OutOrStdout(), cluster)
-
-	return nil
+	return cluster, nil
 }
@@ -14,10 +14,12 @@ import (
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/outputformat"
+	"github.com/intility/indev/pkg/pagination"
 )
 
 func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	output := outputformat.Format("")
+	options := client.ListOptions{}
 	// clusterListCmd represents the list command.
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -28,19 +30,44 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.list")
 			defer span.End()
 
+			if err := pagination.Validate(options); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
-			clusters, err := set.PlatformClient.ListClusters(ctx)
+			clusters := set.PlatformClient.IterClusters(ctx, options)
+
+			if !output.IsStructured() {
+				// clusters are listed without version markers if the versions
+				// cannot be fetched
+				versions, _ := set.PlatformClient.ListClusterVersions(ctx)
+
+				count, err := pagination.StreamTable(
+					cmd.OutOrStdout(), clusters, options.PageSize, clusterColumns(output, versions),
+				)
+				if err != nil {
+					return redact.Errorf("could not list clusters: %w", redact.Safe(err))
+				}
+
+				if count == 0 {
+					ux.Fprintf(cmd.OutOrStdout(), "No clusters found\n")
+				}
+
+				return nil
+			}
+
+			list, err := client.Collect(clusters)
 			if err != nil {
 				return redact.Errorf("could not list clusters: %w", redact.Safe(err))
 			}
 
-			if len(clusters) == 0 {
+			if len(list) == 0 {
 				ux.Fprintf(cmd.OutOrStdout(), "No clusters found\n")
 				return nil
 			}
 
-			if err = printClusterList(cmd.OutOrStdout(), output, clusters); err != nil {
+			if err = printClusterList(cmd.OutOrStdout(), output, list, nil); err != nil {
 				return redact.Errorf("could not print cluster list: %w", redact.Safe(err))
 			}
 
@@ -49,30 +76,20 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	}
 
 	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+	pagination.AddFlags(cmd, &options)
 
 	return cmd
 }
 
-func printClusterList(writer io.Writer, format outputformat.Format, clusters client.ClusterList) error {
+func printClusterList(
+	writer io.Writer,
+	format outputformat.Format,
+	clusters client.ClusterList,
+	versions []client.ClusterVersion,
+) error {
 	var err error
 
 	switch format {
-	case "wide":
-		table := ux.TableFromObjects(clusters, func(cluster client.Cluster) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Name", cluster.Name),
-				ux.NewRow("Version", cluster.Version),
-				ux.NewRow("Console URL", cluster.ConsoleURL),
-				ux.NewRow("Node Pools", nodePoolSummary(cluster)),
-				ux.NewRow("Status", statusString(cluster)),
-				ux.NewRow("Status Details", statusMessage(cluster)),
-				ux.NewRow("Roles", rolesString(cluster)),
-			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
-
-		return nil
 	case "json":
 		enc := json.NewEncoder(writer)
 		enc.SetIndent("", "  ")
@@ -83,15 +100,7 @@ func printClusterList(writer io.Writer, format outputformat.Format, clusters cli
 		enc.SetIndent(indent)
 		err = enc.Encode(clusters)
 	default:
-		table := ux.TableFromObjects(clusters, func(cluster client.Cluster) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Name", cluster.Name),
-				ux.NewRow("Version", cluster.Version),
-				ux.NewRow("Status", statusString(cluster)),
-				ux.NewRow("Node Pools", nodePoolSummary(cluster)),
-			}
-		})
-
+		table := ux.TableFromObjects(clusters, clusterColumns(format, versions))
 		ux.Fprintf(writer, "%s", table.String())
 
 		return nil
@@ -104,6 +113,43 @@ func printClusterList(writer io.Writer, format outputformat.Format, clusters cli
 	return nil
 }
 
+// clusterColumns returns the columns of the cluster table. Versions that are
+// outdated according to the versions that the platform offers are marked.
+func clusterColumns(format outputformat.Format, versions []client.ClusterVersion) ux.ColFactory[client.Cluster] {
+	if format == "wide" {
+		return func(cluster client.Cluster) []ux.Row {
+			return []ux.Row{
+				ux.NewRow("Name", cluster.Name),
+				ux.NewRow("Version", versionString(cluster, versions)),
+				ux.NewRow("Console URL", cluster.ConsoleURL),
+				ux.NewRow("Node Pools", nodePoolSummary(cluster)),
+				ux.NewRow("Status", statusString(cluster)),
+				ux.NewRow("Status Details", statusMessage(cluster)),
+				ux.NewRow("Roles", rolesString(cluster)),
+			}
+		}
+	}
+
+	return func(cluster client.Cluster) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", cluster.Name),
+			ux.NewRow("Version", versionString(cluster, versions)),
+			ux.NewRow("Status", statusString(cluster)),
+			ux.NewRow("Node Pools", nodePoolSummary(cluster)),
+		}
+	}
+}
+
+// versionString returns the version of the cluster, marked if it is
+// outdated or has reached its end of life.
+func versionString(cluster client.Cluster, versions []client.ClusterVersion) string {
+	if state := versionState(versions, cluster.Version); state != "" {
+		return cluster.Version + " (" + state + ")"
+	}
+
+	return cluster.Version
+}
+
 func statusString(cluster client.Cluster) string {
 	if cluster.Status.Ready.Status {
 		return "Ready"
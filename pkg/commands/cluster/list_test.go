@@ -254,7 +254,7 @@ func TestPrintClusterList(t *testing.T) {
 
 	t.Run("default format shows name, version, status, node pools", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := printClusterList(&buf, outputformat.Format(""), sampleClusters)
+		err := printClusterList(&buf, outputformat.Format(""), sampleClusters, nil)
 
 		assert.NoError(t, err)
 		output := buf.String()
@@ -266,9 +266,25 @@ func TestPrintClusterList(t *testing.T) {
 		assert.NotContains(t, output, "console.prod.example.com")
 	})
 
+	t.Run("default format marks outdated versions", func(t *testing.T) {
+		versions := []client.ClusterVersion{
+			{Version: "4.13", Supported: false},
+			{Version: "4.14", Supported: true},
+			{Version: "4.15", Supported: true, Default: true},
+		}
+
+		var buf bytes.Buffer
+		err := printClusterList(&buf, outputformat.Format(""), sampleClusters, versions)
+
+		assert.NoError(t, err)
+		output := buf.String()
+		assert.Contains(t, output, "4.14 (outdated)")
+		assert.Contains(t, output, "4.13 (end of life)")
+	})
+
 	t.Run("wide format includes console URL and roles", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := printClusterList(&buf, outputformat.Format("wide"), sampleClusters)
+		err := printClusterList(&buf, outputformat.Format("wide"), sampleClusters, nil)
 
 		assert.NoError(t, err)
 		output := buf.String()
@@ -279,7 +295,7 @@ func TestPrintClusterList(t *testing.T) {
 
 	t.Run("json format outputs valid JSON", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := printClusterList(&buf, outputformat.Format("json"), sampleClusters)
+		err := printClusterList(&buf, outputformat.Format("json"), sampleClusters, nil)
 
 		assert.NoError(t, err)
 
@@ -292,7 +308,7 @@ func TestPrintClusterList(t *testing.T) {
 
 	t.Run("yaml format outputs valid YAML", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := printClusterList(&buf, outputformat.Format("yaml"), sampleClusters)
+		err := printClusterList(&buf, outputformat.Format("yaml"), sampleClusters, nil)
 
 		assert.NoError(t, err)
 
@@ -304,7 +320,7 @@ func TestPrintClusterList(t *testing.T) {
 
 	t.Run("handles empty cluster list", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := printClusterList(&buf, outputformat.Format("json"), client.ClusterList{})
+		err := printClusterList(&buf, outputformat.Format("json"), client.ClusterList{}, nil)
 
 		assert.NoError(t, err)
 
@@ -0,0 +1,424 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+// nodePoolArgs are the arguments of commands on a node pool: the names of the
+// cluster and of the pool.
+const nodePoolArgs = 2
+
+var (
+	errNoNodePoolChanges = redact.Errorf("nothing to change: use --preset, --nodes, --min-nodes or --max-nodes")
+	errIncompleteRange   = redact.Errorf("both --min-nodes and --max-nodes are required to turn autoscaling on")
+)
+
+// nodePoolChanges are the settings of a node pool that a command changes,
+// where nil leaves a setting as it is.
+type nodePoolChanges struct {
+	preset   *string
+	nodes    *int
+	minNodes *int
+	maxNodes *int
+}
+
+// nodePoolFlags holds the flags of the commands that change node pools.
+type nodePoolFlags struct {
+	preset   string
+	nodes    int
+	minNodes int
+	maxNodes int
+}
+
+func NewNodePoolListCommand(set clientset.ClientSet) *cobra.Command {
+	output := outputformat.Format("")
+
+	cmd := &cobra.Command{
+		Use:     "list <cluster>",
+		Short:   "List the node pools of a cluster",
+		Long:    `List the node pools of a cluster, with their presets and sizes.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.nodepool.list")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			cluster, err := getCluster(ctx, set, args[0])
+			if err != nil {
+				return err
+			}
+
+			pools, err := set.PlatformClient.ListNodePools(ctx, cluster.ID)
+			if err != nil {
+				return redact.Errorf("could not list node pools: %w", redact.Safe(err))
+			}
+
+			if len(pools) == 0 {
+				ux.Fprintf(cmd.OutOrStdout(), "No node pools found\n")
+				return nil
+			}
+
+			return printNodePoolList(cmd.OutOrStdout(), output, pools)
+		},
+	}
+
+	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+
+	return cmd
+}
+
+func NewNodePoolAddCommand(set clientset.ClientSet) *cobra.Command {
+	var options CreateOptions
+
+	cmd := &cobra.Command{
+		Use:   "add <cluster> <pool>",
+		Short: "Add a node pool to a cluster",
+		Long: `Add a node pool to a cluster. The nodes of the pool use a preset, and their
+number is either fixed by --nodes or left to the autoscaler within
+--min-nodes and --max-nodes.`,
+		Args:    cobra.ExactArgs(nodePoolArgs),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.nodepool.add")
+			defer span.End()
+
+			pool := buildNodePool(options)
+			pool.Name = args[1]
+
+			if err := validateNodePool(pool); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			cluster, err := getCluster(ctx, set, args[0])
+			if err != nil {
+				return err
+			}
+
+			added, err := set.PlatformClient.AddNodePool(ctx, cluster.ID, pool)
+			if err != nil {
+				return redact.Errorf("could not add node pool: %w", redact.Safe(err))
+			}
+
+			ux.Fsuccessf(cmd.OutOrStdout(), "added node pool %s to cluster %s: %s\n",
+				nodePoolName(*added), cluster.Name, nodePoolSize(*added))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Preset,
+		"preset", "minimal", "Node preset to use (minimal, balanced, performance)")
+	cmd.Flags().IntVar(&options.NodeCount,
+		"nodes", minCount, fmt.Sprintf("Number of nodes (%d-%d)", minCount, maxCount))
+	cmd.Flags().BoolVar(&options.EnableAutoscaling,
+		"enable-autoscaling", false, "Enable autoscaling for the node pool")
+	cmd.Flags().IntVar(&options.MinNodes,
+		"min-nodes", minCount, fmt.Sprintf("Minimum number of nodes when autoscaling is enabled (%d-%d)", minCount, maxCount))
+	cmd.Flags().IntVar(&options.MaxNodes,
+		"max-nodes", maxCount, fmt.Sprintf("Maximum number of nodes when autoscaling is enabled (%d-%d)", minCount, maxCount))
+
+	return cmd
+}
+
+func NewNodePoolUpdateCommand(set clientset.ClientSet) *cobra.Command {
+	var flags nodePoolFlags
+
+	cmd := &cobra.Command{
+		Use:   "update <cluster> <pool>",
+		Short: "Change the preset or size of a node pool",
+		Long: `Change the preset or size of a node pool. Settings that are not given are
+left as they are.
+
+--nodes gives the pool a fixed number of nodes, and turns autoscaling off.
+--min-nodes and --max-nodes turn autoscaling on; both are required unless
+the pool is autoscaled already.`,
+		Args:    cobra.ExactArgs(nodePoolArgs),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.nodepool.update")
+			defer span.End()
+
+			return runUpdateNodePool(ctx, cmd, set, args[0], args[1], flags.changes(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.preset, "preset", "", "Node preset to use (minimal, balanced, performance)")
+	addNodeCountFlags(cmd, &flags)
+
+	return cmd
+}
+
+func NewNodePoolDeleteCommand(set clientset.ClientSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <cluster> <pool>",
+		Short: "Delete a node pool of a cluster",
+		Long: `Delete a node pool of a cluster, along with its nodes. The last node pool of
+a cluster cannot be deleted.`,
+		Args:    cobra.ExactArgs(nodePoolArgs),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.nodepool.delete")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			cluster, err := getCluster(ctx, set, args[0])
+			if err != nil {
+				return err
+			}
+
+			pool, err := findNodePool(cluster, args[1])
+			if err != nil {
+				return err
+			}
+
+			if err = set.PlatformClient.DeleteNodePool(ctx, cluster.ID, pool.ID); err != nil {
+				return redact.Errorf("could not delete node pool: %w", redact.Safe(err))
+			}
+
+			ux.Fsuccessf(cmd.OutOrStdout(), "deleted node pool %s of cluster %s\n", nodePoolName(pool), cluster.Name)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// addNodeCountFlags adds the flags that change the size of a node pool.
+func addNodeCountFlags(cmd *cobra.Command, flags *nodePoolFlags) {
+	cmd.Flags().IntVar(&flags.nodes,
+		"nodes", 0, fmt.Sprintf("Fixed number of nodes (%d-%d), turns autoscaling off", minCount, maxCount))
+	cmd.Flags().IntVar(&flags.minNodes,
+		"min-nodes", 0, fmt.Sprintf("Minimum number of nodes (%d-%d), turns autoscaling on", minCount, maxCount))
+	cmd.Flags().IntVar(&flags.maxNodes,
+		"max-nodes", 0, fmt.Sprintf("Maximum number of nodes (%d-%d), turns autoscaling on", minCount, maxCount))
+
+	cmd.MarkFlagsMutuallyExclusive("nodes", "min-nodes")
+	cmd.MarkFlagsMutuallyExclusive("nodes", "max-nodes")
+}
+
+// changes returns the settings that were given on the command line.
+func (f *nodePoolFlags) changes(cmd *cobra.Command) nodePoolChanges {
+	var changes nodePoolChanges
+
+	if cmd.Flags().Changed("preset") {
+		changes.preset = &f.preset
+	}
+
+	if cmd.Flags().Changed("nodes") {
+		changes.nodes = &f.nodes
+	}
+
+	if cmd.Flags().Changed("min-nodes") {
+		changes.minNodes = &f.minNodes
+	}
+
+	if cmd.Flags().Changed("max-nodes") {
+		changes.maxNodes = &f.maxNodes
+	}
+
+	return changes
+}
+
+func (c nodePoolChanges) empty() bool {
+	return c.preset == nil && c.nodes == nil && c.minNodes == nil && c.maxNodes == nil
+}
+
+// apply returns the pool with the changes applied.
+func (c nodePoolChanges) apply(pool client.NodePool) client.NodePool {
+	if c.preset != nil {
+		pool.Preset = *c.preset
+		pool.Compute = nil
+	}
+
+	switch {
+	case c.nodes != nil:
+		pool.AutoscalingEnabled = false
+		pool.Replicas = c.nodes
+		pool.MinCount, pool.MaxCount = nil, nil
+	case c.minNodes != nil || c.maxNodes != nil:
+		if !pool.AutoscalingEnabled {
+			pool.MinCount, pool.MaxCount = nil, nil
+		}
+
+		pool.AutoscalingEnabled = true
+		pool.Replicas = nil
+
+		if c.minNodes != nil {
+			pool.MinCount = c.minNodes
+		}
+
+		if c.maxNodes != nil {
+			pool.MaxCount = c.maxNodes
+		}
+	case pool.AutoscalingEnabled:
+		// autoscaled pools report their current size, which the autoscaler
+		// keeps changing
+		pool.Replicas = nil
+	}
+
+	return pool
+}
+
+// runUpdateNodePool applies the changes to the node pool with the given name
+// or ID. An empty pool name selects the only node pool of the cluster.
+func runUpdateNodePool(
+	ctx context.Context,
+	cmd *cobra.Command,
+	set clientset.ClientSet,
+	clusterName, poolName string,
+	changes nodePoolChanges,
+) error {
+	if changes.empty() {
+		return errNoNodePoolChanges
+	}
+
+	cmd.SilenceUsage = true
+
+	cluster, err := getCluster(ctx, set, clusterName)
+	if err != nil {
+		return err
+	}
+
+	pool, err := findNodePool(cluster, poolName)
+	if err != nil {
+		return err
+	}
+
+	if !pool.AutoscalingEnabled && changes.nodes == nil && (changes.minNodes == nil) != (changes.maxNodes == nil) {
+		return errIncompleteRange
+	}
+
+	pool = changes.apply(pool)
+	if err = validateNodePool(pool); err != nil {
+		return err
+	}
+
+	updated, err := set.PlatformClient.UpdateNodePool(ctx, cluster.ID, pool)
+	if err != nil {
+		return redact.Errorf("could not update node pool: %w", redact.Safe(err))
+	}
+
+	ux.Fsuccessf(cmd.OutOrStdout(), "updated node pool %s of cluster %s: %s\n",
+		nodePoolName(*updated), cluster.Name, nodePoolSize(*updated))
+
+	return nil
+}
+
+// findNodePool returns the node pool of the cluster with the given name or
+// ID, or its only node pool if the name is empty.
+func findNodePool(cluster *client.Cluster, name string) (client.NodePool, error) {
+	if name == "" {
+		if len(cluster.NodePools) != 1 {
+			return client.NodePool{}, redact.Errorf("cluster %s has %d node pools, use --pool to select one",
+				cluster.Name, len(cluster.NodePools))
+		}
+
+		return cluster.NodePools[0], nil
+	}
+
+	for _, pool := range cluster.NodePools {
+		if pool.Name == name || pool.ID == name {
+			return pool, nil
+		}
+	}
+
+	return client.NodePool{}, redact.Errorf("node pool %s not found in cluster %s", name, cluster.Name)
+}
+
+// nodePoolName returns the name of the pool, or its ID if it has no name.
+func nodePoolName(pool client.NodePool) string {
+	if pool.Name == "" {
+		return pool.ID
+	}
+
+	return pool.Name
+}
+
+// nodePoolSize describes the number of nodes of the pool.
+func nodePoolSize(pool client.NodePool) string {
+	switch {
+	case pool.AutoscalingEnabled && pool.MinCount != nil && pool.MaxCount != nil:
+		return fmt.Sprintf("%d-%d nodes (autoscaling)", *pool.MinCount, *pool.MaxCount)
+	case pool.Replicas != nil:
+		return fmt.Sprintf("%d nodes", *pool.Replicas)
+	default:
+		return "unknown"
+	}
+}
+
+// nodePoolPreset returns the preset of the pool, or the resources of its nodes
+// if they are custom.
+func nodePoolPreset(pool client.NodePool) string {
+	if pool.Compute != nil {
+		return fmt.Sprintf("%d cores, %s", pool.Compute.Cores, pool.Compute.Memory)
+	}
+
+	return pool.Preset
+}
+
+func printNodePoolList(writer io.Writer, format outputformat.Format, pools client.NodePools) error {
+	var err error
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(pools)
+	case "yaml":
+		indent := 2
+		enc := yaml.NewEncoder(writer)
+		enc.SetIndent(indent)
+		err = enc.Encode(pools)
+	default:
+		table := ux.TableFromObjects(pools, nodePoolColumns(format))
+		ux.Fprintf(writer, "%s", table.String())
+
+		return nil
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+func nodePoolColumns(format outputformat.Format) ux.ColFactory[client.NodePool] {
+	if format == "wide" {
+		return func(pool client.NodePool) []ux.Row {
+			return []ux.Row{
+				ux.NewRow("Name", nodePoolName(pool)),
+				ux.NewRow("ID", pool.ID),
+				ux.NewRow("Preset", nodePoolPreset(pool)),
+				ux.NewRow("Nodes", nodePoolSize(pool)),
+			}
+		}
+	}
+
+	return func(pool client.NodePool) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", nodePoolName(pool)),
+			ux.NewRow("Preset", nodePoolPreset(pool)),
+			ux.NewRow("Nodes", nodePoolSize(pool)),
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/intility/indev/mocks"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+func TestNodePoolChanges_Apply(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	strPtr := func(s string) *string { return &s }
+
+	fixed := client.NodePool{ID: "pool-1", Name: "workers", Preset: "minimal", Replicas: intPtr(2)}
+	autoscaled := client.NodePool{
+		ID:                 "pool-2",
+		Name:               "batch",
+		Preset:             "balanced",
+		Replicas:           intPtr(5),
+		AutoscalingEnabled: true,
+		MinCount:           intPtr(2),
+		MaxCount:           intPtr(6),
+	}
+
+	tests := []struct {
+		name    string
+		pool    client.NodePool
+		changes nodePoolChanges
+		want    client.NodePool
+	}{
+		{
+			name:    "nodes resizes a fixed pool",
+			pool:    fixed,
+			changes: nodePoolChanges{nodes: intPtr(6)},
+			want:    client.NodePool{ID: "pool-1", Name: "workers", Preset: "minimal", Replicas: intPtr(6)},
+		},
+		{
+			name:    "nodes turns autoscaling off",
+			pool:    autoscaled,
+			changes: nodePoolChanges{nodes: intPtr(4)},
+			want:    client.NodePool{ID: "pool-2", Name: "batch", Preset: "balanced", Replicas: intPtr(4)},
+		},
+		{
+			name:    "min and max turn autoscaling on",
+			pool:    fixed,
+			changes: nodePoolChanges{minNodes: intPtr(2), maxNodes: intPtr(8)},
+			want: client.NodePool{
+				ID:                 "pool-1",
+				Name:               "workers",
+				Preset:             "minimal",
+				AutoscalingEnabled: true,
+				MinCount:           intPtr(2),
+				MaxCount:           intPtr(8),
+			},
+		},
+		{
+			name:    "max keeps the min of an autoscaled pool",
+			pool:    autoscaled,
+			changes: nodePoolChanges{maxNodes: intPtr(8)},
+			want: client.NodePool{
+				ID:                 "pool-2",
+				Name:               "batch",
+				Preset:             "balanced",
+				AutoscalingEnabled: true,
+				MinCount:           intPtr(2),
+				MaxCount:           intPtr(8),
+			},
+		},
+		{
+			name: "preset replaces custom compute",
+			pool: client.NodePool{
+				ID:       "pool-3",
+				Compute:  &client.ComputeResources{Cores: 4, Memory: "16Gi"},
+				Replicas: intPtr(2),
+			},
+			changes: nodePoolChanges{preset: strPtr("performance")},
+			want:    client.NodePool{ID: "pool-3", Preset: "performance", Replicas: intPtr(2)},
+		},
+		{
+			name:    "preset leaves the size of an autoscaled pool to the autoscaler",
+			pool:    autoscaled,
+			changes: nodePoolChanges{preset: strPtr("performance")},
+			want: client.NodePool{
+				ID:                 "pool-2",
+				Name:               "batch",
+				Preset:             "performance",
+				AutoscalingEnabled: true,
+				MinCount:           intPtr(2),
+				MaxCount:           intPtr(6),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.changes.apply(tt.pool))
+		})
+	}
+}
+
+func TestFindNodePool(t *testing.T) {
+	single := &client.Cluster{Name: "demo", NodePools: client.NodePools{{ID: "pool-1"}}}
+	several := &client.Cluster{Name: "demo", NodePools: client.NodePools{
+		{ID: "pool-1", Name: "workers"},
+		{ID: "pool-2", Name: "gpu"},
+	}}
+
+	pool, err := findNodePool(single, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "pool-1", pool.ID)
+
+	_, err = findNodePool(several, "")
+	assert.ErrorContains(t, err, "cluster demo has 2 node pools, use --pool to select one")
+
+	pool, err = findNodePool(several, "gpu")
+	assert.NoError(t, err)
+	assert.Equal(t, "pool-2", pool.ID)
+
+	pool, err = findNodePool(several, "pool-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "workers", pool.Name)
+
+	_, err = findNodePool(several, "spare")
+	assert.ErrorContains(t, err, "node pool spare not found in cluster demo")
+}
+
+func TestRunUpdateNodePool(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	cluster := &client.Cluster{ID: "cluster-1", Name: "demo", NodePools: client.NodePools{
+		{ID: "pool-1", Name: "workers", Preset: "minimal", Replicas: intPtr(2)},
+	}}
+
+	tests := []struct {
+		name    string
+		changes nodePoolChanges
+		update  *client.NodePool
+		wantErr error
+		wantOut string
+	}{
+		{
+			name:    "scales the only node pool",
+			changes: nodePoolChanges{nodes: intPtr(6)},
+			update:  &client.NodePool{ID: "pool-1", Name: "workers", Preset: "minimal", Replicas: intPtr(6)},
+			wantOut: "updated node pool workers of cluster demo: 6 nodes",
+		},
+		{
+			name:    "rejects a node count out of range",
+			changes: nodePoolChanges{nodes: intPtr(9)},
+			wantErr: errInvalidNodeCount,
+		},
+		{
+			name:    "rejects a range without a minimum",
+			changes: nodePoolChanges{maxNodes: intPtr(6)},
+			wantErr: errIncompleteRange,
+		},
+		{
+			name:    "rejects a minimum above the maximum",
+			changes: nodePoolChanges{minNodes: intPtr(6), maxNodes: intPtr(4)},
+			wantErr: errMinGreaterThanMax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := mocks.NewClient(t)
+			mc.EXPECT().GetCluster(mock.Anything, "demo").Return(cluster, nil)
+
+			if tt.update != nil {
+				mc.EXPECT().UpdateNodePool(mock.Anything, "cluster-1", *tt.update).Return(tt.update, nil)
+			}
+
+			var out bytes.Buffer
+
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+
+			err := runUpdateNodePool(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc},
+				"demo", "", tt.changes)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Contains(t, out.String(), tt.wantOut)
+		})
+	}
+}
+
+func TestRunUpdateNodePool_NoChanges(t *testing.T) {
+	// nothing is requested from the platform
+	mc := mocks.NewClient(t)
+
+	err := runUpdateNodePool(context.Background(), &cobra.Command{}, clientset.ClientSet{PlatformClient: mc},
+		"demo", "", nodePoolChanges{})
+	assert.ErrorIs(t, err, errNoNodePoolChanges)
+}
@@ -0,0 +1,43 @@
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+func NewScaleCommand(set clientset.ClientSet) *cobra.Command {
+	var (
+		poolName string
+		flags    nodePoolFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scale <name>",
+		Short: "Change the number of nodes of a cluster",
+		Long: `Change the number of nodes of a node pool of a cluster, such as before and
+after a load test.
+
+--nodes gives the pool a fixed number of nodes, and turns autoscaling off.
+--min-nodes and --max-nodes turn autoscaling on; both are required unless
+the pool is autoscaled already. --pool selects the node pool, and can be left
+out for clusters with a single node pool.`,
+		Example: `  indev cluster scale my-cluster --nodes 6
+  indev cluster scale my-cluster --pool workers --min-nodes 2 --max-nodes 8`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.scale")
+			defer span.End()
+
+			return runUpdateNodePool(ctx, cmd, set, args[0], poolName, flags.changes(cmd))
+		},
+	}
+
+	cmd.Flags().StringVar(&poolName, "pool", "", "Name or ID of the node pool to scale")
+	addNodeCountFlags(cmd, &flags)
+	cmd.MarkFlagsOneRequired("nodes", "min-nodes", "max-nodes")
+
+	return cmd
+}
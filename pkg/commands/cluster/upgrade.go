@@ -0,0 +1,210 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/cli"
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+var errConfirmationRequired = redact.Errorf("use --yes to upgrade without a terminal to confirm on")
+
+type UpgradeOptions struct {
+	Version     string
+	Yes         bool
+	Wait        bool
+	WaitTimeout time.Duration
+}
+
+func NewUpgradeCommand(set clientset.ClientSet) *cobra.Command {
+	var options UpgradeOptions
+
+	cmd := &cobra.Command{
+		Use:   "upgrade <name>",
+		Short: "Upgrade a cluster to another OpenShift version",
+		Long: `Upgrade a cluster to a newer OpenShift version, after confirming.
+
+The upgrade is checked before it starts: the cluster must be ready, and the
+version must be offered by the platform and newer than the current one.
+Clusters are upgraded one minor version at a time. See "indev cluster
+versions" for the versions that the platform offers.
+
+Use --wait to wait until the upgrade is done, while its progress is shown.
+The upgrade is done once the cluster runs the new version and is ready. The
+command gives up after --wait-timeout; the global --timeout flag limits each
+request to the platform instead.`,
+		Example: `  indev cluster upgrade my-cluster --to 4.18 --wait`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.upgrade")
+			defer span.End()
+
+			return runUpgradeCommand(ctx, cmd, set, args[0], options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Version, "to", "", "Version to upgrade to")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", false, "Upgrade without asking for confirmation")
+	cmd.Flags().BoolVar(&options.Wait, "wait", false, "Wait until the upgrade is done")
+	cmd.Flags().DurationVar(&options.WaitTimeout, "wait-timeout", defaultWaitTimeout,
+		"How long to wait for the upgrade to be done (--timeout limits each request)")
+
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func runUpgradeCommand(
+	ctx context.Context,
+	cmd *cobra.Command,
+	set clientset.ClientSet,
+	name string,
+	options UpgradeOptions,
+) error {
+	if options.Wait && options.WaitTimeout <= 0 {
+		return errInvalidTimeout
+	}
+
+	cmd.SilenceUsage = true
+
+	cluster, err := getCluster(ctx, set, name)
+	if err != nil {
+		return err
+	}
+
+	versions, err := set.PlatformClient.ListClusterVersions(ctx)
+	if err != nil {
+		return redact.Errorf("could not list versions: %w", redact.Safe(err))
+	}
+
+	if err = checkUpgrade(cluster, versions, options.Version); err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		question := fmt.Sprintf("Upgrade cluster %s from %s to %s?", cluster.Name, cluster.Version, options.Version)
+
+		confirmed, err := cli.Confirm(cmd.InOrStdin(), cmd.OutOrStdout(), question)
+		if errors.Is(err, cli.ErrNotInteractive) {
+			return errConfirmationRequired
+		}
+
+		if err != nil {
+			return redact.Errorf("%w", redact.Safe(err))
+		}
+
+		if !confirmed {
+			ux.Fprintf(cmd.OutOrStdout(), "Upgrade cancelled\n")
+			return nil
+		}
+	}
+
+	request := client.UpdateClusterRequest{Version: options.Version}
+
+	upgraded, err := set.PlatformClient.UpdateCluster(ctx, cluster.ID, request)
+	if err != nil {
+		return redact.Errorf("could not upgrade cluster: %w", redact.Safe(err))
+	}
+
+	ux.Fsuccessf(cmd.OutOrStdout(), "upgrading cluster %s to %s\n", cluster.Name, options.Version)
+
+	if !options.Wait {
+		return nil
+	}
+
+	status, err := newWaiter(set.PlatformClient, cmd.ErrOrStderr()).
+		waitFor(ctx, upgraded, options.WaitTimeout, upgradeGoal(options.Version))
+	if err != nil {
+		return err
+	}
+
+	ux.Fsuccessf(cmd.OutOrStdout(), "cluster %s runs %s\n", cluster.Name, status.Version)
+
+	return nil
+}
+
+// upgradeGoal is reached once the cluster is ready and runs the version, or
+// is ready again after it stopped being ready during the upgrade. A cluster
+// that is still ready right after the upgrade was requested may not have
+// started upgrading yet.
+func upgradeGoal(version string) goal {
+	leftReady := false
+
+	return goal{
+		reached: func(status *client.Cluster) bool {
+			if !status.Status.Ready.Status {
+				leftReady = true
+
+				return false
+			}
+
+			return leftReady || runsVersion(status.Version, version)
+		},
+		describe: func(name string, status *client.Cluster) string {
+			state := "Upgrading " + name + " to " + version
+			if !status.Status.Ready.Status && status.Status.Ready.Message != "" {
+				state += ": " + status.Status.Ready.Message
+			}
+
+			return state
+		},
+	}
+}
+
+// runsVersion reports whether current is the version, or a patch version of
+// it.
+func runsVersion(current, version string) bool {
+	return compareVersions(current, version) == 0 || strings.HasPrefix(current, version+".")
+}
+
+// checkUpgrade fails with the reason why the cluster cannot be upgraded to
+// the version.
+//
+//nolint:cyclop // pre-flight checks are inherently sequential
+func checkUpgrade(cluster *client.Cluster, versions []client.ClusterVersion, version string) error {
+	switch {
+	case cluster.Status.Deployment.Active:
+		return redact.Errorf("cluster %s is being deployed, wait until it is ready", cluster.Name)
+	case cluster.Status.Deployment.Failed:
+		return redact.Errorf("cluster %s failed to deploy and cannot be upgraded", cluster.Name)
+	case !cluster.Status.Ready.Status:
+		return redact.Errorf("cluster %s is not ready, see `indev cluster status %s`", cluster.Name, cluster.Name)
+	}
+
+	target, ok := findVersion(versions, version)
+
+	switch {
+	case !ok:
+		return redact.Errorf("version %s is not offered, see `indev cluster versions`", version)
+	case !target.Supported:
+		return redact.Errorf("version %s has reached its end of life", version)
+	case runsVersion(cluster.Version, version):
+		return redact.Errorf("cluster %s already runs %s", cluster.Name, version)
+	case compareVersions(version, cluster.Version) < 0:
+		return redact.Errorf("cluster %s runs %s, which is newer than %s: downgrades are not supported",
+			cluster.Name, cluster.Version, version)
+	}
+
+	major, minor, okCurrent := minorVersion(cluster.Version)
+	targetMajor, targetMinor, okTarget := minorVersion(version)
+
+	if okCurrent && okTarget && (targetMajor != major || targetMinor > minor+1) {
+		next := fmt.Sprintf("%d.%d", major, minor+1)
+
+		return redact.Errorf("clusters are upgraded one minor version at a time: upgrade %s to %s first",
+			cluster.Name, next)
+	}
+
+	return nil
+}
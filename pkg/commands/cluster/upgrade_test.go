@@ -0,0 +1,217 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/mocks"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+func TestCheckUpgrade(t *testing.T) {
+	ready := client.ClusterStatus{Ready: client.StatusReady{Status: true}}
+
+	tests := []struct {
+		name      string
+		cluster   client.Cluster
+		version   string
+		errSubstr string
+	}{
+		{
+			name:    "allows the next minor version",
+			cluster: client.Cluster{Name: "demo", Version: "4.16", Status: ready},
+			version: "4.17",
+		},
+		{
+			name:    "allows the next minor version from a patch version",
+			cluster: client.Cluster{Name: "demo", Version: "4.16.12", Status: ready},
+			version: "4.17",
+		},
+		{
+			name: "rejects a cluster in deployment",
+			cluster: client.Cluster{Name: "demo", Version: "4.16", Status: client.ClusterStatus{
+				Deployment: client.StatusDeployment{Active: true},
+			}},
+			version:   "4.17",
+			errSubstr: "cluster demo is being deployed",
+		},
+		{
+			name: "rejects a failed cluster",
+			cluster: client.Cluster{Name: "demo", Version: "4.16", Status: client.ClusterStatus{
+				Deployment: client.StatusDeployment{Failed: true},
+			}},
+			version:   "4.17",
+			errSubstr: "cluster demo failed to deploy",
+		},
+		{
+			name:      "rejects a version that is not offered",
+			cluster:   client.Cluster{Name: "demo", Version: "4.16", Status: ready},
+			version:   "4.99",
+			errSubstr: "version 4.99 is not offered",
+		},
+		{
+			name:      "rejects a version at its end of life",
+			cluster:   client.Cluster{Name: "demo", Version: "4.14", Status: ready},
+			version:   "4.15",
+			errSubstr: "version 4.15 has reached its end of life",
+		},
+		{
+			name:      "rejects the current version",
+			cluster:   client.Cluster{Name: "demo", Version: "4.17.3", Status: ready},
+			version:   "4.17",
+			errSubstr: "cluster demo already runs 4.17",
+		},
+		{
+			name:      "rejects a downgrade",
+			cluster:   client.Cluster{Name: "demo", Version: "4.18", Status: ready},
+			version:   "4.17",
+			errSubstr: "downgrades are not supported",
+		},
+		{
+			name:      "rejects skipping a minor version",
+			cluster:   client.Cluster{Name: "demo", Version: "4.16", Status: ready},
+			version:   "4.18",
+			errSubstr: "upgrade demo to 4.17 first",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUpgrade(&tt.cluster, sampleVersions(), tt.version)
+
+			if tt.errSubstr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.errSubstr)
+		})
+	}
+}
+
+func TestRunUpgradeCommand(t *testing.T) {
+	cluster := &client.Cluster{
+		ID:      "cluster-1",
+		Name:    "demo",
+		Version: "4.16",
+		Status:  client.ClusterStatus{Ready: client.StatusReady{Status: true}},
+	}
+
+	t.Run("upgrades with --yes", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetCluster(mock.Anything, "demo").Return(cluster, nil)
+		mc.EXPECT().ListClusterVersions(mock.Anything).Return(sampleVersions(), nil)
+		mc.EXPECT().UpdateCluster(mock.Anything, "cluster-1", client.UpdateClusterRequest{Version: "4.17"}).
+			Return(cluster, nil)
+
+		var out bytes.Buffer
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+
+		err := runUpgradeCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, "demo",
+			UpgradeOptions{Version: "4.17", Yes: true})
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "upgrading cluster demo to 4.17")
+	})
+
+	t.Run("waits for the upgrade with --wait", func(t *testing.T) {
+		upgraded := &client.Cluster{
+			ID:      "cluster-1",
+			Name:    "demo",
+			Version: "4.17.2",
+			Status:  client.ClusterStatus{Ready: client.StatusReady{Status: true}},
+		}
+
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetCluster(mock.Anything, "demo").Return(cluster, nil)
+		mc.EXPECT().ListClusterVersions(mock.Anything).Return(sampleVersions(), nil)
+		mc.EXPECT().UpdateCluster(mock.Anything, "cluster-1", client.UpdateClusterRequest{Version: "4.17"}).
+			Return(cluster, nil)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-1").Return(upgraded, nil).Once()
+
+		var out bytes.Buffer
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+
+		err := runUpgradeCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, "demo",
+			UpgradeOptions{Version: "4.17", Yes: true, Wait: true, WaitTimeout: time.Minute})
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "cluster demo runs 4.17.2")
+	})
+
+	t.Run("asks for --yes without a terminal", func(t *testing.T) {
+		// the cluster is not upgraded
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetCluster(mock.Anything, "demo").Return(cluster, nil)
+		mc.EXPECT().ListClusterVersions(mock.Anything).Return(sampleVersions(), nil)
+
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader("yes\n"))
+
+		err := runUpgradeCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, "demo",
+			UpgradeOptions{Version: "4.17"})
+		assert.ErrorIs(t, err, errConfirmationRequired)
+	})
+}
+
+func TestUpgradeGoal(t *testing.T) {
+	ready := client.StatusReady{Status: true, Message: "Cluster is ready", Reason: "Ready"}
+	upgrading := client.StatusReady{Status: false, Message: "Cluster is being upgraded", Reason: "Upgrading"}
+
+	withStatus := func(version string, status client.StatusReady) *client.Cluster {
+		return &client.Cluster{ID: "cluster-id", Name: "demo", Version: version, Status: client.ClusterStatus{
+			Ready: status,
+		}}
+	}
+
+	t.Run("waits until the upgrade has started and is done", func(t *testing.T) {
+		// the platform reports the cluster as ready until the upgrade starts
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(withStatus("4.16", ready), nil).Once()
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(withStatus("4.16", upgrading), nil).Once()
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(withStatus("4.17", ready), nil).Once()
+
+		var out bytes.Buffer
+
+		status, err := testWaiter(mc, &out).
+			waitFor(context.Background(), withStatus("4.16", ready), time.Minute, upgradeGoal("4.17"))
+		require.NoError(t, err)
+		assert.Equal(t, "4.17", status.Version)
+		assert.Equal(t,
+			"[0s] Upgrading demo to 4.17\n[0s] Upgrading demo to 4.17: Cluster is being upgraded\n",
+			out.String())
+	})
+
+	t.Run("is done once the cluster is ready again", func(t *testing.T) {
+		// whichever version it reports, as the cluster has been upgraded
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(withStatus("4.16", upgrading), nil).Once()
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(withStatus("4.16.9", ready), nil).Once()
+
+		status, err := testWaiter(mc, &bytes.Buffer{}).
+			waitFor(context.Background(), withStatus("4.16", ready), time.Minute, upgradeGoal("4.17"))
+		require.NoError(t, err)
+		assert.Equal(t, "4.16.9", status.Version)
+	})
+
+	t.Run("times out while the upgrade has not started", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(withStatus("4.16", ready), nil)
+
+		_, err := testWaiter(mc, &bytes.Buffer{}).
+			waitFor(context.Background(), withStatus("4.16", ready), 20*time.Millisecond, upgradeGoal("4.17"))
+		require.ErrorIs(t, err, errWaitTimeout)
+	})
+}
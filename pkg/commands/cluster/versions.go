@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+// Version states of a cluster, as shown by the list command.
+const (
+	versionOutdated  = "outdated"
+	versionEndOfLife = "end of life"
+)
+
+func NewVersionsCommand(set clientset.ClientSet) *cobra.Command {
+	output := outputformat.Format("")
+
+	cmd := &cobra.Command{
+		Use:   "versions",
+		Short: "List the OpenShift versions that clusters can run",
+		Long: `List the OpenShift versions that the platform offers, with the default
+version of new clusters and the end of life of each version.`,
+		Args:    cobra.NoArgs,
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "cluster.versions")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			versions, err := set.PlatformClient.ListClusterVersions(ctx)
+			if err != nil {
+				return redact.Errorf("could not list versions: %w", redact.Safe(err))
+			}
+
+			if len(versions) == 0 {
+				ux.Fprintf(cmd.OutOrStdout(), "No versions found\n")
+				return nil
+			}
+
+			return printVersionList(cmd.OutOrStdout(), output, sortVersions(versions))
+		},
+	}
+
+	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+
+	return cmd
+}
+
+func printVersionList(writer io.Writer, format outputformat.Format, versions []client.ClusterVersion) error {
+	var err error
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(versions)
+	case "yaml":
+		indent := 2
+		enc := yaml.NewEncoder(writer)
+		enc.SetIndent(indent)
+		err = enc.Encode(versions)
+	default:
+		table := ux.TableFromObjects(versions, versionColumns)
+		ux.Fprintf(writer, "%s", table.String())
+
+		return nil
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+func versionColumns(version client.ClusterVersion) []ux.Row {
+	isDefault := ""
+	if version.Default {
+		isDefault = "yes"
+	}
+
+	status := "supported"
+	if !version.Supported {
+		status = versionEndOfLife
+	}
+
+	endOfLife := ""
+	if version.EndOfLife != nil {
+		endOfLife = version.EndOfLife.Format("2006-01-02")
+	}
+
+	return []ux.Row{
+		ux.NewRow("Version", version.Version),
+		ux.NewRow("Default", isDefault),
+		ux.NewRow("Status", status),
+		ux.NewRow("End of Life", endOfLife),
+	}
+}
+
+// checkVersion fails unless new clusters can be created with the version.
+func checkVersion(ctx context.Context, set clientset.ClientSet, version string) error {
+	versions, err := set.PlatformClient.ListClusterVersions(ctx)
+	if err != nil {
+		return redact.Errorf("could not list versions: %w", redact.Safe(err))
+	}
+
+	offered, ok := findVersion(versions, version)
+
+	switch {
+	case !ok:
+		return redact.Errorf("version %s is not offered, see `indev cluster versions`", version)
+	case !offered.Supported:
+		return redact.Errorf("version %s has reached its end of life, see `indev cluster versions`", version)
+	default:
+		return nil
+	}
+}
+
+// findVersion returns the version of the catalog that a cluster version,
+// such as 4.17.3, belongs to.
+func findVersion(versions []client.ClusterVersion, version string) (client.ClusterVersion, bool) {
+	for _, offered := range versions {
+		if offered.Version == version || strings.HasPrefix(version, offered.Version+".") {
+			return offered, true
+		}
+	}
+
+	return client.ClusterVersion{}, false
+}
+
+// versionState tells whether a cluster runs a version that has reached its
+// end of life, or one older than the default version, and is empty
+// otherwise.
+func versionState(versions []client.ClusterVersion, version string) string {
+	if len(versions) == 0 || version == "" {
+		return ""
+	}
+
+	offered, ok := findVersion(versions, version)
+	if !ok || !offered.Supported {
+		return versionEndOfLife
+	}
+
+	for _, candidate := range versions {
+		if candidate.Default && compareVersions(offered.Version, candidate.Version) < 0 {
+			return versionOutdated
+		}
+	}
+
+	return ""
+}
+
+// sortVersions sorts versions from the newest to the oldest.
+func sortVersions(versions []client.ClusterVersion) []client.ClusterVersion {
+	slices.SortStableFunc(versions, func(a, b client.ClusterVersion) int {
+		return compareVersions(b.Version, a.Version)
+	})
+
+	return versions
+}
+
+// compareVersions compares dotted versions such as 4.9 and 4.17 by their
+// numeric components.
+func compareVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := range max(len(partsA), len(partsB)) {
+		if i >= len(partsA) {
+			return -1
+		}
+
+		if i >= len(partsB) {
+			return 1
+		}
+
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+
+		if errA != nil || errB != nil {
+			if c := strings.Compare(partsA[i], partsB[i]); c != 0 {
+				return c
+			}
+
+			continue
+		}
+
+		if numA != numB {
+			if numA < numB {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// minorVersion returns the major and minor component of a version, such as
+// 4.17 for 4.17.3.
+func minorVersion(version string) (int, int, bool) {
+	parts := strings.SplitN(version, ".", 3) //nolint:mnd // major, minor and the rest
+
+	if len(parts) < 2 { //nolint:mnd // major and minor
+		return 0, 0, false
+	}
+
+	major, errMajor := strconv.Atoi(parts[0])
+	minor, errMinor := strconv.Atoi(parts[1])
+
+	return major, minor, errMajor == nil && errMinor == nil
+}
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+// sampleVersions is a catalog where 4.17 is the default version and 4.15 has
+// reached its end of life.
+func sampleVersions() []client.ClusterVersion {
+	endOfLife := time.Date(2025, time.August, 27, 0, 0, 0, 0, time.UTC)
+
+	return []client.ClusterVersion{
+		{Version: "4.15", Supported: false, EndOfLife: &endOfLife},
+		{Version: "4.16", Supported: true},
+		{Version: "4.17", Supported: true, Default: true},
+		{Version: "4.18", Supported: true},
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"4.17", "4.17", 0},
+		{"4.9", "4.17", -1},
+		{"4.17", "4.9", 1},
+		{"4.17", "4.17.3", -1},
+		{"4.17.10", "4.17.9", 1},
+		{"5.0", "4.18", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, compareVersions(tt.a, tt.b))
+		})
+	}
+}
+
+func TestVersionState(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"4.18", ""},
+		{"4.17", ""},
+		{"4.17.3", ""},
+		{"4.16", versionOutdated},
+		{"4.16.12", versionOutdated},
+		{"4.15", versionEndOfLife},
+		{"4.12", versionEndOfLife},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, versionState(sampleVersions(), tt.version))
+		})
+	}
+
+	// without a catalog, nothing is known to be outdated
+	assert.Empty(t, versionState(nil, "4.12"))
+}
+
+func TestPrintVersionList(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := printVersionList(&buf, outputformat.Format(""), sortVersions(sampleVersions()))
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("4.18")), bytes.Index(buf.Bytes(), []byte("4.15")))
+	assert.Contains(t, output, "end of life")
+	assert.Contains(t, output, "2025-08-27")
+}
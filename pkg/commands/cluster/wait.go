@@ -0,0 +1,148 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+)
+
+const (
+	defaultWaitTimeout = 30 * time.Minute
+	// the status is polled often at first, as failures show up quickly, and
+	// less often while the cluster is being deployed
+	initialPollInterval = 2 * time.Second
+	maxPollInterval     = 30 * time.Second
+	pollBackoff         = 1.5
+)
+
+var (
+	errProvisioningFailed = errors.New("cluster provisioning failed")
+	errWaitTimeout        = errors.New("timed out waiting for the cluster to become ready")
+)
+
+// waiter polls the status of a cluster until it is ready.
+type waiter struct {
+	platformClient client.Client
+	progress       *ux.Progress
+	interval       time.Duration
+	maxInterval    time.Duration
+}
+
+func newWaiter(platformClient client.Client, out io.Writer) waiter {
+	return waiter{
+		platformClient: platformClient,
+		progress:       ux.NewProgress(out),
+		interval:       initialPollInterval,
+		maxInterval:    maxPollInterval,
+	}
+}
+
+// goal is what a waiter waits for a cluster to reach.
+type goal struct {
+	// reached reports whether the cluster has reached the goal.
+	reached func(status *client.Cluster) bool
+	// describe describes the progress of the cluster towards the goal.
+	describe func(name string, status *client.Cluster) string
+}
+
+// readyGoal is reached once the cluster is ready.
+func readyGoal() goal {
+	return goal{
+		reached:  func(status *client.Cluster) bool { return status.Status.Ready.Status },
+		describe: func(name string, status *client.Cluster) string { return describeProgress(name, status.Status) },
+	}
+}
+
+// wait returns the status of the cluster once it is ready. It fails with the
+// reason given by the platform if provisioning fails, and when the cluster is
+// not ready within the timeout.
+func (w waiter) wait(ctx context.Context, cluster *client.Cluster, timeout time.Duration) (*client.Cluster, error) {
+	return w.waitFor(ctx, cluster, timeout, readyGoal())
+}
+
+// waitFor returns the status of the cluster once it has reached the goal, and
+// fails like wait otherwise.
+func (w waiter) waitFor(
+	ctx context.Context,
+	cluster *client.Cluster,
+	timeout time.Duration,
+	target goal,
+) (*client.Cluster, error) {
+	defer w.progress.Stop()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := w.interval
+
+	for {
+		status, err := w.platformClient.GetClusterStatus(ctx, cluster.ID)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, w.timedOut(ctx, cluster, timeout)
+		case err != nil:
+			return nil, redact.Errorf("could not get cluster status: %w", redact.Safe(err))
+		case target.reached(status):
+			return status, nil
+		case status.Status.Deployment.Failed:
+			return status, redact.Errorf("%w: %s", errProvisioningFailed, redact.Safe(failureReason(status.Status)))
+		}
+
+		w.progress.Update(target.describe(cluster.Name, status))
+
+		select {
+		case <-ctx.Done():
+			return nil, w.timedOut(ctx, cluster, timeout)
+		case <-time.After(interval):
+		}
+
+		interval = min(time.Duration(float64(interval)*pollBackoff), w.maxInterval)
+	}
+}
+
+func (w waiter) timedOut(ctx context.Context, cluster *client.Cluster, timeout time.Duration) error {
+	// the command was cancelled rather than timed out
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("stopped waiting for cluster %s: %w", cluster.Name, ctx.Err())
+	}
+
+	return redact.Errorf("%w after %s, it may still become ready (see `indev cluster status %s`)",
+		errWaitTimeout, timeout, cluster.Name)
+}
+
+// describeProgress describes the deployment state of a cluster and the
+// latest message of the platform.
+func describeProgress(name string, status client.ClusterStatus) string {
+	state := "Waiting for deployment of " + name
+	if status.Deployment.Active {
+		state = "Deploying " + name
+	}
+
+	if status.Ready.Message != "" {
+		state += ": " + status.Ready.Message
+	}
+
+	return state
+}
+
+// failureReason explains why provisioning failed, from the reason and
+// message of the platform.
+func failureReason(status client.ClusterStatus) string {
+	switch {
+	case status.Ready.Reason != "" && status.Ready.Message != "":
+		return status.Ready.Reason + " (" + status.Ready.Message + ")"
+	case status.Ready.Reason != "":
+		return status.Ready.Reason
+	case status.Ready.Message != "":
+		return status.Ready.Message
+	default:
+		return "no reason given"
+	}
+}
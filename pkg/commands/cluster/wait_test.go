@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/mocks"
+	"github.com/intility/indev/pkg/client"
+)
+
+func testWaiter(mc *mocks.Client, out *bytes.Buffer) waiter {
+	return waiter{
+		platformClient: mc,
+		progress:       ux.NewProgress(out),
+		interval:       time.Millisecond,
+		maxInterval:    time.Millisecond,
+	}
+}
+
+func clusterWithStatus(status client.ClusterStatus) *client.Cluster {
+	return &client.Cluster{ID: "cluster-id", Name: "demo", Status: status}
+}
+
+func TestWaiter(t *testing.T) {
+	pending := client.ClusterStatus{}
+	deploying := client.ClusterStatus{
+		Deployment: client.StatusDeployment{Active: true, Failed: false},
+		Ready:      client.StatusReady{Status: false, Message: "Cluster is being provisioned", Reason: "Provisioning"},
+	}
+	ready := client.ClusterStatus{Ready: client.StatusReady{Status: true, Message: "Cluster is ready", Reason: "Ready"}}
+	failed := client.ClusterStatus{
+		Deployment: client.StatusDeployment{Active: false, Failed: true},
+		Ready:      client.StatusReady{Status: false, Message: "insufficient capacity", Reason: "ProvisioningFailed"},
+	}
+
+	t.Run("waits until the cluster is ready", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(clusterWithStatus(pending), nil).Once()
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(clusterWithStatus(deploying), nil).Twice()
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(clusterWithStatus(ready), nil).Once()
+
+		var out bytes.Buffer
+
+		cluster, err := testWaiter(mc, &out).wait(context.Background(), clusterWithStatus(pending), time.Minute)
+		require.NoError(t, err)
+		assert.True(t, cluster.Status.Ready.Status)
+
+		assert.Equal(t,
+			"[0s] Waiting for deployment of demo\n[0s] Deploying demo: Cluster is being provisioned\n",
+			out.String())
+	})
+
+	t.Run("fails with the reason of a failed deployment", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(clusterWithStatus(deploying), nil).Once()
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(clusterWithStatus(failed), nil).Once()
+
+		_, err := testWaiter(mc, &bytes.Buffer{}).wait(context.Background(), clusterWithStatus(pending), time.Minute)
+		require.ErrorIs(t, err, errProvisioningFailed)
+		assert.ErrorContains(t, err, "ProvisioningFailed (insufficient capacity)")
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().GetClusterStatus(mock.Anything, "cluster-id").Return(clusterWithStatus(deploying), nil)
+
+		_, err := testWaiter(mc, &bytes.Buffer{}).wait(context.Background(), clusterWithStatus(pending), 20*time.Millisecond)
+		require.ErrorIs(t, err, errWaitTimeout)
+		assert.ErrorContains(t, err, "indev cluster status demo")
+	})
+}
+
+func TestFailureReason(t *testing.T) {
+	assert.Equal(t, "no reason given", failureReason(client.ClusterStatus{}))
+	assert.Equal(t, "Quota", failureReason(client.ClusterStatus{Ready: client.StatusReady{Reason: "Quota"}}))
+}
@@ -0,0 +1,194 @@
+package pullsecret
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/intility/indev/internal/cli"
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+const (
+	maxNameLength  = 50
+	minNameLength  = 3
+	validNameRegex = "^[a-z0-9]([a-z0-9-]*[a-z0-9])?$"
+)
+
+var (
+	errInvalidNameLength = redact.Errorf(
+		"pull secret name must be between %d and %d characters long",
+		minNameLength, maxNameLength,
+	)
+	errInvalidNameFormat = redact.Errorf("pull secret name must match the pattern %s", validNameRegex)
+	errEmptyUsername     = redact.Errorf("--username is required with --registry")
+	errEmptyPassword     = redact.Errorf("the password cannot be empty")
+	errPasswordRequired  = redact.Errorf("use --password-stdin to read the password without a terminal")
+)
+
+type CreateOptions struct {
+	File          string // Docker config.json or .dockerconfigjson, "-" for stdin
+	Registry      string
+	Username      string
+	PasswordStdin bool
+}
+
+func NewCreateCommand(set clientset.ClientSet) *cobra.Command {
+	var options CreateOptions
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a pull secret",
+		Long: `Create a pull secret with the credentials that clusters use to pull images
+from private registries.
+
+The credentials are read from a Docker config.json or .dockerconfigjson file
+with --file, or given for a single registry with --registry and --username.
+The password is asked for on the terminal, or read from stdin with
+--password-stdin. Credentials kept by a Docker credential helper cannot be
+read from config.json, use --registry for those registries instead.
+
+The credentials are sent to the platform and never shown again.`,
+		Example: `  indev pullsecret create quay --file ~/.docker/config.json
+  echo "$TOKEN" | indev pullsecret create ghcr --registry ghcr.io --username ci --password-stdin`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "pullsecret.create")
+			defer span.End()
+
+			return runCreateCommand(ctx, cmd, set, args[0], options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.File,
+		"file", "f", "", "Docker config.json or .dockerconfigjson with the credentials (- for stdin)")
+
+	cmd.Flags().StringVar(&options.Registry,
+		"registry", "", "Registry to create the pull secret for, such as quay.io")
+
+	cmd.Flags().StringVarP(&options.Username,
+		"username", "u", "", "Username of the registry")
+
+	cmd.Flags().BoolVar(&options.PasswordStdin,
+		"password-stdin", false, "Read the password of the registry from stdin")
+
+	cmd.MarkFlagsOneRequired("file", "registry")
+
+	for _, flag := range []string{"registry", "username", "password-stdin"} {
+		cmd.MarkFlagsMutuallyExclusive("file", flag)
+	}
+
+	return cmd
+}
+
+func runCreateCommand(
+	ctx context.Context,
+	cmd *cobra.Command,
+	set clientset.ClientSet,
+	name string,
+	options CreateOptions,
+) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	if options.Registry != "" && options.Username == "" {
+		return errEmptyUsername
+	}
+
+	cmd.SilenceUsage = true
+
+	config, err := readCredentials(cmd, options)
+	if err != nil {
+		return err
+	}
+
+	secret, err := set.PlatformClient.CreatePullSecret(ctx, client.NewPullSecretRequest{
+		Name:             name,
+		DockerConfigJSON: config,
+	})
+	if err != nil {
+		return redact.Errorf("could not create pull secret: %w", redact.Safe(err))
+	}
+
+	ux.Fsuccessf(cmd.OutOrStdout(), "created pull secret %s for %s\n", secret.Name, strings.Join(secret.Registries, ", "))
+
+	return nil
+}
+
+// readCredentials returns the .dockerconfigjson of the pull secret.
+func readCredentials(cmd *cobra.Command, options CreateOptions) ([]byte, error) {
+	if options.File != "" {
+		input := cmd.InOrStdin()
+
+		if options.File != "-" {
+			file, err := os.Open(options.File)
+			if err != nil {
+				return nil, redact.Errorf("could not open Docker config: %w", redact.Safe(err))
+			}
+
+			defer file.Close()
+
+			input = file
+		}
+
+		return readDockerConfig(input)
+	}
+
+	var (
+		password string
+		err      error
+	)
+
+	if options.PasswordStdin {
+		password, err = readPassword(cmd.InOrStdin())
+	} else {
+		password, err = promptPassword(cmd)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if password == "" {
+		return nil, errEmptyPassword
+	}
+
+	return dockerConfigFor(options.Registry, options.Username, password)
+}
+
+// promptPassword asks for the password on the terminal, without echoing it.
+func promptPassword(cmd *cobra.Command) (string, error) {
+	file, ok := cmd.InOrStdin().(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(int(file.Fd())) { //nolint:gosec // G115 - file descriptors fit in int
+		return "", errPasswordRequired
+	}
+
+	password, err := cli.CreatePasswordPrompter(cmd)("Password")
+	if err != nil {
+		return "", redact.Errorf("%w", redact.Safe(err))
+	}
+
+	return password, nil
+}
+
+func validateName(name string) error {
+	if len(name) < minNameLength || len(name) > maxNameLength {
+		return errInvalidNameLength
+	}
+
+	if matched, err := regexp.MatchString(validNameRegex, name); err != nil || !matched {
+		return errInvalidNameFormat
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package pullsecret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/mocks"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+func TestReadDockerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "keeps only the registry credentials of a config.json",
+			config: `{
+				"auths": {"quay.io": {"auth": "dTpw", "email": "ci@example.com"}},
+				"credsStore": "desktop",
+				"currentContext": "default"
+			}`,
+			want: `{"auths":{"quay.io":{"auth":"dTpw"}}}`,
+		},
+		{
+			name:   "reads a .dockerconfigjson",
+			config: `{"auths":{"ghcr.io":{"username":"u","password":"p","auth":"dTpw"}}}`,
+			want:   `{"auths":{"ghcr.io":{"username":"u","password":"p","auth":"dTpw"}}}`,
+		},
+		{
+			name:   "leaves out registries kept by a credential helper",
+			config: `{"auths":{"quay.io":{"auth":"dTpw"},"docker.io":{}},"credHelpers":{"docker.io":"desktop"}}`,
+			want:   `{"auths":{"quay.io":{"auth":"dTpw"}}}`,
+		},
+		{
+			name:    "rejects a config without credentials",
+			config:  `{"auths":{"docker.io":{}},"credsStore":"desktop"}`,
+			wantErr: errNoRegistries,
+		},
+		{
+			name:    "rejects a file that is not JSON",
+			config:  "registry: quay.io\npassword: hunter2\n",
+			wantErr: errInvalidDockerConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := readDockerConfig(strings.NewReader(tt.config))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.NotContains(t, err.Error(), "hunter2")
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(config))
+		})
+	}
+}
+
+func TestDockerConfigFor(t *testing.T) {
+	config, err := dockerConfigFor("ghcr.io", "ci", "hunter2")
+	require.NoError(t, err)
+
+	// the auth field is the base64 encoding of "ci:hunter2"
+	assert.JSONEq(t,
+		`{"auths":{"ghcr.io":{"username":"ci","password":"hunter2","auth":"Y2k6aHVudGVyMg=="}}}`, string(config))
+}
+
+func TestRunCreateCommand(t *testing.T) {
+	created := &client.PullSecret{ID: "secret-1", Name: "ghcr", Registries: []string{"ghcr.io"}}
+
+	t.Run("reads the password from stdin", func(t *testing.T) {
+		mc := mocks.NewClient(t)
+		mc.EXPECT().CreatePullSecret(mock.Anything, mock.MatchedBy(func(request client.NewPullSecretRequest) bool {
+			var config dockerConfig
+
+			return request.Name == "ghcr" &&
+				json.Unmarshal(request.DockerConfigJSON, &config) == nil &&
+				config.Auths["ghcr.io"].Password == "hunter2"
+		})).Return(created, nil)
+
+		var out bytes.Buffer
+
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader("hunter2\n"))
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+
+		err := runCreateCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, "ghcr",
+			CreateOptions{Registry: "ghcr.io", Username: "ci", PasswordStdin: true})
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "created pull secret ghcr for ghcr.io")
+		assert.NotContains(t, out.String(), "hunter2")
+	})
+
+	t.Run("reads a Docker config file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"auths":{"ghcr.io":{"auth":"dTpw"}}}`), 0o600))
+
+		mc := mocks.NewClient(t)
+		mc.EXPECT().CreatePullSecret(mock.Anything, client.NewPullSecretRequest{
+			Name:             "ghcr",
+			DockerConfigJSON: []byte(`{"auths":{"ghcr.io":{"auth":"dTpw"}}}`),
+		}).Return(created, nil)
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&bytes.Buffer{})
+
+		err := runCreateCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, "ghcr",
+			CreateOptions{File: path})
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name    string
+		secret  string
+		options CreateOptions
+		stdin   string
+		wantErr error
+	}{
+		{
+			name:    "rejects an invalid name",
+			secret:  "My Secret",
+			options: CreateOptions{File: "-"},
+			wantErr: errInvalidNameFormat,
+		},
+		{
+			name:    "requires a username with a registry",
+			secret:  "ghcr",
+			options: CreateOptions{Registry: "ghcr.io", PasswordStdin: true},
+			wantErr: errEmptyUsername,
+		},
+		{
+			name:    "rejects an empty password",
+			secret:  "ghcr",
+			options: CreateOptions{Registry: "ghcr.io", Username: "ci", PasswordStdin: true},
+			stdin:   "\n",
+			wantErr: errEmptyPassword,
+		},
+		{
+			name:    "asks for --password-stdin without a terminal",
+			secret:  "ghcr",
+			options: CreateOptions{Registry: "ghcr.io", Username: "ci"},
+			wantErr: errPasswordRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// no pull secret is created
+			mc := mocks.NewClient(t)
+
+			cmd := &cobra.Command{}
+			cmd.SetIn(strings.NewReader(tt.stdin))
+
+			err := runCreateCommand(context.Background(), cmd, clientset.ClientSet{PlatformClient: mc}, tt.secret,
+				tt.options)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
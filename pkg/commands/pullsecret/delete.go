@@ -0,0 +1,42 @@
+package pullsecret
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/clientset"
+)
+
+func NewDeleteCommand(set clientset.ClientSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a pull secret",
+		Long: `Delete a pull secret from the Intility Developer Platform. Pull secrets that
+a cluster uses cannot be deleted.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "pullsecret.delete")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			secret, err := set.PlatformClient.GetPullSecret(ctx, args[0])
+			if err != nil {
+				return redact.Errorf("could not find pull secret: %w", redact.Safe(err))
+			}
+
+			if err = set.PlatformClient.DeletePullSecret(ctx, secret.ID); err != nil {
+				return redact.Errorf("could not delete pull secret: %w", redact.Safe(err))
+			}
+
+			ux.Fsuccessf(cmd.OutOrStdout(), "deleted pull secret: %s\n", secret.Name)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,94 @@
+package pullsecret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/intility/indev/internal/redact"
+)
+
+// The errors below never include the contents of the Docker config, as they
+// may end up in telemetry.
+var (
+	errInvalidDockerConfig = redact.Errorf("not a Docker config.json or .dockerconfigjson file")
+	errNoRegistries        = redact.Errorf("the Docker config has no credentials for any registry")
+)
+
+// dockerConfig is the part of a Docker config.json that is sent to the
+// platform. Settings such as credsStore and credHelpers are left out.
+type dockerConfig struct {
+	Auths map[string]registryAuth `json:"auths"`
+}
+
+type registryAuth struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+func (a registryAuth) empty() bool {
+	return a.Auth == "" && a.IdentityToken == "" && (a.Username == "" || a.Password == "")
+}
+
+// readDockerConfig reads a Docker config.json or .dockerconfigjson, and
+// returns a .dockerconfigjson with only the registry credentials in it.
+func readDockerConfig(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, redact.Errorf("could not read Docker config: %w", redact.Safe(err))
+	}
+
+	var config dockerConfig
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, errInvalidDockerConfig
+	}
+
+	// registries whose credentials are kept by a credential helper have an
+	// empty entry, as do registries that were logged out of
+	for registry, auth := range config.Auths {
+		if auth.empty() {
+			delete(config.Auths, registry)
+		}
+	}
+
+	if len(config.Auths) == 0 {
+		return nil, errNoRegistries
+	}
+
+	return encodeDockerConfig(config)
+}
+
+// dockerConfigFor returns a .dockerconfigjson with the credentials of a
+// single registry, the way that kubectl create secret docker-registry
+// builds them.
+func dockerConfigFor(registry, username, password string) ([]byte, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+
+	return encodeDockerConfig(dockerConfig{Auths: map[string]registryAuth{
+		registry: {Username: username, Password: password, Auth: auth, IdentityToken: ""},
+	}})
+}
+
+func encodeDockerConfig(config dockerConfig) ([]byte, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, redact.Errorf("could not encode Docker config: %w", redact.Safe(err))
+	}
+
+	return data, nil
+}
+
+// readPassword reads a password from r, the way that docker login
+// --password-stdin does: up to the end of the input, without the trailing
+// line break.
+func readPassword(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", redact.Errorf("could not read password: %w", redact.Safe(err))
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,78 @@
+package pullsecret
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+func NewGetCommand(set clientset.ClientSet) *cobra.Command {
+	output := outputformat.Format("")
+
+	cmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Get detailed information about a pull secret",
+		Long: `Display the registries of a pull secret. The credentials of a pull secret
+are never returned by the platform.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "pullsecret.get")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			secret, err := set.PlatformClient.GetPullSecret(ctx, args[0])
+			if err != nil {
+				return redact.Errorf("could not get pull secret: %w", redact.Safe(err))
+			}
+
+			return printPullSecret(cmd.OutOrStdout(), output, secret)
+		},
+	}
+
+	cmd.Flags().VarP(&output, "output", "o", "Output format (json, yaml)")
+
+	return cmd
+}
+
+func printPullSecret(writer io.Writer, format outputformat.Format, secret *client.PullSecret) error {
+	var err error
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(secret)
+	case "yaml":
+		indent := 2
+		enc := yaml.NewEncoder(writer)
+		enc.SetIndent(indent)
+		err = enc.Encode(secret)
+	default:
+		ux.Fprintf(writer, "Pull Secret Information:\n")
+		ux.Fprintf(writer, "  Name:       %s\n", secret.Name)
+		ux.Fprintf(writer, "  ID:         %s\n", secret.ID)
+		ux.Fprintf(writer, "  Registries: %s\n", strings.Join(secret.Registries, ", "))
+		ux.Fprintf(writer, "  Created:    %s\n", secret.CreatedAt.Local().Format(time.DateTime))
+
+		return nil
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
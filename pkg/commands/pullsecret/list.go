@@ -0,0 +1,122 @@
+package pullsecret
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
+	"github.com/intility/indev/pkg/outputformat"
+	"github.com/intility/indev/pkg/pagination"
+)
+
+func NewListCommand(set clientset.ClientSet) *cobra.Command {
+	output := outputformat.Format("")
+	options := client.ListOptions{}
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List pull secrets",
+		Long:    "List the pull secrets in the Intility Developer Platform, without their credentials",
+		PreRunE: set.EnsureSignedInPreHook,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "pullsecret.list")
+			defer span.End()
+
+			if err := pagination.Validate(options); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			secrets := set.PlatformClient.IterPullSecrets(ctx, options)
+
+			if !output.IsStructured() {
+				count, err := pagination.StreamTable(cmd.OutOrStdout(), secrets, options.PageSize, pullSecretColumns(output))
+				if err != nil {
+					return redact.Errorf("could not list pull secrets: %w", redact.Safe(err))
+				}
+
+				if count == 0 {
+					ux.Fprintf(cmd.OutOrStdout(), "No pull secrets found\n")
+				}
+
+				return nil
+			}
+
+			list, err := client.Collect(secrets)
+			if err != nil {
+				return redact.Errorf("could not list pull secrets: %w", redact.Safe(err))
+			}
+
+			if len(list) == 0 {
+				ux.Fprintf(cmd.OutOrStdout(), "No pull secrets found\n")
+				return nil
+			}
+
+			if err = printPullSecretList(cmd.OutOrStdout(), output, list); err != nil {
+				return redact.Errorf("could not print pull secrets: %w", redact.Safe(err))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+	pagination.AddFlags(cmd, &options)
+
+	return cmd
+}
+
+func printPullSecretList(writer io.Writer, format outputformat.Format, secrets []client.PullSecret) error {
+	var err error
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(secrets)
+	case "yaml":
+		indent := 2
+		enc := yaml.NewEncoder(writer)
+		enc.SetIndent(indent)
+		err = enc.Encode(secrets)
+	default:
+		table := ux.TableFromObjects(secrets, pullSecretColumns(format))
+		ux.Fprintf(writer, "%s", table.String())
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+func pullSecretColumns(format outputformat.Format) ux.ColFactory[client.PullSecret] {
+	if format == "wide" {
+		return func(p client.PullSecret) []ux.Row {
+			return []ux.Row{
+				ux.NewRow("Name", p.Name),
+				ux.NewRow("Registries", strings.Join(p.Registries, ", ")),
+				ux.NewRow("Created", p.CreatedAt.Local().Format(time.DateTime)),
+				ux.NewRow("ID", p.ID),
+			}
+		}
+	}
+
+	return func(p client.PullSecret) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", p.Name),
+			ux.NewRow("Registries", strings.Join(p.Registries, ", ")),
+			ux.NewRow("Created", p.CreatedAt.Local().Format(time.DateTime)),
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pullsecret
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/outputformat"
+)
+
+func TestPrintPullSecretList(t *testing.T) {
+	secrets := []client.PullSecret{
+		{
+			ID:         "secret-1",
+			Name:       "quay",
+			Registries: []string{"quay.io"},
+			CreatedAt:  time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:         "secret-2",
+			Name:       "registries",
+			Registries: []string{"ghcr.io", "registry.example.com"},
+			CreatedAt:  time.Date(2026, time.April, 9, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	t.Run("default format shows name and registries", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := printPullSecretList(&buf, outputformat.Format(""), secrets)
+		require.NoError(t, err)
+
+		output := buf.String()
+		assert.Contains(t, output, "quay")
+		assert.Contains(t, output, "ghcr.io, registry.example.com")
+		assert.NotContains(t, output, "secret-1")
+	})
+
+	t.Run("wide format includes ID", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := printPullSecretList(&buf, outputformat.Format("wide"), secrets)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "secret-2")
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := printPullSecretList(&buf, outputformat.Format("json"), secrets)
+		require.NoError(t, err)
+
+		var decoded []client.PullSecret
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, secrets, decoded)
+	})
+}
@@ -0,0 +1,134 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/sandbox"
+)
+
+const (
+	defaultAddr        = "localhost:8080"
+	defaultBluriteAddr = "localhost:8082"
+	readHeaderTimeout  = 10 * time.Second
+	shutdownTimeout    = 5 * time.Second
+)
+
+type ServeOptions struct {
+	Addr              string
+	BluriteAddr       string
+	ProvisioningDelay time.Duration
+}
+
+func NewServeCommand() *cobra.Command {
+	var options ServeOptions
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local fake of the Intility Developer Platform",
+		Long: `Run an in-memory fake of the Intility Developer Platform and Blurite APIs.
+
+The sandbox starts with a signed-in user, a few colleagues and a team. All
+changes are kept in memory and are lost when the sandbox stops. Point indev at
+the sandbox by setting INDEV_SANDBOX to its URL; no sign-in is needed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, span := telemetry.StartSpan(cmd.Context(), "sandbox.serve")
+			defer span.End()
+
+			cmd.SilenceUsage = true
+
+			handler := sandbox.New(sandbox.WithProvisioningDelay(options.ProvisioningDelay))
+
+			addrs := []string{options.Addr}
+			if options.BluriteAddr != "" && options.BluriteAddr != options.Addr {
+				addrs = append(addrs, options.BluriteAddr)
+			}
+
+			listeners := make([]net.Listener, 0, len(addrs))
+			for _, addr := range addrs {
+				listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+				if err != nil {
+					closeAll(listeners)
+
+					return redact.Errorf("could not listen on %s: %w", addr, redact.Safe(err))
+				}
+
+				listeners = append(listeners, listener)
+			}
+
+			ux.Fsuccessf(cmd.OutOrStdout(), "sandbox listening on http://%s\n", listeners[0].Addr())
+			ux.Fprintf(cmd.OutOrStdout(), "\nIn another terminal, run:\n\n")
+			ux.Fprintf(cmd.OutOrStdout(), "  export INDEV_SANDBOX=http://%s\n\n", listeners[0].Addr())
+			ux.Fprintf(cmd.OutOrStdout(), "Press Ctrl+C to stop the sandbox.\n")
+
+			if err := serve(ctx, handler, listeners); err != nil {
+				return redact.Errorf("sandbox failed: %w", redact.Safe(err))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Addr,
+		"addr", defaultAddr, "Address to serve the platform API on")
+	cmd.Flags().StringVar(&options.BluriteAddr,
+		"blurite-addr", defaultBluriteAddr, "Address to also serve the Blurite API on (empty to disable)")
+	cmd.Flags().DurationVar(&options.ProvisioningDelay,
+		"provisioning-delay", sandbox.DefaultProvisioningDelay, "Time it takes for a new cluster to become ready")
+
+	return cmd
+}
+
+// serve serves the handler on every listener until the context is canceled
+// or one of the servers fails.
+func serve(ctx context.Context, handler http.Handler, listeners []net.Listener) error {
+	servers := make([]*http.Server, 0, len(listeners))
+	errs := make(chan error, len(listeners))
+
+	for _, listener := range listeners {
+		server := &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: readHeaderTimeout,
+		}
+		servers = append(servers, server)
+
+		go func() {
+			errs <- server.Serve(listener)
+		}()
+	}
+
+	var err error
+
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		_ = server.Shutdown(shutdownCtx)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err //nolint:wrapcheck // wrapped by the caller
+}
+
+func closeAll(listeners []net.Listener) {
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+}
@@ -15,10 +15,13 @@ import (
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/outputformat"
+	"github.com/intility/indev/pkg/pagination"
 )
 
 func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	output := outputformat.Format("")
+	options := client.ListOptions{}
+	sorted := false
 	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List all teams",
@@ -28,19 +31,40 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "teams.list")
 			defer span.End()
 
+			if err := pagination.Validate(options); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
-			teams, err := set.PlatformClient.ListTeams(ctx)
+			teams := set.PlatformClient.IterTeams(ctx, options)
+
+			// rows are printed in the order of the platform as they arrive,
+			// unless they are sorted, which needs the complete list
+			if !output.IsStructured() && !sorted {
+				count, err := pagination.StreamTable(cmd.OutOrStdout(), teams, options.PageSize, teamColumns(output))
+				if err != nil {
+					return redact.Errorf("could not list teams: %w", redact.Safe(err))
+				}
+
+				if count == 0 {
+					ux.Fprintf(cmd.OutOrStdout(), "No teams found\n")
+				}
+
+				return nil
+			}
+
+			list, err := client.Collect(teams)
 			if err != nil {
 				return redact.Errorf("could not list teams: %w", redact.Safe(err))
 			}
 
-			if len(teams) == 0 {
+			if len(list) == 0 {
 				ux.Fprintf(cmd.OutOrStdout(), "No teams found\n")
 				return nil
 			}
 
-			if err = printTeamsList(cmd.OutOrStdout(), output, teams); err != nil {
+			if err = printTeamsList(cmd.OutOrStdout(), output, list); err != nil {
 				return redact.Errorf("could not print teams list: %w", redact.Safe(err))
 			}
 
@@ -49,6 +73,9 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	}
 
 	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+	cmd.Flags().BoolVar(&sorted, "sort", false,
+		"List the teams you are a member of first, once every team has been received")
+	pagination.AddFlags(cmd, &options)
 
 	return cmd
 }
@@ -56,23 +83,9 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 func printTeamsList(writer io.Writer, format outputformat.Format, teams []client.Team) error {
 	var err error
 
-	sort.Slice(teams, func(i, j int) bool {
-		// list teams where authenticated user has membership first
-		return strings.Join(teams[i].Role, ",") > strings.Join(teams[j].Role, ",")
-	})
+	sortTeamsByMembership(teams)
 
 	switch format {
-	case "wide":
-		table := ux.TableFromObjects(teams, func(team client.Team) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Id", team.ID),
-				ux.NewRow("Name", team.Name),
-				ux.NewRow("Description", team.Description),
-				ux.NewRow("Role", strings.Join(team.Role, ",")),
-			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
 	case "json":
 		enc := json.NewEncoder(writer)
 		enc.SetIndent("", "  ")
@@ -83,20 +96,41 @@ func printTeamsList(writer io.Writer, format outputformat.Format, teams []client
 		enc.SetIndent(indent)
 		err = enc.Encode(teams)
 	default:
-		table := ux.TableFromObjects(teams, func(team client.Team) []ux.Row {
+		table := ux.TableFromObjects(teams, teamColumns(format))
+		ux.Fprintf(writer, "%s", table.String())
+	}
+
+	if err != nil {
+		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	}
+
+	return nil
+}
+
+func teamColumns(format outputformat.Format) ux.ColFactory[client.Team] {
+	if format == "wide" {
+		return func(team client.Team) []ux.Row {
 			return []ux.Row{
+				ux.NewRow("Id", team.ID),
 				ux.NewRow("Name", team.Name),
 				ux.NewRow("Description", team.Description),
 				ux.NewRow("Role", strings.Join(team.Role, ",")),
 			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
+		}
 	}
 
-	if err != nil {
-		return redact.Errorf("output encoder failed: %w", redact.Safe(err))
+	return func(team client.Team) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", team.Name),
+			ux.NewRow("Description", team.Description),
+			ux.NewRow("Role", strings.Join(team.Role, ",")),
+		}
 	}
+}
 
-	return nil
+func sortTeamsByMembership(teams []client.Team) {
+	sort.Slice(teams, func(i, j int) bool {
+		// list teams where authenticated user has membership first
+		return strings.Join(teams[i].Role, ",") > strings.Join(teams[j].Role, ",")
+	})
 }
@@ -2,6 +2,7 @@ package member
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -106,7 +107,7 @@ func runAddMemberCommand(
 		},
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "409 Conflict") {
+		if errors.Is(err, client.ErrConflict) {
 			return redact.Errorf("user %s is already a member of team %s", options.User, options.Team)
 		}
 
@@ -2,13 +2,14 @@ package member
 
 import (
 	"context"
-	"strings"
+	"errors"
 
 	"github.com/spf13/cobra"
 
 	"github.com/intility/indev/internal/redact"
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 )
 
@@ -83,7 +84,7 @@ func runRemoveMemberCommand(
 
 	err = set.PlatformClient.RemoveTeamMember(ctx, options.TeamID, memberID)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
+		if errors.Is(err, client.ErrNotFound) {
 			return redact.Errorf("user %s is not a member of team %s", options.User, options.Team)
 		}
 
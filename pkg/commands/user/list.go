@@ -16,10 +16,13 @@ import (
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/outputformat"
+	"github.com/intility/indev/pkg/pagination"
 )
 
 func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	output := outputformat.Format("")
+	options := client.ListOptions{}
+	sorted := false
 	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List all users",
@@ -29,19 +32,40 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 			ctx, span := telemetry.StartSpan(cmd.Context(), "users.list")
 			defer span.End()
 
+			if err := pagination.Validate(options); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
-			users, err := set.PlatformClient.ListUsers(ctx)
+			users := set.PlatformClient.IterUsers(ctx, options)
+
+			// rows are printed in the order of the platform as they arrive,
+			// unless they are sorted, which needs the complete list
+			if !output.IsStructured() && !sorted {
+				count, err := pagination.StreamTable(cmd.OutOrStdout(), users, options.PageSize, userColumns(output))
+				if err != nil {
+					return redact.Errorf("could not list users: %w", redact.Safe(err))
+				}
+
+				if count == 0 {
+					ux.Fprintf(cmd.OutOrStdout(), "No users found\n")
+				}
+
+				return nil
+			}
+
+			list, err := client.Collect(users)
 			if err != nil {
 				return redact.Errorf("could not list users: %w", redact.Safe(err))
 			}
 
-			if len(users) == 0 {
+			if len(list) == 0 {
 				ux.Fprintf(cmd.OutOrStdout(), "No users found\n")
 				return nil
 			}
 
-			if err = printUsersList(cmd.OutOrStdout(), output, users); err != nil {
+			if err = printUsersList(cmd.OutOrStdout(), output, list); err != nil {
 				return redact.Errorf("could not print users list: %w", redact.Safe(err))
 			}
 
@@ -50,6 +74,9 @@ func NewListCommand(set clientset.ClientSet) *cobra.Command {
 	}
 
 	cmd.Flags().VarP(&output, "output", "o", "Output format (wide, json, yaml)")
+	cmd.Flags().BoolVar(&sorted, "sort", false,
+		"List owners first, then by name, once every user has been received")
+	pagination.AddFlags(cmd, &options)
 
 	return cmd
 }
@@ -60,17 +87,6 @@ func printUsersList(writer io.Writer, format outputformat.Format, users []client
 	sortUsersByOwnerThenName(users)
 
 	switch format {
-	case "wide":
-		table := ux.TableFromObjects(users, func(user client.User) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Id", user.ID),
-				ux.NewRow("Name", user.Name),
-				ux.NewRow("UPN", user.UPN),
-				ux.NewRow("Role", strings.Join(user.Roles, ",")),
-			}
-		})
-
-		ux.Fprintf(writer, "%s", table.String())
 	case "json":
 		enc := json.NewEncoder(writer)
 		enc.SetIndent("", "  ")
@@ -81,14 +97,7 @@ func printUsersList(writer io.Writer, format outputformat.Format, users []client
 		enc.SetIndent(indent)
 		err = enc.Encode(users)
 	default:
-		table := ux.TableFromObjects(users, func(user client.User) []ux.Row {
-			return []ux.Row{
-				ux.NewRow("Name", user.Name),
-				ux.NewRow("UPN", user.UPN),
-				ux.NewRow("Role", strings.Join(user.Roles, ",")),
-			}
-		})
-
+		table := ux.TableFromObjects(users, userColumns(format))
 		ux.Fprintf(writer, "%s", table.String())
 	}
 
@@ -99,6 +108,27 @@ func printUsersList(writer io.Writer, format outputformat.Format, users []client
 	return nil
 }
 
+func userColumns(format outputformat.Format) ux.ColFactory[client.User] {
+	if format == "wide" {
+		return func(user client.User) []ux.Row {
+			return []ux.Row{
+				ux.NewRow("Id", user.ID),
+				ux.NewRow("Name", user.Name),
+				ux.NewRow("UPN", user.UPN),
+				ux.NewRow("Role", strings.Join(user.Roles, ",")),
+			}
+		}
+	}
+
+	return func(user client.User) []ux.Row {
+		return []ux.Row{
+			ux.NewRow("Name", user.Name),
+			ux.NewRow("UPN", user.UPN),
+			ux.NewRow("Role", strings.Join(user.Roles, ",")),
+		}
+	}
+}
+
 func sortUsersByOwnerThenName(users []client.User) {
 	sort.Slice(users, func(i, j int) bool {
 		hasOwnerI := slices.Contains(users[i].Roles, "owner")
@@ -2,14 +2,19 @@ package user
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/intility/indev/mocks"
 	"github.com/intility/indev/pkg/client"
+	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/outputformat"
 )
 
@@ -231,3 +236,46 @@ func TestPrintUsersList(t *testing.T) {
 		assert.Contains(t, output, "admin,developer,owner")
 	})
 }
+
+func TestListCommand(t *testing.T) {
+	users := []client.User{
+		{ID: "1", Name: "Zach", UPN: "zach@example.com", Roles: []string{"member"}},
+		{ID: "2", Name: "Alice", UPN: "alice@example.com", Roles: []string{"owner"}},
+	}
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		mc := mocks.NewClient(t)
+		mc.EXPECT().IterUsers(mock.Anything, client.ListOptions{PageSize: 1}).Return(
+			func(yield func(client.User, error) bool) {
+				for _, user := range users {
+					if !yield(user, nil) {
+						return
+					}
+				}
+			})
+
+		var out bytes.Buffer
+
+		cmd := NewListCommand(clientset.ClientSet{PlatformClient: mc})
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{"--page-size", "1"}, args...))
+		cmd.PreRunE = nil
+
+		require.NoError(t, cmd.ExecuteContext(context.Background()))
+
+		return out.String()
+	}
+
+	t.Run("lists users in the order of the platform", func(t *testing.T) {
+		output := run(t)
+		assert.Less(t, strings.Index(output, "Zach"), strings.Index(output, "Alice"))
+	})
+
+	t.Run("sorts the complete list", func(t *testing.T) {
+		// the owner on the last page is listed first
+		output := run(t, "--sort")
+		assert.Less(t, strings.Index(output, "Alice"), strings.Index(output, "Zach"))
+	})
+}
@@ -0,0 +1,148 @@
+// Package config reads and writes the settings of indev, which are kept in a
+// YAML file in the XDG config directory.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFileMode = 0o600
+	configDirMode  = 0o700
+)
+
+// Config holds the settings of indev. The zero value is the default.
+type Config struct {
+	// Account is the username of the account that commands use, when
+	// several accounts are signed in.
+	Account string `yaml:"account,omitempty"`
+	// Tenant is the tenant of the account, for users who are signed in to
+	// several tenants with the same username.
+	Tenant string `yaml:"tenant,omitempty"`
+	// AzureCLI is set to acquire tokens from the Azure CLI, for the account
+	// signed in with `az login`, instead of signing in with indev.
+	AzureCLI bool `yaml:"azureCLI,omitempty"`
+	// TokenCache selects how the token cache is stored.
+	TokenCache TokenCache `yaml:"tokenCache,omitempty"`
+	// Network holds the proxy and certificates used for all requests.
+	Network Network `yaml:"network,omitempty"`
+}
+
+// Network holds the proxy and certificate settings of all requests, for
+// networks that require a proxy or inspect TLS traffic. The flags and
+// environment variables of the same settings take precedence.
+type Network struct {
+	// Proxy is the URL of the proxy used for all requests. It takes
+	// precedence over the HTTPS_PROXY environment variable.
+	Proxy string `yaml:"proxy,omitempty"`
+	// NoProxy is a comma-separated list of hosts, domains and CIDR ranges
+	// that are reached without the proxy.
+	NoProxy string `yaml:"noProxy,omitempty"`
+	// CABundle is the path of a PEM file with certificates that are trusted
+	// in addition to the system certificates.
+	CABundle string `yaml:"caBundle,omitempty"`
+	// ClientCert is the path of a PEM client certificate presented to
+	// servers that ask for one.
+	ClientCert string `yaml:"clientCert,omitempty"`
+	// ClientKey is the path of the PEM private key of the client
+	// certificate, if it is not in ClientCert.
+	ClientKey string `yaml:"clientKey,omitempty"`
+}
+
+// Encryption of the token cache.
+const (
+	EncryptionNone       = ""
+	EncryptionPassphrase = "passphrase"
+	EncryptionKeyFile    = "keyfile"
+)
+
+// TokenCache holds the settings of the token cache. The zero value stores the
+// cache in plaintext, readable only by the user.
+type TokenCache struct {
+	// Encryption is "passphrase" to encrypt the cache with a key derived
+	// from a passphrase, "keyfile" to encrypt it with the key in KeyFile, or
+	// empty to store it in plaintext.
+	Encryption string `yaml:"encryption,omitempty"`
+	// KeyFile is the path of the file with the encryption key.
+	KeyFile string `yaml:"keyFile,omitempty"`
+	// Helper is the name of a credential helper that stores the cache
+	// instead, such as "pass" for the program indev-credential-pass.
+	Helper string `yaml:"helper,omitempty"`
+	// HelperTimeout is how long the credential helper may take to answer,
+	// such as "30s". Zero is the default of 10 seconds.
+	HelperTimeout time.Duration `yaml:"helperTimeout,omitempty"`
+}
+
+// DefaultPath returns the path of the indev config file.
+func DefaultPath() string {
+	return filepath.Join(xdg.ConfigHome, "indev", "config.yaml")
+}
+
+// Load reads the config file at path. A missing file is an empty config.
+func Load(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return config, fmt.Errorf("could not read config: %w", err)
+	}
+
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Save writes the config to the file at path, replacing it atomically.
+func (c Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("could not encode config: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), configDirMode); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("could not write config: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("could not write config: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not write config: %w", err)
+	}
+
+	if err = os.Chmod(tmp.Name(), configFileMode); err != nil {
+		return fmt.Errorf("could not write config: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not write config: %w", err)
+	}
+
+	return nil
+}
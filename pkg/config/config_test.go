@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissing(t *testing.T) {
+	config, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, Config{}, config)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "indev", "config.yaml")
+
+	require.NoError(t, Config{Account: "ada@example.com", Tenant: "tenant"}.Save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(configFileMode), info.Mode().Perm())
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, Config{Account: "ada@example.com", Tenant: "tenant"}, config)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("account: ["), 0o600))
+
+	_, err := Load(path)
+	require.ErrorContains(t, err, path)
+}
+
+func TestLoadTokenCacheHelper(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("tokenCache:\n  helper: pass\n  helperTimeout: 30s\n"), 0o600))
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, TokenCache{Helper: "pass", HelperTimeout: 30 * time.Second}, config.TokenCache)
+}
+
+func TestLoadNetwork(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`network:
+  proxy: http://proxy.example.com:3128
+  noProxy: .internal.example.com
+  caBundle: /etc/ssl/certs/corporate-root.pem
+  clientCert: /etc/indev/client.pem
+  clientKey: /etc/indev/client.key
+`), 0o600))
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, Network{
+		Proxy:      "http://proxy.example.com:3128",
+		NoProxy:    ".internal.example.com",
+		CABundle:   "/etc/ssl/certs/corporate-root.pem",
+		ClientCert: "/etc/indev/client.pem",
+		ClientKey:  "/etc/indev/client.key",
+	}, config.Network)
+}
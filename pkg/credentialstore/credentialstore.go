@@ -8,3 +8,18 @@ type CredentialStore interface {
 	// Clear removes all data from the store.
 	Clear() error
 }
+
+// Locker is implemented by stores that are shared with other processes.
+type Locker interface {
+	// Lock blocks until the store is locked for reading, or for writing
+	// when exclusive is set, and returns a function that releases the lock.
+	Lock(exclusive bool) (func() error, error)
+}
+
+// Quarantiner is implemented by stores that can set aside data that could not
+// be decoded, instead of discarding it.
+type Quarantiner interface {
+	// Quarantine moves the data of the partition out of the way and returns
+	// where it was moved to.
+	Quarantine(partitionKey string) (string, error)
+}
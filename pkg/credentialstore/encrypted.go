@@ -0,0 +1,245 @@
+package credentialstore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	keyLength  = 32
+	saltLength = 16
+
+	// argon2id parameters, as recommended by RFC 9106 for memory-constrained
+	// environments.
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+// encryptedMagic starts every encrypted cache file, followed by the format
+// version, the salt, the nonce and the ciphertext.
+var encryptedMagic = []byte("INDEVENC")
+
+const encryptedVersion byte = 1
+
+var (
+	ErrDecrypt          = errors.New("could not decrypt the token cache, the passphrase or key is wrong or the cache was modified")
+	ErrNotEncrypted     = errors.New("the token cache is not encrypted")
+	ErrEmptyPassphrase  = errors.New("the passphrase must not be empty")
+	ErrInvalidKeyLength = fmt.Errorf("the key must be %d bytes, or their base64 encoding", keyLength)
+)
+
+// KeyFunc returns the encryption key for the salt that is stored with the
+// data.
+type KeyFunc func(salt []byte) ([]byte, error)
+
+// PassphraseKey derives keys from a passphrase with argon2id. The passphrase
+// is asked for the first time a key is derived.
+func PassphraseKey(passphrase func() ([]byte, error)) KeyFunc {
+	return func(salt []byte) ([]byte, error) {
+		secret, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(secret) == 0 {
+			return nil, ErrEmptyPassphrase
+		}
+
+		return argon2.IDKey(secret, salt, argonTime, argonMemory, argonThreads, keyLength), nil
+	}
+}
+
+// KeyFileKey reads the key from a file that holds 32 random bytes, either
+// raw or base64-encoded, such as the output of `openssl rand -base64 32`.
+// The salt is not used, as the key is random already.
+func KeyFileKey(path string) KeyFunc {
+	return func([]byte) ([]byte, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read key file: %w", err)
+		}
+
+		if len(data) == keyLength {
+			return data, nil
+		}
+
+		key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+		if err != nil || len(key) != keyLength {
+			return nil, fmt.Errorf("invalid key file %s: %w", path, ErrInvalidKeyLength)
+		}
+
+		return key, nil
+	}
+}
+
+// EncryptedCredentialStore encrypts the data of another store with
+// AES-256-GCM. The key is derived once per command, and every write uses a
+// new nonce. Locking, quarantining and clearing are passed on to the
+// underlying store.
+type EncryptedCredentialStore struct {
+	store CredentialStore
+	key   KeyFunc
+
+	mu   sync.Mutex
+	salt []byte
+	aead cipher.AEAD
+}
+
+func NewEncryptedCredentialStore(store CredentialStore, key KeyFunc) *EncryptedCredentialStore {
+	return &EncryptedCredentialStore{
+		store: store,
+		key:   key,
+		mu:    sync.Mutex{},
+		salt:  nil,
+		aead:  nil,
+	}
+}
+
+func (store *EncryptedCredentialStore) Get(partitionKey string) ([]byte, error) {
+	data, err := store.store.Get(partitionKey)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // errors of the underlying store are passed on
+	}
+
+	// a cache that was never written is empty
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	return store.decrypt(data)
+}
+
+func (store *EncryptedCredentialStore) Set(data []byte, partitionKey string) error {
+	sealed, err := store.encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	return store.store.Set(sealed, partitionKey) //nolint:wrapcheck // errors of the underlying store are passed on
+}
+
+func (store *EncryptedCredentialStore) Clear() error {
+	return store.store.Clear() //nolint:wrapcheck // errors of the underlying store are passed on
+}
+
+func (store *EncryptedCredentialStore) Lock(exclusive bool) (func() error, error) {
+	locker, ok := store.store.(Locker)
+	if !ok {
+		return func() error { return nil }, nil
+	}
+
+	return locker.Lock(exclusive) //nolint:wrapcheck // errors of the underlying store are passed on
+}
+
+func (store *EncryptedCredentialStore) Quarantine(partitionKey string) (string, error) {
+	quarantiner, ok := store.store.(Quarantiner)
+	if !ok {
+		return "", fmt.Errorf("could not move cache: %w", errors.ErrUnsupported)
+	}
+
+	return quarantiner.Quarantine(partitionKey) //nolint:wrapcheck // errors of the underlying store are passed on
+}
+
+func (store *EncryptedCredentialStore) encrypt(data []byte) ([]byte, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// the salt of the data that was read last is reused, so that the key is
+	// not derived again
+	if store.aead == nil {
+		salt := make([]byte, saltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("could not generate salt: %w", err)
+		}
+
+		if err := store.useSalt(salt); err != nil {
+			return nil, err
+		}
+	}
+
+	nonce := make([]byte, store.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	header := store.header()
+	sealed := append(append(header, nonce...), store.aead.Seal(nil, nonce, data, header)...)
+
+	return sealed, nil
+}
+
+func (store *EncryptedCredentialStore) decrypt(data []byte) ([]byte, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	headerLength := len(encryptedMagic) + 1 + saltLength
+	if len(data) < headerLength || !bytes.HasPrefix(data, encryptedMagic) {
+		return nil, ErrNotEncrypted
+	}
+
+	if version := data[len(encryptedMagic)]; version != encryptedVersion {
+		return nil, fmt.Errorf("unsupported token cache format version %d", version) //nolint:err113 // only seen after a downgrade
+	}
+
+	salt := data[len(encryptedMagic)+1 : headerLength]
+	if store.aead == nil || !bytes.Equal(salt, store.salt) {
+		if err := store.useSalt(bytes.Clone(salt)); err != nil {
+			return nil, err
+		}
+	}
+
+	rest := data[headerLength:]
+	if len(rest) < store.aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	nonce, ciphertext := rest[:store.aead.NonceSize()], rest[store.aead.NonceSize():]
+
+	plaintext, err := store.aead.Open(nil, nonce, ciphertext, data[:headerLength])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return plaintext, nil
+}
+
+// useSalt derives the key for the salt and sets up the cipher with it.
+func (store *EncryptedCredentialStore) useSalt(salt []byte) error {
+	key, err := store.key(salt)
+	if err != nil {
+		return fmt.Errorf("could not get encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("could not create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("could not create cipher: %w", err)
+	}
+
+	store.salt = salt
+	store.aead = aead
+
+	return nil
+}
+
+func (store *EncryptedCredentialStore) header() []byte {
+	header := make([]byte, 0, len(encryptedMagic)+1+saltLength)
+	header = append(header, encryptedMagic...)
+	header = append(header, encryptedVersion)
+
+	return append(header, store.salt...)
+}
@@ -0,0 +1,85 @@
+package credentialstore_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/credentialstore"
+)
+
+func passphrase(secret string) credentialstore.KeyFunc {
+	return credentialstore.PassphraseKey(func() ([]byte, error) {
+		return []byte(secret), nil
+	})
+}
+
+func TestEncryptedCredentialStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "msal.cache.enc")
+	files := credentialstore.NewFilesystemCredentialStore(path)
+
+	store := credentialstore.NewEncryptedCredentialStore(files, passphrase("correct horse"))
+	require.NoError(t, store.Set([]byte(`{"RefreshToken":{}}`), ""))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "RefreshToken")
+
+	// a new store derives the key again from the salt in the file
+	data, err := credentialstore.NewEncryptedCredentialStore(files, passphrase("correct horse")).Get("")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"RefreshToken":{}}`, string(data))
+
+	_, err = credentialstore.NewEncryptedCredentialStore(files, passphrase("wrong")).Get("")
+	require.ErrorIs(t, err, credentialstore.ErrDecrypt)
+}
+
+func TestEncryptedCredentialStore_Empty(t *testing.T) {
+	store := credentialstore.NewEncryptedCredentialStore(
+		credentialstore.NewFilesystemCredentialStore(filepath.Join(t.TempDir(), "msal.cache.enc")),
+		func([]byte) ([]byte, error) {
+			t.Fatal("the key is not needed to read an empty cache")
+
+			return nil, nil
+		},
+	)
+
+	data, err := store.Get("")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestEncryptedCredentialStore_NotEncrypted(t *testing.T) {
+	files := credentialstore.NewFilesystemCredentialStore(filepath.Join(t.TempDir(), "msal.cache.enc"))
+	require.NoError(t, files.Set([]byte(`{}`), ""))
+
+	_, err := credentialstore.NewEncryptedCredentialStore(files, passphrase("secret")).Get("")
+	require.ErrorIs(t, err, credentialstore.ErrNotEncrypted)
+}
+
+func TestKeyFileKey(t *testing.T) {
+	dir := t.TempDir()
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	raw := filepath.Join(dir, "raw.key")
+	require.NoError(t, os.WriteFile(raw, key, 0o600))
+
+	encoded := filepath.Join(dir, "encoded.key")
+	require.NoError(t, os.WriteFile(encoded, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600))
+
+	short := filepath.Join(dir, "short.key")
+	require.NoError(t, os.WriteFile(short, []byte("c2hvcnQ="), 0o600))
+
+	for _, path := range []string{raw, encoded} {
+		got, err := credentialstore.KeyFileKey(path)(nil)
+		require.NoError(t, err)
+		assert.Equal(t, key, got)
+	}
+
+	_, err := credentialstore.KeyFileKey(short)(nil)
+	require.ErrorIs(t, err, credentialstore.ErrInvalidKeyLength)
+}
@@ -1,12 +1,16 @@
 package credentialstore
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -14,8 +18,17 @@ import (
 const (
 	cacheFileMode = 0o600
 	cacheDirMode  = 0o700
+
+	// partitionHashLength is the number of hex characters of the partition
+	// key hash that are used in file names.
+	partitionHashLength = 16
 )
 
+// FilesystemCredentialStore keeps the data of the empty partition key in the
+// file it is created with, and the data of other partitions in files next to
+// it, named after a hash of the partition key. Writes replace the files
+// atomically, and Lock takes an advisory lock that is shared with other
+// processes using the same file.
 type FilesystemCredentialStore struct {
 	filePath string
 	fs       afero.Fs
@@ -43,14 +56,13 @@ func WithFilesystem(fs afero.Fs) FileSystemOption {
 	}
 }
 
-//goland:noinspection GoUnusedParameter
 func (store *FilesystemCredentialStore) Get(partitionKey string) ([]byte, error) {
 	err := store.createCacheDirIfNotExists()
 	if err != nil {
 		return nil, err
 	}
 
-	file, err := store.fs.OpenFile(store.filePath, os.O_RDONLY, cacheFileMode)
+	file, err := store.fs.OpenFile(store.partitionPath(partitionKey), os.O_RDONLY, cacheFileMode)
 	if err != nil {
 		// the cache file may not exist yet, so we return an empty byte slice
 		// instead to indicate that there is no data in the cache
@@ -61,6 +73,8 @@ func (store *FilesystemCredentialStore) Get(partitionKey string) ([]byte, error)
 		return nil, fmt.Errorf("could not read cache file: %w", err)
 	}
 
+	defer file.Close()
+
 	cacheData, err := io.ReadAll(file)
 	if err != nil {
 		return nil, fmt.Errorf("could not read cache file: %w", err)
@@ -69,26 +83,107 @@ func (store *FilesystemCredentialStore) Get(partitionKey string) ([]byte, error)
 	return cacheData, nil
 }
 
-//goland:noinspection GoUnusedParameter
+// Set writes the data to a temporary file that is synced to disk and then
+// renamed over the cache file, so that readers and crashes never leave a
+// partially written cache behind.
 func (store *FilesystemCredentialStore) Set(data []byte, partitionKey string) error {
 	err := store.createCacheDirIfNotExists()
 	if err != nil {
 		return err
 	}
 
-	file, err := store.fs.OpenFile(store.filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cacheFileMode)
+	path := store.partitionPath(partitionKey)
+
+	tmp, err := afero.TempFile(store.fs, filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
-		return fmt.Errorf("could not open cache file: %w", err)
+		return fmt.Errorf("could not create cache file: %w", err)
 	}
 
-	_, err = file.Write(data)
-	if err != nil {
+	defer func() {
+		_ = store.fs.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
 		return fmt.Errorf("could not write to cache file: %w", err)
 	}
 
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("could not write to cache file: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not write to cache file: %w", err)
+	}
+
+	if err = store.fs.Chmod(tmp.Name(), cacheFileMode); err != nil {
+		return fmt.Errorf("could not write to cache file: %w", err)
+	}
+
+	if err = store.fs.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not replace cache file: %w", err)
+	}
+
+	store.syncDir(filepath.Dir(path))
+
 	return nil
 }
 
+// Lock takes an advisory lock on a lock file next to the cache file, which
+// blocks until no other process holds a conflicting lock. Stores on file
+// systems without file descriptors, such as in-memory ones, are not locked.
+func (store *FilesystemCredentialStore) Lock(exclusive bool) (func() error, error) {
+	err := store.createCacheDirIfNotExists()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := store.fs.OpenFile(store.filePath+".lock", os.O_RDWR|os.O_CREATE, cacheFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("could not open lock file: %w", err)
+	}
+
+	descriptor, ok := file.(interface{ Fd() uintptr })
+	if !ok {
+		return file.Close, nil
+	}
+
+	if err = lockFile(descriptor.Fd(), exclusive); err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("could not lock cache file: %w", err)
+	}
+
+	return func() error {
+		// closing the file releases the lock as well, but unlocking first
+		// makes the release explicit
+		unlockErr := unlockFile(descriptor.Fd())
+		closeErr := file.Close()
+
+		if err := errors.Join(unlockErr, closeErr); err != nil {
+			return fmt.Errorf("could not unlock cache file: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
+// Quarantine renames the cache file of the partition, so that it is kept for
+// inspection while the partition starts out empty. It returns the new path.
+func (store *FilesystemCredentialStore) Quarantine(partitionKey string) (string, error) {
+	path := store.partitionPath(partitionKey)
+	quarantined := path + ".corrupt-" + time.Now().UTC().Format("20060102T150405Z")
+
+	if err := store.fs.Rename(path, quarantined); err != nil {
+		return "", fmt.Errorf("could not move cache file: %w", err)
+	}
+
+	return quarantined, nil
+}
+
 func (store *FilesystemCredentialStore) createCacheDirIfNotExists() error {
 	dir := filepath.Dir(store.filePath)
 
@@ -105,15 +200,105 @@ func (store *FilesystemCredentialStore) createCacheDirIfNotExists() error {
 	return nil
 }
 
+// Clear removes the cache files of all partitions, including the ones that
+// were quarantined, as they may still hold credentials. The files are
+// overwritten before they are removed.
 func (store *FilesystemCredentialStore) Clear() error {
-	err := store.fs.Remove(store.filePath)
+	paths, err := store.files()
 	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
+		return err
+	}
+
+	for _, path := range paths {
+		if err = store.wipe(path); err != nil {
+			return err
 		}
 
-		return fmt.Errorf("could not remove cache file: %w", err)
+		err = store.fs.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("could not remove cache file: %w", err)
+		}
 	}
 
 	return nil
 }
+
+// wipe overwrites the file with zeros and syncs it to disk, so that the
+// credentials are not left in the freed blocks. Copy-on-write file systems
+// and SSDs may still keep old copies, which only disk encryption prevents.
+func (store *FilesystemCredentialStore) wipe(path string) error {
+	file, err := store.fs.OpenFile(path, os.O_WRONLY, cacheFileMode)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not wipe cache file: %w", err)
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("could not wipe cache file: %w", err)
+	}
+
+	if _, err = file.Write(make([]byte, info.Size())); err != nil {
+		return fmt.Errorf("could not wipe cache file: %w", err)
+	}
+
+	if err = file.Sync(); err != nil {
+		return fmt.Errorf("could not wipe cache file: %w", err)
+	}
+
+	return nil
+}
+
+// partitionPath returns the path of the file that holds the data of the
+// partition. The empty partition key uses the file the store was created
+// with, so that caches written before partitions were stored separately are
+// still read.
+func (store *FilesystemCredentialStore) partitionPath(partitionKey string) string {
+	if partitionKey == "" {
+		return store.filePath
+	}
+
+	sum := sha256.Sum256([]byte(partitionKey))
+	ext := filepath.Ext(store.filePath)
+
+	return strings.TrimSuffix(store.filePath, ext) + "-" + hex.EncodeToString(sum[:])[:partitionHashLength] + ext
+}
+
+// files returns the cache files of all partitions and the ones that were
+// quarantined.
+func (store *FilesystemCredentialStore) files() ([]string, error) {
+	patterns := []string{
+		strings.TrimSuffix(store.filePath, filepath.Ext(store.filePath)) + "-*",
+		store.filePath + ".corrupt-*",
+	}
+
+	paths := []string{store.filePath}
+
+	for _, pattern := range patterns {
+		matches, err := afero.Glob(store.fs, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("could not list cache files: %w", err)
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	return paths, nil
+}
+
+// syncDir flushes the rename of a cache file to disk. Not every platform can
+// sync directories, so failures are ignored.
+func (store *FilesystemCredentialStore) syncDir(dir string) {
+	file, err := store.fs.Open(dir)
+	if err != nil {
+		return
+	}
+
+	_ = file.Sync()
+	_ = file.Close()
+}
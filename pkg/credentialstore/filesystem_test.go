@@ -1,9 +1,86 @@
 package credentialstore_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/credentialstore"
 )
 
 func TestFilesystemCredentialStore_Get(t *testing.T) {
+	store := credentialstore.NewFilesystemCredentialStore(filepath.Join(t.TempDir(), "indev", "msal.cache"))
+
+	data, err := store.Get("")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestFilesystemCredentialStore_Partitions(t *testing.T) {
+	dir := t.TempDir()
+	store := credentialstore.NewFilesystemCredentialStore(filepath.Join(dir, "msal.cache"))
+
+	require.NoError(t, store.Set([]byte("default"), ""))
+	require.NoError(t, store.Set([]byte("first"), "first"))
+	require.NoError(t, store.Set([]byte("second"), "second"))
+
+	for key, want := range map[string]string{"": "default", "first": "first", "second": "second"} {
+		data, err := store.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "msal.cache"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// no temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	require.NoError(t, store.Clear())
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFilesystemCredentialStore_Quarantine(t *testing.T) {
+	dir := t.TempDir()
+	store := credentialstore.NewFilesystemCredentialStore(filepath.Join(dir, "msal.cache"))
+
+	require.NoError(t, store.Set([]byte("{"), ""))
+
+	path, err := store.Quarantine("")
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	data, err := store.Get("")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	require.NoError(t, store.Clear())
+	assert.NoFileExists(t, path)
+}
+
+func TestFilesystemCredentialStore_Lock(t *testing.T) {
+	store := credentialstore.NewFilesystemCredentialStore(filepath.Join(t.TempDir(), "msal.cache"))
+
+	unlock, err := store.Lock(true)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+
+	// shared locks do not block each other
+	first, err := store.Lock(false)
+	require.NoError(t, err)
+
+	second, err := store.Lock(false)
+	require.NoError(t, err)
 
+	require.NoError(t, second())
+	require.NoError(t, first())
 }
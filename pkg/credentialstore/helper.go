@@ -0,0 +1,208 @@
+package credentialstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HelperPrefix is the prefix of the names of credential helper programs.
+const HelperPrefix = "indev-credential-"
+
+// DefaultHelperTimeout is how long a credential helper may take to answer.
+const DefaultHelperTimeout = 10 * time.Second
+
+// helperNotFound is the message with which helpers report that they hold no
+// data for a partition, as Docker credential helpers do.
+const helperNotFound = "credentials not found"
+
+// Verbs of the credential helper protocol.
+const (
+	verbGet   = "get"
+	verbStore = "store"
+	verbErase = "erase"
+)
+
+var (
+	ErrHelperNotFound = errors.New("credential helper not found")
+	ErrHelperTimeout  = errors.New("credential helper timed out")
+	ErrHelperFailed   = errors.New("credential helper failed")
+
+	// errNotStored is returned by run when the helper holds no data for the
+	// partition.
+	errNotStored = errors.New(helperNotFound)
+)
+
+// HelperRequest is written as JSON to the standard input of a helper. Get
+// and erase requests carry only the partition key.
+type HelperRequest struct {
+	PartitionKey string `json:"PartitionKey"`
+	// Secret is the data to store, encoded as base64.
+	Secret []byte `json:"Secret,omitempty"`
+}
+
+// HelperResponse is read as JSON from the standard output of a helper that
+// answers a get request.
+type HelperResponse struct {
+	PartitionKey string `json:"PartitionKey"`
+	Secret       []byte `json:"Secret"`
+}
+
+// HelperCredentialStore keeps the data in an external program, such as one
+// that stores it in pass, 1Password or Vault. The program is called
+// indev-credential-<name> and is found in PATH. Like Docker credential
+// helpers, it is run with the verb get, store or erase as its argument and a
+// HelperRequest on standard input. Get prints a HelperResponse, and a
+// helper without data for the partition prints "credentials not found" and
+// exits with a non-zero status. Any other non-zero exit is an error, whose
+// message is the output of the helper.
+type HelperCredentialStore struct {
+	name    string
+	timeout time.Duration
+
+	mu sync.Mutex
+	// partitions are the partition keys that were used, which Clear erases,
+	// as helpers cannot list what they store
+	partitions map[string]struct{}
+}
+
+type HelperOption func(*HelperCredentialStore)
+
+func NewHelperCredentialStore(name string, options ...HelperOption) *HelperCredentialStore {
+	store := &HelperCredentialStore{
+		name:       name,
+		timeout:    DefaultHelperTimeout,
+		mu:         sync.Mutex{},
+		partitions: map[string]struct{}{"": {}},
+	}
+
+	for _, option := range options {
+		option(store)
+	}
+
+	return store
+}
+
+// WithHelperTimeout sets how long the helper may take to answer a request.
+func WithHelperTimeout(timeout time.Duration) HelperOption {
+	return func(store *HelperCredentialStore) {
+		if timeout > 0 {
+			store.timeout = timeout
+		}
+	}
+}
+
+func (store *HelperCredentialStore) Get(partitionKey string) ([]byte, error) {
+	store.use(partitionKey)
+
+	output, err := store.run(verbGet, HelperRequest{PartitionKey: partitionKey, Secret: nil})
+	if errors.Is(err, errNotStored) {
+		return []byte{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var response HelperResponse
+	if err = json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("%w: invalid response of %s: %w", ErrHelperFailed, store.program(), err)
+	}
+
+	return response.Secret, nil
+}
+
+func (store *HelperCredentialStore) Set(data []byte, partitionKey string) error {
+	store.use(partitionKey)
+
+	_, err := store.run(verbStore, HelperRequest{PartitionKey: partitionKey, Secret: data})
+
+	return err
+}
+
+// Clear erases the empty partition and the partitions that were read or
+// written by this store.
+func (store *HelperCredentialStore) Clear() error {
+	store.mu.Lock()
+	partitions := make([]string, 0, len(store.partitions))
+
+	for partition := range store.partitions {
+		partitions = append(partitions, partition)
+	}
+	store.mu.Unlock()
+
+	var errs []error
+
+	for _, partition := range partitions {
+		_, err := store.run(verbErase, HelperRequest{PartitionKey: partition, Secret: nil})
+		if err != nil && !errors.Is(err, errNotStored) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (store *HelperCredentialStore) use(partitionKey string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.partitions[partitionKey] = struct{}{}
+}
+
+func (store *HelperCredentialStore) program() string {
+	return HelperPrefix + store.name
+}
+
+func (store *HelperCredentialStore) run(verb string, request HelperRequest) ([]byte, error) {
+	path, err := exec.LookPath(store.program())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not in PATH", ErrHelperNotFound, store.program())
+	}
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode helper request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path, verb)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// helpers that leave children with the output pipes open would otherwise
+	// block after being killed
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w: %s %s did not answer within %s", ErrHelperTimeout, store.program(), verb, store.timeout)
+	}
+
+	if err != nil {
+		message := strings.TrimSpace(stdout.String() + "\n" + stderr.String())
+
+		if strings.Contains(strings.ToLower(message), helperNotFound) {
+			return nil, errNotStored
+		}
+
+		if message == "" {
+			message = err.Error()
+		}
+
+		return nil, fmt.Errorf("%w: %s %s: %s", ErrHelperFailed, store.program(), verb, message)
+	}
+
+	return stdout.Bytes(), nil
+}
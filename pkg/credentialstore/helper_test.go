@@ -0,0 +1,139 @@
+package credentialstore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/credentialstore"
+)
+
+// stubHelperDir is set when the test binary runs as the stub credential
+// helper, and names the directory where it keeps partitions.
+const stubHelperDir = "INDEV_TEST_STUB_HELPER_DIR"
+
+func TestMain(m *testing.M) {
+	if dir := os.Getenv(stubHelperDir); dir != "" {
+		os.Exit(runStubHelper(dir))
+	}
+
+	os.Exit(m.Run())
+}
+
+// runStubHelper answers a credential helper request by keeping each
+// partition in a file. The partitions "fail" and "slow" misbehave.
+func runStubHelper(dir string) int {
+	var request credentialstore.HelperRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Println("invalid request:", err)
+
+		return 1
+	}
+
+	switch request.PartitionKey {
+	case "fail":
+		fmt.Println("vault is sealed")
+
+		return 1
+	case "slow":
+		time.Sleep(time.Minute)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("partition-%x", request.PartitionKey))
+
+	switch os.Args[1] {
+	case "get":
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Println("credentials not found in stub")
+
+			return 1
+		}
+
+		_ = json.NewEncoder(os.Stdout).Encode(credentialstore.HelperResponse{
+			PartitionKey: request.PartitionKey,
+			Secret:       data,
+		})
+	case "store":
+		_ = os.WriteFile(path, request.Secret, 0o600)
+	case "erase":
+		if err := os.Remove(path); err != nil {
+			fmt.Println("credentials not found in stub")
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// installStubHelper puts the test binary in PATH as indev-credential-stub,
+// and returns the directory where it keeps partitions.
+func installStubHelper(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub helper is a symlink")
+	}
+
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	bin := t.TempDir()
+	require.NoError(t, os.Symlink(executable, filepath.Join(bin, credentialstore.HelperPrefix+"stub")))
+
+	data := t.TempDir()
+
+	t.Setenv("PATH", bin)
+	t.Setenv(stubHelperDir, data)
+
+	return data
+}
+
+func TestHelperCredentialStore(t *testing.T) {
+	dir := installStubHelper(t)
+	store := credentialstore.NewHelperCredentialStore("stub")
+
+	data, err := store.Get("account")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	require.NoError(t, store.Set([]byte(`{"RefreshToken":{}}`), "account"))
+	require.NoError(t, store.Set([]byte(`{}`), ""))
+
+	data, err = store.Get("account")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"RefreshToken":{}}`, string(data))
+
+	require.NoError(t, store.Clear())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestHelperCredentialStore_Errors(t *testing.T) {
+	installStubHelper(t)
+
+	_, err := credentialstore.NewHelperCredentialStore("stub").Get("fail")
+	require.ErrorIs(t, err, credentialstore.ErrHelperFailed)
+	assert.ErrorContains(t, err, "vault is sealed")
+
+	store := credentialstore.NewHelperCredentialStore("stub",
+		credentialstore.WithHelperTimeout(100*time.Millisecond))
+
+	_, err = store.Get("slow")
+	require.ErrorIs(t, err, credentialstore.ErrHelperTimeout)
+
+	_, err = credentialstore.NewHelperCredentialStore("missing").Get("")
+	require.ErrorIs(t, err, credentialstore.ErrHelperNotFound)
+}
@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package credentialstore
+
+// platforms without file locks, such as js and wasip1, run a single process
+// at a time
+
+func lockFile(uintptr, bool) error {
+	return nil
+}
+
+func unlockFile(uintptr) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package credentialstore
+
+import "syscall"
+
+func lockFile(fd uintptr, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+
+	for {
+		err := syscall.Flock(int(fd), how)
+		if err != syscall.EINTR { //nolint:errorlint // syscall errors are not wrapped
+			return err //nolint:wrapcheck // wrapped by the caller
+		}
+	}
+}
+
+func unlockFile(fd uintptr) error {
+	return syscall.Flock(int(fd), syscall.LOCK_UN) //nolint:wrapcheck // wrapped by the caller
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package credentialstore
+
+import "golang.org/x/sys/windows"
+
+// lockedBytes is the length of the byte range that is locked. Any range
+// works, as long as every process locks the same one.
+const lockedBytes = 1
+
+func lockFile(fd uintptr, exclusive bool) error {
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+
+	overlapped := new(windows.Overlapped)
+
+	return windows.LockFileEx(windows.Handle(fd), flags, 0, lockedBytes, 0, overlapped) //nolint:wrapcheck // wrapped by the caller
+}
+
+func unlockFile(fd uintptr) error {
+	overlapped := new(windows.Overlapped)
+
+	return windows.UnlockFileEx(windows.Handle(fd), 0, lockedBytes, 0, overlapped) //nolint:wrapcheck // wrapped by the caller
+}
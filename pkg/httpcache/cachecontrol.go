@@ -0,0 +1,47 @@
+package httpcache
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds the directives of a Cache-Control header, keyed by their
+// lower case name.
+type cacheControl map[string]string
+
+func parseCacheControl(value string) cacheControl {
+	directives := make(cacheControl)
+
+	for part := range strings.SplitSeq(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+
+		directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+	}
+
+	return directives
+}
+
+func (c cacheControl) has(name string) bool {
+	_, ok := c[name]
+
+	return ok
+}
+
+// seconds returns the duration argument of a directive such as max-age.
+func (c cacheControl) seconds(name string) (time.Duration, bool) {
+	argument, ok := c[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(argument)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,364 @@
+// Package httpcache implements an on-disk cache of GET responses, as an
+// http.RoundTripper. Cached responses are revalidated with their ETag, unless
+// Cache-Control says that they are still fresh, and can be served without
+// network access in offline mode. Successful changes, such as POST or DELETE
+// requests, drop the responses cached from the same origin.
+//
+// Responses are stored per partition, usually the signed-in account, which
+// is attached to the request context with [WithPartition]. Requests without a
+// partition are not cached.
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// maxEntrySize is the size of the largest response body that is cached.
+const maxEntrySize = 8 << 20
+
+const (
+	headerCacheControl = "Cache-Control"
+	headerETag         = "ETag"
+	headerIfNoneMatch  = "If-None-Match"
+	headerAge          = "Age"
+)
+
+var (
+	ErrNotCached = errors.New("no cached response is available offline")
+	ErrOffline   = errors.New("changes cannot be made offline")
+)
+
+type Mode int
+
+const (
+	// ModeDefault serves fresh responses from the cache, and revalidates
+	// the others.
+	ModeDefault Mode = iota
+	// ModeBypass neither reads from nor writes to the cache.
+	ModeBypass
+	// ModeOffline serves every GET request from the cache, however old the
+	// cached response is, and fails all other requests.
+	ModeOffline
+)
+
+type partitionKey struct{}
+
+// WithPartition returns a context that makes requests cache their responses
+// in the given partition, such as the ID of the signed-in account.
+func WithPartition(ctx context.Context, partition string) context.Context {
+	return context.WithValue(ctx, partitionKey{}, partition)
+}
+
+func partitionFrom(ctx context.Context) string {
+	partition, _ := ctx.Value(partitionKey{}).(string)
+
+	return partition
+}
+
+// DefaultDir returns the directory used by the indev cache.
+func DefaultDir() string {
+	return filepath.Join(xdg.CacheHome, "indev", "http")
+}
+
+// RemovePartition removes the responses cached in dir for the partition, such
+// as those of an account that signs out.
+func RemovePartition(dir, partition string) error {
+	if err := os.RemoveAll(store{dir: dir}.partitionDir(partition)); err != nil {
+		return fmt.Errorf("could not remove cached responses: %w", err)
+	}
+
+	return nil
+}
+
+// Clear removes every response cached in dir.
+func Clear(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("could not remove cached responses: %w", err)
+	}
+
+	return nil
+}
+
+// Transport is an http.RoundTripper that caches GET responses. It is safe for
+// concurrent use.
+type Transport struct {
+	next  http.RoundTripper
+	store store
+	now   func() time.Time
+	mode  Mode
+
+	mu sync.Mutex
+	// staleSince is when the oldest response served in offline mode was
+	// stored
+	staleSince time.Time
+}
+
+type Option func(*Transport)
+
+// WithClock sets the clock used to compute the age of cached responses.
+func WithClock(now func() time.Time) Option {
+	return func(t *Transport) {
+		t.now = now
+	}
+}
+
+// WithMode sets how the cache is used.
+func WithMode(mode Mode) Option {
+	return func(t *Transport) {
+		t.mode = mode
+	}
+}
+
+// New creates a transport that caches responses in dir, and sends requests
+// with next.
+func New(dir string, next http.RoundTripper, options ...Option) *Transport {
+	transport := &Transport{
+		next:       next,
+		store:      store{dir: dir},
+		now:        time.Now,
+		mode:       ModeDefault,
+		mu:         sync.Mutex{},
+		staleSince: time.Time{},
+	}
+
+	transport.Configure(options...)
+
+	return transport
+}
+
+// Configure applies options to an existing transport. It must be called
+// before the transport is used.
+func (t *Transport) Configure(options ...Option) {
+	for _, opt := range options {
+		opt(t)
+	}
+}
+
+// Stale reports whether responses have been served from the cache in offline
+// mode, and when the oldest of them was stored.
+func (t *Transport) Stale() (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.staleSince, !t.staleSince.IsZero()
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeOffline && req.Method != http.MethodGet {
+		return nil, ErrOffline
+	}
+
+	partition := partitionFrom(req.Context())
+	if t.mode == ModeBypass || partition == "" {
+		return t.next.RoundTrip(req) //nolint:wrapcheck // transparent wrapper
+	}
+
+	if req.Method != http.MethodGet {
+		return t.invalidate(partition, req)
+	}
+
+	key := entryKey(req)
+	cached, _ := t.store.load(partition, req.URL, key)
+
+	if t.mode == ModeOffline {
+		return t.serveOffline(req, cached)
+	}
+
+	if cached != nil && cached.isFresh(t.now()) {
+		return cached.response(req, t.now()), nil
+	}
+
+	outgoing := req
+	if cached != nil && cached.ETag() != "" {
+		outgoing = req.Clone(req.Context())
+		outgoing.Header.Set(headerIfNoneMatch, cached.ETag())
+	}
+
+	resp, err := t.next.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		discard(resp)
+
+		cached.revalidated(resp.Header, t.now())
+		_ = t.store.save(partition, req.URL, key, cached)
+
+		return cached.response(req, t.now()), nil
+	case resp.StatusCode == http.StatusOK:
+		return t.storeResponse(partition, req, resp)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		t.store.remove(partition, req.URL, key)
+	}
+
+	return resp, nil
+}
+
+// invalidate sends a request that may change resources, and drops the
+// responses cached from its origin once it succeeds, as RFC 9111 section 4.4
+// requires for the target URI and for its Location and Content-Location,
+// which must share its origin. The whole origin is dropped rather than only
+// the target URI and its parent, because a change shows in other
+// representations too: scaling a node pool changes the cluster returned by
+// name and in every page of the cluster list.
+func (t *Transport) invalidate(partition string, req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	if isUnsafe(req.Method) && resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		t.store.removeOrigin(partition, req.URL)
+	}
+
+	return resp, nil
+}
+
+// isUnsafe reports whether requests with the method may change resources.
+func isUnsafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	default:
+		return true
+	}
+}
+
+func (t *Transport) serveOffline(req *http.Request, cached *entry) (*http.Response, error) {
+	if cached == nil {
+		return nil, ErrNotCached
+	}
+
+	t.mu.Lock()
+	if t.staleSince.IsZero() || cached.StoredAt.Before(t.staleSince) {
+		t.staleSince = cached.StoredAt
+	}
+	t.mu.Unlock()
+
+	return cached.response(req, t.now()), nil
+}
+
+// storeResponse caches a successful response, unless it is too large or the
+// server forbids it, and returns an equivalent response to the caller.
+func (t *Transport) storeResponse(partition string, req *http.Request, resp *http.Response) (*http.Response, error) {
+	key := entryKey(req)
+
+	directives := parseCacheControl(resp.Header.Get(headerCacheControl))
+	if directives.has("no-store") {
+		t.store.remove(partition, req.URL, key)
+
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEntrySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if len(body) > maxEntrySize {
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	_ = t.store.save(partition, req.URL, key, &entry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   t.now(),
+	})
+
+	return resp, nil
+}
+
+// entry is a cached response.
+type entry struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"storedAt"`
+}
+
+func (e *entry) ETag() string {
+	return e.Header.Get(headerETag)
+}
+
+// isFresh reports whether the response can be used without revalidation,
+// according to its max-age directive.
+func (e *entry) isFresh(now time.Time) bool {
+	directives := parseCacheControl(e.Header.Get(headerCacheControl))
+	if directives.has("no-cache") {
+		return false
+	}
+
+	maxAge, ok := directives.seconds("max-age")
+	if !ok {
+		return false
+	}
+
+	initialAge, _ := strconv.Atoi(e.Header.Get(headerAge))
+
+	return now.Sub(e.StoredAt)+time.Duration(initialAge)*time.Second < maxAge
+}
+
+// revalidated updates the entry with the headers of a 304 Not Modified
+// response.
+func (e *entry) revalidated(header http.Header, now time.Time) {
+	for _, key := range []string{headerCacheControl, headerETag, headerAge, "Date", "Expires"} {
+		if values, ok := header[key]; ok {
+			e.Header[key] = values
+		}
+	}
+
+	if _, ok := header[headerAge]; !ok {
+		e.Header.Del(headerAge)
+	}
+
+	e.StoredAt = now
+}
+
+func (e *entry) response(req *http.Request, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set(headerAge, strconv.Itoa(int(now.Sub(e.StoredAt).Seconds())))
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxEntrySize))
+	_ = resp.Body.Close()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,406 @@
+package httpcache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+type origin struct {
+	*httptest.Server
+
+	requests     atomic.Int32
+	revalidated  atomic.Int32
+	body         atomic.Value
+	cacheControl string
+}
+
+func newOrigin(t *testing.T, cacheControl string) *origin {
+	t.Helper()
+
+	o := &origin{cacheControl: cacheControl}
+	o.body.Store("v1")
+
+	o.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.requests.Add(1)
+
+		if r.Method != http.MethodGet {
+			if r.URL.Path == "/conflict" {
+				w.WriteHeader(http.StatusConflict)
+			} else {
+				w.WriteHeader(http.StatusNoContent)
+			}
+
+			return
+		}
+
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		body, _ := o.body.Load().(string)
+		etag := `"` + body + `"`
+
+		if o.cacheControl != "" {
+			w.Header().Set(headerCacheControl, o.cacheControl)
+		}
+
+		w.Header().Set(headerETag, etag)
+
+		if r.Header.Get(headerIfNoneMatch) == etag {
+			o.revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(o.Close)
+
+	return o
+}
+
+func newTestTransport(t *testing.T, clock *fakeClock, options ...Option) *Transport {
+	t.Helper()
+
+	return New(t.TempDir(), http.DefaultTransport, append([]Option{WithClock(clock.Now)}, options...)...)
+}
+
+func get(t *testing.T, transport http.RoundTripper, ctx context.Context, url string) (*http.Response, string, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(body), nil
+}
+
+func TestRevalidatesWithETag(t *testing.T) {
+	server := newOrigin(t, "")
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	transport := newTestTransport(t, clock)
+	ctx := WithPartition(context.Background(), "account")
+
+	_, body, err := get(t, transport, ctx, server.URL+"/clusters")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", body)
+
+	resp, body, err := get(t, transport, ctx, server.URL+"/clusters")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "a 304 is served as the cached response")
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, int32(2), server.requests.Load())
+	assert.Equal(t, int32(1), server.revalidated.Load())
+
+	server.body.Store("v2")
+
+	_, body, err = get(t, transport, ctx, server.URL+"/clusters")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", body, "a changed resource replaces the cached response")
+}
+
+func TestServesFreshResponses(t *testing.T) {
+	server := newOrigin(t, "private, max-age=60")
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	transport := newTestTransport(t, clock)
+	ctx := WithPartition(context.Background(), "account")
+
+	_, _, err := get(t, transport, ctx, server.URL+"/models")
+	require.NoError(t, err)
+
+	clock.Advance(30 * time.Second)
+
+	resp, body, err := get(t, transport, ctx, server.URL+"/models")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, "30", resp.Header.Get(headerAge))
+	assert.Equal(t, int32(1), server.requests.Load(), "a fresh response is not revalidated")
+
+	clock.Advance(time.Minute)
+
+	_, _, err = get(t, transport, ctx, server.URL+"/models")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), server.requests.Load(), "a stale response is revalidated")
+	assert.Equal(t, int32(1), server.revalidated.Load())
+}
+
+func TestDoesNotStore(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+
+	t.Run("no-store", func(t *testing.T) {
+		server := newOrigin(t, "no-store")
+		transport := newTestTransport(t, clock)
+		ctx := WithPartition(context.Background(), "account")
+
+		for range 2 {
+			_, _, err := get(t, transport, ctx, server.URL+"/secrets")
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(0), server.revalidated.Load())
+	})
+
+	t.Run("without partition", func(t *testing.T) {
+		server := newOrigin(t, "max-age=60")
+		transport := newTestTransport(t, clock)
+
+		for range 2 {
+			_, _, err := get(t, transport, context.Background(), server.URL+"/me")
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), server.requests.Load())
+	})
+
+	t.Run("bypass", func(t *testing.T) {
+		server := newOrigin(t, "max-age=60")
+		transport := newTestTransport(t, clock, WithMode(ModeBypass))
+		ctx := WithPartition(context.Background(), "account")
+
+		for range 2 {
+			_, _, err := get(t, transport, ctx, server.URL+"/clusters")
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), server.requests.Load())
+		assert.Equal(t, int32(0), server.revalidated.Load())
+	})
+}
+
+func TestPartitions(t *testing.T) {
+	server := newOrigin(t, "max-age=60")
+	clock := &fakeClock{now: time.Now()}
+	transport := newTestTransport(t, clock)
+
+	_, _, err := get(t, transport, WithPartition(context.Background(), "alice"), server.URL+"/clusters")
+	require.NoError(t, err)
+
+	_, _, err = get(t, transport, WithPartition(context.Background(), "bob"), server.URL+"/clusters")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), server.requests.Load(), "accounts do not share cached responses")
+}
+
+func TestRemovePartition(t *testing.T) {
+	server := newOrigin(t, "max-age=60")
+	clock := &fakeClock{now: time.Now()}
+	dir := t.TempDir()
+	transport := New(dir, http.DefaultTransport, WithClock(clock.Now))
+	alice := WithPartition(context.Background(), "alice")
+	bob := WithPartition(context.Background(), "bob")
+
+	for _, ctx := range []context.Context{alice, bob} {
+		_, _, err := get(t, transport, ctx, server.URL+"/clusters")
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, RemovePartition(dir, "alice"))
+
+	for _, ctx := range []context.Context{alice, bob} {
+		_, _, err := get(t, transport, ctx, server.URL+"/clusters")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(3), server.requests.Load(), "only the removed partition is fetched again")
+
+	require.NoError(t, Clear(dir))
+	assert.NoDirExists(t, dir)
+}
+
+func TestRemovesMissingResources(t *testing.T) {
+	server := newOrigin(t, "")
+	clock := &fakeClock{now: time.Now()}
+	transport := newTestTransport(t, clock)
+	ctx := WithPartition(context.Background(), "account")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/missing", nil)
+	require.NoError(t, err)
+
+	err = transport.store.save("account", req.URL, entryKey(req), &entry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       []byte("deleted"),
+		StoredAt:   clock.Now(),
+	})
+	require.NoError(t, err)
+
+	resp, _, err := get(t, transport, ctx, server.URL+"/missing")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	_, err = transport.store.load("account", req.URL, entryKey(req))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInvalidatesAfterChanges(t *testing.T) {
+	server := newOrigin(t, "max-age=60")
+	clock := &fakeClock{now: time.Now()}
+	transport := newTestTransport(t, clock)
+	ctx := WithPartition(context.Background(), "account")
+	other := WithPartition(context.Background(), "other")
+	urls := []string{
+		server.URL + "/clusters/1",
+		server.URL + "/clusters?pageSize=100",
+		server.URL + "/clusters/by-name/dev",
+	}
+
+	send := func(ctx context.Context, method, url string) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		require.NoError(t, err)
+
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	for _, url := range urls {
+		_, _, err := get(t, transport, ctx, url)
+		require.NoError(t, err)
+	}
+
+	_, _, err := get(t, transport, other, server.URL+"/clusters/1")
+	require.NoError(t, err)
+	require.Equal(t, int32(4), server.requests.Load())
+
+	send(ctx, http.MethodPatch, server.URL+"/conflict")
+
+	_, _, err = get(t, transport, ctx, urls[0])
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), server.requests.Load(), "failed changes keep cached responses")
+
+	send(ctx, http.MethodPatch, server.URL+"/clusters/1/nodepools/2")
+	require.Equal(t, int32(6), server.requests.Load())
+
+	for _, url := range urls {
+		_, _, err = get(t, transport, ctx, url)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(9), server.requests.Load(), "responses are not served after a change")
+
+	_, _, err = get(t, transport, other, server.URL+"/clusters/1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(9), server.requests.Load(), "other accounts keep their cached responses")
+}
+
+func TestOffline(t *testing.T) {
+	server := newOrigin(t, "")
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	dir := t.TempDir()
+	ctx := WithPartition(context.Background(), "account")
+
+	online := New(dir, http.DefaultTransport, WithClock(clock.Now))
+
+	_, _, err := get(t, online, ctx, server.URL+"/clusters")
+	require.NoError(t, err)
+
+	storedAt := clock.Now()
+	clock.Advance(time.Hour)
+
+	offline := New(dir, http.DefaultTransport, WithClock(clock.Now), WithMode(ModeOffline))
+
+	_, stale := offline.Stale()
+	assert.False(t, stale)
+
+	resp, body, err := get(t, offline, ctx, server.URL+"/clusters")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, "3600", resp.Header.Get(headerAge))
+	assert.Equal(t, int32(1), server.requests.Load(), "nothing is sent offline")
+
+	since, stale := offline.Stale()
+	assert.True(t, stale)
+	assert.Equal(t, storedAt, since)
+
+	_, _, err = get(t, offline, ctx, server.URL+"/teams")
+	require.ErrorIs(t, err, ErrNotCached)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, server.URL+"/clusters/1", nil)
+	require.NoError(t, err)
+
+	_, err = offline.RoundTrip(req)
+	require.ErrorIs(t, err, ErrOffline)
+	assert.Equal(t, int32(1), server.requests.Load())
+}
+
+func TestEntryFiles(t *testing.T) {
+	server := newOrigin(t, "")
+	dir := t.TempDir()
+	transport := New(dir, http.DefaultTransport)
+
+	_, _, err := get(t, transport, WithPartition(context.Background(), "oid.tid"), server.URL+"/clusters")
+	require.NoError(t, err)
+
+	var files []string
+
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		require.NoError(t, err)
+
+		if !d.IsDir() {
+			files = append(files, path)
+
+			info, err := d.Info()
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(entryFileMode), info.Mode().Perm())
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, files, 1)
+	assert.False(t, strings.Contains(files[0], "oid.tid"), "account IDs do not appear in file names")
+	assert.True(t, strings.HasSuffix(files[0], ".json"))
+}
+
+func TestParseCacheControl(t *testing.T) {
+	directives := parseCacheControl(`private, Max-Age=120, no-cache="Set-Cookie"`)
+
+	assert.True(t, directives.has("private"))
+	assert.True(t, directives.has("no-cache"))
+	assert.False(t, directives.has("no-store"))
+
+	maxAge, ok := directives.seconds("max-age")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, maxAge)
+
+	_, ok = directives.seconds("s-maxage")
+	assert.False(t, ok)
+}
@@ -0,0 +1,115 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	entryFileMode = 0o600
+	entryDirMode  = 0o700
+)
+
+// store keeps one file per cached response, in a directory per partition and
+// origin. File names are hashes, so that neither account IDs nor URLs appear
+// in them.
+type store struct {
+	dir string
+}
+
+func entryKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String()
+}
+
+func (s store) path(partition string, origin *url.URL, key string) string {
+	return filepath.Join(s.originDir(partition, origin), hash(key)+".json")
+}
+
+func (s store) originDir(partition string, origin *url.URL) string {
+	return filepath.Join(s.partitionDir(partition), hash(origin.Scheme + "://" + origin.Host)[:16])
+}
+
+func (s store) partitionDir(partition string) string {
+	return filepath.Join(s.dir, hash(partition)[:16])
+}
+
+func (s store) load(partition string, origin *url.URL, key string) (*entry, error) {
+	data, err := os.ReadFile(s.path(partition, origin, key))
+	if err != nil {
+		return nil, fmt.Errorf("could not read cache entry: %w", err)
+	}
+
+	var cached entry
+	if err = json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("could not decode cache entry: %w", err)
+	}
+
+	return &cached, nil
+}
+
+// save writes the entry to a temporary file that replaces the previous entry,
+// so that concurrent readers never see a partial entry.
+func (s store) save(partition string, origin *url.URL, key string, cached *entry) error {
+	path := s.path(partition, origin, key)
+
+	if err := os.MkdirAll(filepath.Dir(path), entryDirMode); err != nil {
+		return fmt.Errorf("could not create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("could not encode cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), strings.TrimSuffix(filepath.Base(path), ".json")+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create cache entry: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+
+	if err = os.Chmod(tmp.Name(), entryFileMode); err != nil {
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s store) remove(partition string, origin *url.URL, key string) {
+	_ = os.Remove(s.path(partition, origin, key))
+}
+
+// removeOrigin removes every response cached from the origin of the URL in
+// the partition.
+func (s store) removeOrigin(partition string, origin *url.URL) {
+	_ = os.RemoveAll(s.originDir(partition, origin))
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
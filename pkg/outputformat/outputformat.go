@@ -23,3 +23,9 @@ func (o *Format) Set(value string) error {
 func (o *Format) Type() string {
 	return "outputFormat"
 }
+
+// IsStructured reports whether the format is a machine readable encoding
+// rather than a table.
+func (o *Format) IsStructured() bool {
+	return *o == "json" || *o == "yaml"
+}
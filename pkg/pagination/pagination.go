@@ -0,0 +1,96 @@
+package pagination
+
+import (
+	"io"
+	"iter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/intility/indev/internal/redact"
+	"github.com/intility/indev/internal/ux"
+	"github.com/intility/indev/pkg/client"
+)
+
+// DefaultPageSize is the number of items requested per page by the list
+// commands.
+const DefaultPageSize = 100
+
+var (
+	errNegativeLimit    = redact.Errorf("--limit must not be negative")
+	errNegativePageSize = redact.Errorf("--page-size must not be negative")
+)
+
+// AddFlags registers the --limit and --page-size flags of a list command.
+func AddFlags(cmd *cobra.Command, options *client.ListOptions) {
+	cmd.Flags().IntVar(&options.Limit, "limit", 0, "Maximum number of items to list (0 lists all)")
+	cmd.Flags().IntVar(&options.PageSize, "page-size", DefaultPageSize, "Number of items to fetch per request")
+}
+
+// Validate checks the values of the pagination flags.
+func Validate(options client.ListOptions) error {
+	if options.Limit < 0 {
+		return errNegativeLimit
+	}
+
+	if options.PageSize < 0 {
+		return errNegativePageSize
+	}
+
+	return nil
+}
+
+// StreamTable renders items as a table, writing rows one page at a time as
+// they arrive. It returns the number of rows written.
+func StreamTable[T any](
+	writer io.Writer,
+	items iter.Seq2[T, error],
+	pageSize int,
+	rowFactory ux.ColFactory[T],
+) (int, error) {
+	table := ux.NewTableStream(writer, rowFactory)
+
+	for page, err := range batch(items, pageSize) {
+		if err != nil {
+			return table.Rows(), err
+		}
+
+		table.Write(page)
+	}
+
+	return table.Rows(), nil
+}
+
+// batch groups the items of an iterator into slices of at most size items,
+// so that a table is written a page worth of rows at a time. A size of zero
+// or less yields a single batch holding all items.
+func batch[T any](seq iter.Seq2[T, error], size int) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		var page []T
+
+		for item, err := range seq {
+			if err != nil {
+				if len(page) > 0 && !yield(page, nil) {
+					return
+				}
+
+				yield(nil, err)
+
+				return
+			}
+
+			page = append(page, item)
+
+			if size > 0 && len(page) >= size {
+				if !yield(page, nil) {
+					return
+				}
+
+				page = nil
+			}
+		}
+
+		if len(page) > 0 {
+			yield(page, nil)
+		}
+	}
+}
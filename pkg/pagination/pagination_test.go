@@ -0,0 +1,53 @@
+package pagination
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/internal/ux"
+)
+
+func TestBatch(t *testing.T) {
+	seq := func(yield func(int, error) bool) {
+		for i := range 5 {
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+
+	var batches [][]int
+
+	for page, err := range batch(seq, 2) {
+		require.NoError(t, err)
+
+		batches = append(batches, page)
+	}
+
+	assert.Equal(t, [][]int{{0, 1}, {2, 3}, {4}}, batches)
+}
+
+func TestStreamTable(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	// the rows received before the error are written
+	seq := func(yield func(string, error) bool) {
+		if yield("alice", nil) && yield("bob", nil) {
+			yield("", errFailed)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	count, err := StreamTable(&buf, seq, 10, func(name string) []ux.Row {
+		return []ux.Row{ux.NewRow("Name", name)}
+	})
+	require.ErrorIs(t, err, errFailed)
+	assert.Equal(t, 2, count)
+	assert.Contains(t, buf.String(), "alice")
+	assert.Contains(t, buf.String(), "bob")
+}
@@ -1,12 +1,25 @@
 package rootcommand
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/term"
 
 	"github.com/intility/indev/internal/build"
+	"github.com/intility/indev/internal/cli"
+	"github.com/intility/indev/internal/env"
+	"github.com/intility/indev/internal/redact"
 	"github.com/intility/indev/internal/telemetry"
 	"github.com/intility/indev/internal/ux"
 	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/cassette"
 	"github.com/intility/indev/pkg/client"
 	"github.com/intility/indev/pkg/clientset"
 	"github.com/intility/indev/pkg/commands/account"
@@ -15,15 +28,140 @@ import (
 	"github.com/intility/indev/pkg/commands/ai/deployment"
 	"github.com/intility/indev/pkg/commands/cluster"
 	"github.com/intility/indev/pkg/commands/cluster/access"
+	"github.com/intility/indev/pkg/commands/pullsecret"
+	sandboxcmd "github.com/intility/indev/pkg/commands/sandbox"
 	"github.com/intility/indev/pkg/commands/teams"
 	"github.com/intility/indev/pkg/commands/teams/member"
 	"github.com/intility/indev/pkg/commands/user"
+	"github.com/intility/indev/pkg/config"
+	"github.com/intility/indev/pkg/httpcache"
+	"github.com/intility/indev/pkg/sandbox"
+	"github.com/intility/indev/pkg/tokencache"
+	"github.com/intility/indev/pkg/transport"
+)
+
+var (
+	errNegativeRetries    = redact.Errorf("--retries must not be negative")
+	errOfflineNoCache     = redact.Errorf("--offline and --no-cache cannot be used together")
+	errOfflineUnavailable = redact.Errorf("--offline cannot be used with INDEV_HTTP_CASSETTE")
 )
 
+// GlobalOptions contains the options that apply to every command.
+type GlobalOptions struct {
+	Retries   int
+	Timeout   time.Duration
+	DebugHTTP bool
+	Account   string
+	NoCache   bool
+	Offline   bool
+	Transport transport.Config
+}
+
+// globalTargets are the objects that the global options are applied to.
+type globalTargets struct {
+	platformClient *client.RestClient
+	tokenSource    *authenticator.TokenSource
+	acquirer       authenticator.TokenAcquirer
+	interactive    *authenticator.Authenticator
+	baseTransport  *http.Transport
+	// cache is nil when responses are recorded or replayed
+	cache *httpcache.Transport
+}
+
 func GetRootCommand() *cobra.Command {
+	// run the persistent hooks of every parent command, so that the global
+	// options are applied even when a subcommand defines its own hooks
+	cobra.EnableTraverseRunHooks = true
+
+	// the transport is shared by the platform client and the authenticator,
+	// and configured from the global flags before any request is sent
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // always a *http.Transport
+
+	authHTTPClient := &http.Client{Transport: baseTransport}
+
+	// the account selected with `indev account switch`, and the network
+	// settings of the config file
+	settings, settingsErr := config.Load(config.DefaultPath())
+
+	// the token cache is plaintext or encrypted, as selected in the settings
+	tokenCache, tokenCacheErr := tokencache.Open(settings.TokenCache, cli.CreatePassphrasePrompter(os.Stderr, false))
+	if tokenCacheErr != nil {
+		tokenCacheErr = redact.Errorf("invalid token cache settings: %w", redact.Safe(tokenCacheErr))
+		tokenCache = tokencache.New()
+	}
+
+	interactive := authenticator.NewAuthenticator(
+		authenticator.ConfigFromBuildProps(),
+		authenticator.WithTokenCache(tokenCache),
+		authenticator.WithHTTPClient(authHTTPClient),
+		authenticator.WithAccount(settings.Account),
+		authenticator.WithTenant(settings.Tenant),
+		// scripts get an error instead of a sign-in prompt when the
+		// platform asks for a new sign-in, and users are signed in the way
+		// that works on their machine, as with login
+		authenticator.WithAutomaticFlow(printToStderr),
+		authenticator.WithInteraction(term.IsTerminal(int(os.Stdin.Fd()))), //nolint:gosec // G115 - stdin fd fits in int
+	)
+
+	// pipelines sign in with the credentials in their environment, users
+	// with the account they signed in to with `indev login`, or with `az
+	// login` if they opted in
+	acquirer := authenticator.NewCredentialChain(
+		authenticator.ChainConfig{
+			Token:              env.Token(),
+			TenantID:           env.TenantID(),
+			ClientID:           env.ClientID(),
+			ClientSecret:       env.ClientSecret(),
+			FederatedTokenFile: env.FederatedTokenFile(),
+			AzureCLI:           settings.AzureCLI || env.UseAzureCLI(),
+			AzureCLITenant:     settings.Tenant,
+		},
+		interactive,
+		authenticator.WithClientCredentialsHTTPClient(authHTTPClient),
+	)
+
+	var platformTransport http.RoundTripper = baseTransport
+
+	clientOptions := make([]client.RestClientOption, 0)
+
+	// commands run against the sandbox do not need a signed-in user
+	if sandboxURL := env.Sandbox(); sandboxURL != "" {
+		clientOptions = append(clientOptions, client.WithBaseURIs(sandboxURL, sandboxURL))
+		acquirer = authenticator.NewStaticAcquirer(sandbox.TenantID, "sandbox")
+	}
+
+	var cache *httpcache.Transport
+
+	recorder, cassetteErr := newRecorder(baseTransport)
+	if recorder != nil {
+		platformTransport = recorder
+
+		// replayed commands do not need a signed-in user
+		if recorder.Mode() == cassette.ModeReplay {
+			acquirer = authenticator.NewStaticAcquirer(recorder.TenantID(), "replay")
+		}
+	} else {
+		// a cassette must see every request, so responses are only cached
+		// when nothing is recorded or replayed
+		cache = httpcache.New(httpcache.DefaultDir(), platformTransport)
+		platformTransport = cache
+	}
+
+	clientOptions = append(clientOptions, client.WithHTTPClient(&http.Client{
+		Transport: otelhttp.NewTransport(platformTransport),
+	}))
+
+	setupErr := errors.Join(cassetteErr, settingsErr, tokenCacheErr)
+
+	// the token source is shared by the pre-run hooks and the platform
+	// client, so that a command acquires its access token only once
+	tokenSource := authenticator.NewTokenSource(acquirer)
+	platformClient := client.New(append(clientOptions, client.WithTokenSource(tokenSource))...)
 	clients := clientset.ClientSet{
-		Authenticator:  authenticator.NewAuthenticator(authenticator.ConfigFromBuildProps()),
-		PlatformClient: client.New(),
+		Authenticator:  tokenSource,
+		PlatformClient: platformClient,
+		HTTPClient:     authHTTPClient,
+		TokenCache:     tokenCache,
 	}
 
 	rootCmd := &cobra.Command{
@@ -34,23 +172,217 @@ func GetRootCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 
+	addGlobalFlags(rootCmd, globalTargets{
+		platformClient: platformClient,
+		tokenSource:    tokenSource,
+		acquirer:       acquirer,
+		interactive:    interactive,
+		baseTransport:  baseTransport,
+		cache:          cache,
+	}, networkDefaults(settings.Network), setupErr)
+
 	rootCmd.AddCommand(getVersionCommand())
 	rootCmd.AddCommand(account.NewLoginCommand(clients))
 	rootCmd.AddCommand(account.NewLogoutCommand(clients))
 	rootCmd.AddCommand(getClusterCommand(clients))
 	rootCmd.AddCommand(getAccountCommand(clients))
+	rootCmd.AddCommand(getPullSecretCommand(clients))
 	rootCmd.AddCommand(getTeamsCommand(clients))
 	rootCmd.AddCommand(getUserCommand(clients))
 	rootCmd.AddCommand(getAICommand(clients))
+	rootCmd.AddCommand(getSandboxCommand())
+
+	// help and shell completion work with broken settings, so that they can
+	// be used to fix them
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "help" || cmd.Name() == "completion" {
+			runWithoutSetup(cmd)
+		}
+	}
 
 	return rootCmd
 }
 
+func runWithoutSetup(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations[cli.AnnotationWithoutSetup] = "true"
+}
+
+// printToStderr prints the device code message of sign-ins that happen in
+// the middle of a command, so that it does not mix with the output.
+func printToStderr(_ context.Context, message string) error {
+	ux.Fprintf(os.Stderr, "%s\n", message)
+
+	return nil
+}
+
+// newRecorder creates the HTTP cassette recorder configured through the
+// INDEV_HTTP_CASSETTE environment variable, if any.
+func newRecorder(next http.RoundTripper) (*cassette.Recorder, error) {
+	spec := env.HTTPCassette()
+	if spec == "" {
+		return nil, nil //nolint:nilnil // recording is disabled
+	}
+
+	recorder, err := cassette.NewFromSpec(spec, next)
+	if err != nil {
+		return nil, redact.Errorf("invalid INDEV_HTTP_CASSETTE: %w", redact.Safe(err))
+	}
+
+	return recorder, nil
+}
+
+// networkDefaults returns the network settings that apply when their flags are
+// not set: the environment variables take precedence over the config file.
+func networkDefaults(network config.Network) transport.Config {
+	return transport.Config{
+		Proxy:      cmp.Or(env.Proxy(), network.Proxy),
+		NoProxy:    cmp.Or(env.NoProxy(), network.NoProxy),
+		CABundle:   cmp.Or(env.CABundle(), network.CABundle),
+		ClientCert: cmp.Or(env.ClientCert(), network.ClientCert),
+		ClientKey:  cmp.Or(env.ClientKey(), network.ClientKey),
+	}
+}
+
+// addGlobalFlags registers the flags shared by every command. network holds
+// the defaults of the network flags, and setupErr is an error encountered
+// while building the root command, which is reported before any command runs
+// that needs the settings or the token cache.
+func addGlobalFlags(rootCmd *cobra.Command, targets globalTargets, network transport.Config, setupErr error) {
+	var options GlobalOptions
+
+	rootCmd.PersistentFlags().IntVar(&options.Retries,
+		"retries", client.DefaultRetryPolicy().MaxRetries, "Number of times a failed request is retried")
+
+	rootCmd.PersistentFlags().DurationVar(&options.Timeout,
+		"timeout", client.DefaultHTTPTimeout, "Timeout of each request to the platform (0 disables the timeout)")
+
+	rootCmd.PersistentFlags().BoolVar(&options.DebugHTTP,
+		"debug-http", false, "Print requests to the platform and their responses to stderr, with secrets redacted")
+
+	rootCmd.PersistentFlags().StringVar(&options.Account,
+		"account", "", "Username of the signed-in account to use (see 'indev account list')")
+
+	rootCmd.PersistentFlags().BoolVar(&options.NoCache,
+		"no-cache", false, "Fetch every response from the platform instead of the local cache")
+
+	rootCmd.PersistentFlags().BoolVar(&options.Offline,
+		"offline", false, "Show the last fetched data from the local cache without contacting the platform")
+
+	rootCmd.PersistentFlags().StringVar(&options.Transport.Proxy,
+		"proxy", network.Proxy, "URL of the proxy used for all requests (defaults to HTTPS_PROXY)")
+
+	rootCmd.PersistentFlags().StringVar(&options.Transport.NoProxy,
+		"no-proxy", network.NoProxy, "Comma-separated hosts reached without the proxy (defaults to NO_PROXY)")
+
+	rootCmd.PersistentFlags().StringVar(&options.Transport.CABundle,
+		"ca-bundle", network.CABundle, "PEM file with certificates to trust in addition to the system ones")
+
+	rootCmd.PersistentFlags().StringVar(&options.Transport.ClientCert,
+		"client-cert", network.ClientCert, "PEM client certificate presented to servers that ask for one")
+
+	rootCmd.PersistentFlags().StringVar(&options.Transport.ClientKey,
+		"client-key", network.ClientKey, "PEM private key of the client certificate (defaults to --client-cert)")
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if setupErr != nil && !cli.RunsWithoutSetup(cmd) {
+			cmd.SilenceUsage = true
+
+			return setupErr
+		}
+
+		if options.Retries < 0 {
+			cmd.SilenceUsage = true
+
+			return errNegativeRetries
+		}
+
+		if err := transport.Configure(targets.baseTransport, options.Transport); err != nil {
+			cmd.SilenceUsage = true
+
+			return redact.Errorf("invalid network settings: %w", redact.Safe(err))
+		}
+
+		if options.Account != "" {
+			targets.interactive.Configure(
+				authenticator.WithAccount(options.Account),
+				authenticator.WithTenant(""),
+			)
+		}
+
+		if err := configureCache(targets, options); err != nil {
+			cmd.SilenceUsage = true
+
+			return err
+		}
+
+		// cache misses are not retried, as nothing would change offline
+		if options.Offline {
+			options.Retries = 0
+		}
+
+		targets.platformClient.Configure(
+			client.WithRetries(options.Retries),
+			client.WithTimeout(options.Timeout),
+		)
+
+		if options.DebugHTTP {
+			targets.platformClient.Configure(client.WithDebugWriter(cmd.ErrOrStderr()))
+		}
+
+		return nil
+	}
+
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		if targets.cache == nil {
+			return
+		}
+
+		if storedAt, stale := targets.cache.Stale(); stale {
+			ux.Fwarningf(cmd.ErrOrStderr(),
+				"offline: showing data cached %s, which may be out of date\n",
+				storedAt.Local().Format(time.DateTime))
+		}
+	}
+}
+
+// configureCache sets how the response cache is used. Offline commands use
+// the signed-in account without refreshing its token, as no requests are
+// sent to the platform.
+func configureCache(targets globalTargets, options GlobalOptions) error {
+	switch {
+	case options.Offline && options.NoCache:
+		return errOfflineNoCache
+	case options.Offline && targets.cache == nil:
+		return errOfflineUnavailable
+	case targets.cache == nil:
+		return nil
+	case options.Offline:
+		targets.cache.Configure(httpcache.WithMode(httpcache.ModeOffline))
+		targets.tokenSource.Configure(authenticator.WithAcquirer(
+			authenticator.NewOfflineAcquirer(targets.acquirer),
+		))
+	case options.NoCache:
+		targets.cache.Configure(httpcache.WithMode(httpcache.ModeBypass))
+	}
+
+	return nil
+}
+
 func getVersionCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
 		Short: "Print the version information",
 		Long:  `Print the version information of indev.`,
+		Annotations: map[string]string{
+			cli.AnnotationWithoutSetup: "true",
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			_, span := telemetry.StartSpan(cmd.Context(), "version")
 			defer span.End()
@@ -74,8 +406,44 @@ func getClusterCommand(set clientset.ClientSet) *cobra.Command {
 	cmd.AddCommand(cluster.NewListCommand(set))
 	cmd.AddCommand(cluster.NewLoginCommand(set))
 	cmd.AddCommand(cluster.NewOpenCommand(set))
+	cmd.AddCommand(cluster.NewScaleCommand(set))
 	cmd.AddCommand(cluster.NewStatusCommand(set))
+	cmd.AddCommand(cluster.NewUpgradeCommand(set))
+	cmd.AddCommand(cluster.NewVersionsCommand(set))
 	cmd.AddCommand(getAccessCommand(set))
+	cmd.AddCommand(getNodePoolCommand(set))
+
+	return cmd
+}
+
+func getNodePoolCommand(set clientset.ClientSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nodepool",
+		Short: "Manage the node pools of a cluster",
+		Long:  "Manage the node pools of a cluster",
+		Run:   showHelp,
+	}
+
+	cmd.AddCommand(cluster.NewNodePoolListCommand(set))
+	cmd.AddCommand(cluster.NewNodePoolAddCommand(set))
+	cmd.AddCommand(cluster.NewNodePoolUpdateCommand(set))
+	cmd.AddCommand(cluster.NewNodePoolDeleteCommand(set))
+
+	return cmd
+}
+
+func getPullSecretCommand(set clientset.ClientSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pullsecret",
+		Short: "Manage the pull secrets that clusters use to pull images",
+		Long:  "Manage the pull secrets that clusters use to pull images from private registries",
+		Run:   showHelp,
+	}
+
+	cmd.AddCommand(pullsecret.NewCreateCommand(set))
+	cmd.AddCommand(pullsecret.NewListCommand(set))
+	cmd.AddCommand(pullsecret.NewGetCommand(set))
+	cmd.AddCommand(pullsecret.NewDeleteCommand(set))
 
 	return cmd
 }
@@ -104,6 +472,10 @@ func getAccountCommand(set clientset.ClientSet) *cobra.Command {
 	}
 
 	cmd.AddCommand(account.NewShowCommand(set))
+	cmd.AddCommand(account.NewListCommand(set))
+	cmd.AddCommand(account.NewSwitchCommand(set))
+	cmd.AddCommand(account.NewEncryptCacheCommand(set))
+	cmd.AddCommand(account.NewTokenCommand(set))
 
 	return cmd
 }
@@ -213,6 +585,19 @@ func getAIAPIKeyCommand(set clientset.ClientSet) *cobra.Command {
 	return cmd
 }
 
+func getSandboxCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sandbox",
+		Short: "Run a local fake of the Intility Developer Platform",
+		Long:  "Run a local fake of the Intility Developer Platform",
+		Run:   showHelp,
+	}
+
+	cmd.AddCommand(sandboxcmd.NewServeCommand())
+
+	return cmd
+}
+
 func showHelp(cmd *cobra.Command, args []string) {
 	_, span := telemetry.StartSpan(cmd.Context(), cmd.Use)
 	defer span.End()
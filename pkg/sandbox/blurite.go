@@ -0,0 +1,202 @@
+package sandbox
+
+import (
+	"crypto/rand"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/intility/indev/pkg/client"
+)
+
+const (
+	apiKeyPrefixLength = 8
+	defaultAPIKeyTTL   = 90
+	hoursPerDay        = 24
+)
+
+type deployment struct {
+	client.AIDeployment
+	apiKeys []client.AIAPIKey
+}
+
+func (s *Server) findDeployment(match func(*deployment) bool) (*deployment, bool) {
+	index := slices.IndexFunc(s.deployments, match)
+	if index < 0 {
+		return nil, false
+	}
+
+	return s.deployments[index], true
+}
+
+func (s *Server) deploymentByID(w http.ResponseWriter, r *http.Request) (*deployment, bool) {
+	d, ok := s.findDeployment(func(d *deployment) bool { return d.ID == r.PathValue("id") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "deployment "+r.PathValue("id")+" does not exist")
+	}
+
+	return d, ok
+}
+
+func (s *Server) createdBy() client.AIDeploymentCreatedBy {
+	return client.AIDeploymentCreatedBy{ID: s.users[0].ID, Name: s.users[0].Name, UPN: s.users[0].UPN}
+}
+
+func (s *Server) listModels(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.models)
+}
+
+func (s *Server) listDeployments(w http.ResponseWriter, r *http.Request) {
+	deployments := make([]client.AIDeployment, 0, len(s.deployments))
+	for _, d := range s.deployments {
+		deployments = append(deployments, d.AIDeployment)
+	}
+
+	writePage(w, r, deployments)
+}
+
+func (s *Server) createDeployment(w http.ResponseWriter, r *http.Request) {
+	var request client.NewAIDeploymentRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeProblem(w, http.StatusBadRequest, "deployment name is required")
+		return
+	}
+
+	if !slices.ContainsFunc(s.models, func(m client.AIModel) bool { return m.ID == request.Model }) {
+		writeProblem(w, http.StatusBadRequest, "model "+request.Model+" does not exist")
+		return
+	}
+
+	if _, exists := s.findDeployment(func(d *deployment) bool { return d.Name == request.Name }); exists {
+		writeProblem(w, http.StatusConflict, "deployment "+request.Name+" already exists")
+		return
+	}
+
+	d := &deployment{
+		AIDeployment: client.AIDeployment{
+			ID:        uuid.NewString(),
+			Name:      request.Name,
+			Model:     request.Model,
+			Endpoint:  "https://" + request.Name + ".blurite.sandbox.local/v1",
+			CreatedBy: s.createdBy(),
+		},
+		apiKeys: nil,
+	}
+
+	s.deployments = append(s.deployments, d)
+
+	writeJSON(w, http.StatusCreated, d.AIDeployment)
+}
+
+func (s *Server) getDeployment(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.findDeployment(func(d *deployment) bool { return d.Name == r.PathValue("name") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "deployment "+r.PathValue("name")+" does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d.AIDeployment)
+}
+
+func (s *Server) deleteDeployment(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.deploymentByID(w, r); !ok {
+		return
+	}
+
+	s.deployments = slices.DeleteFunc(s.deployments, func(d *deployment) bool { return d.ID == r.PathValue("id") })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.deploymentByID(w, r)
+	if !ok {
+		return
+	}
+
+	writePage(w, r, d.apiKeys)
+}
+
+func (s *Server) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.deploymentByID(w, r)
+	if !ok {
+		return
+	}
+
+	var request client.NewAIAPIKeyRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeProblem(w, http.StatusBadRequest, "API key name is required")
+		return
+	}
+
+	if slices.ContainsFunc(d.apiKeys, func(k client.AIAPIKey) bool { return k.Name == request.Name }) {
+		writeProblem(w, http.StatusConflict, "API key "+request.Name+" already exists")
+		return
+	}
+
+	ttl := request.TTLDays
+	if ttl <= 0 {
+		ttl = defaultAPIKeyTTL
+	}
+
+	secret := "sk-sandbox-" + rand.Text()
+	now := s.now().UTC()
+
+	key := client.AIAPIKeyWithSecret{
+		AIAPIKey: client.AIAPIKey{
+			ID:        uuid.NewString(),
+			Name:      request.Name,
+			Prefix:    secret[:len("sk-sandbox-")+apiKeyPrefixLength],
+			CreatedBy: s.createdBy(),
+			CreatedAt: now.Format(time.RFC3339),
+			ExpiresAt: now.Add(time.Duration(ttl) * hoursPerDay * time.Hour).Format(time.RFC3339),
+		},
+		Key: secret,
+	}
+
+	d.apiKeys = append(d.apiKeys, key.AIAPIKey)
+
+	writeJSON(w, http.StatusCreated, key)
+}
+
+func (s *Server) getAPIKey(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.deploymentByID(w, r)
+	if !ok {
+		return
+	}
+
+	index := slices.IndexFunc(d.apiKeys, func(k client.AIAPIKey) bool { return k.Name == r.PathValue("name") })
+	if index < 0 {
+		writeProblem(w, http.StatusNotFound, "API key "+r.PathValue("name")+" does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d.apiKeys[index])
+}
+
+func (s *Server) deleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.deploymentByID(w, r)
+	if !ok {
+		return
+	}
+
+	before := len(d.apiKeys)
+	d.apiKeys = slices.DeleteFunc(d.apiKeys, func(k client.AIAPIKey) bool { return k.ID == r.PathValue("keyId") })
+
+	if len(d.apiKeys) == before {
+		writeProblem(w, http.StatusNotFound, "API key "+r.PathValue("keyId")+" does not exist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
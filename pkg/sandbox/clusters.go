@@ -0,0 +1,408 @@
+package sandbox
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/intility/indev/pkg/client"
+)
+
+const (
+	defaultClusterVersion = "4.17"
+	defaultNodeReplicas   = 2
+
+	// failingClusterPrefix makes provisioning of a cluster fail, so that
+	// error handling can be demonstrated.
+	failingClusterPrefix = "fail-"
+)
+
+type cluster struct {
+	client.Cluster
+	createdAt time.Time
+	// upgradedAt is when the last upgrade started, or zero if the cluster
+	// was never upgraded.
+	upgradedAt time.Time
+	members    []client.ClusterMember
+}
+
+// seedVersions returns the OpenShift versions that the sandbox offers: one
+// that has reached its end of life, the default version, and two others.
+func seedVersions() []client.ClusterVersion {
+	date := func(year int, month time.Month, day int) *time.Time {
+		t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	return []client.ClusterVersion{
+		{Version: "4.18", Default: false, Supported: true, EndOfLife: date(2027, time.August, 25)},
+		{Version: defaultClusterVersion, Default: true, Supported: true, EndOfLife: date(2027, time.April, 1)},
+		{Version: "4.16", Default: false, Supported: true, EndOfLife: date(2026, time.December, 27)},
+		{Version: "4.15", Default: false, Supported: false, EndOfLife: date(2025, time.August, 27)},
+	}
+}
+
+// view returns the cluster as seen by the client at the current time.
+// Clusters are in deployment until the provisioning delay has passed, and
+// become ready afterwards, unless their name starts with "fail-". Upgrades
+// take as long as provisioning.
+func (s *Server) view(c *cluster) client.Cluster {
+	result := c.Cluster
+	result.NodePools = slices.Clone(c.NodePools)
+
+	switch {
+	case s.now().Sub(c.createdAt) < s.provisioningDelay:
+		result.Status = client.ClusterStatus{
+			Ready: client.StatusReady{
+				Status:  false,
+				Message: "Cluster is being provisioned",
+				Reason:  "Provisioning",
+			},
+			Deployment: client.StatusDeployment{Active: true, Failed: false},
+		}
+	case s.now().Sub(c.upgradedAt) < s.provisioningDelay:
+		result.Status = client.ClusterStatus{
+			Ready: client.StatusReady{
+				Status:  false,
+				Message: "Cluster is being upgraded to " + c.Version,
+				Reason:  "Upgrading",
+			},
+			Deployment: client.StatusDeployment{Active: true, Failed: false},
+		}
+	case strings.HasPrefix(c.Name, failingClusterPrefix):
+		result.Status = client.ClusterStatus{
+			Ready: client.StatusReady{
+				Status:  false,
+				Message: "Provisioning failed: insufficient capacity",
+				Reason:  "ProvisioningFailed",
+			},
+			Deployment: client.StatusDeployment{Active: false, Failed: true},
+		}
+	default:
+		result.Status = client.ClusterStatus{
+			Ready:      client.StatusReady{Status: true, Message: "Cluster is ready", Reason: "Ready"},
+			Deployment: client.StatusDeployment{Active: false, Failed: false},
+		}
+	}
+
+	return result
+}
+
+func (s *Server) findCluster(match func(*cluster) bool) (*cluster, bool) {
+	index := slices.IndexFunc(s.clusters, match)
+	if index < 0 {
+		return nil, false
+	}
+
+	return s.clusters[index], true
+}
+
+func (s *Server) clusterByID(w http.ResponseWriter, r *http.Request) (*cluster, bool) {
+	c, ok := s.findCluster(func(c *cluster) bool { return c.ID == r.PathValue("id") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "cluster "+r.PathValue("id")+" does not exist")
+	}
+
+	return c, ok
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
+	clusters := make([]client.Cluster, 0, len(s.clusters))
+	for _, c := range s.clusters {
+		clusters = append(clusters, s.view(c))
+	}
+
+	writePage(w, r, clusters)
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request) {
+	var request client.NewClusterRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeProblem(w, http.StatusBadRequest, "cluster name is required")
+		return
+	}
+
+	if _, exists := s.findCluster(func(c *cluster) bool { return c.Name == request.Name }); exists {
+		writeProblem(w, http.StatusConflict, "cluster "+request.Name+" already exists")
+		return
+	}
+
+	version := request.Version
+	if version == "" {
+		version = defaultClusterVersion
+	}
+
+	if !s.offersVersion(version) {
+		writeProblem(w, http.StatusBadRequest, "version "+version+" is not offered")
+		return
+	}
+
+	var pullSecretID string
+
+	if request.PullSecretRef != nil {
+		pullSecretID = *request.PullSecretRef
+
+		if _, exists := s.findPullSecret(func(p client.PullSecret) bool { return p.ID == pullSecretID }); !exists {
+			writeProblem(w, http.StatusBadRequest, "pull secret "+pullSecretID+" does not exist")
+			return
+		}
+	}
+
+	nodePools := request.NodePools
+	if len(nodePools) == 0 {
+		replicas := defaultNodeReplicas
+		nodePools = client.NodePools{{Name: "default", Preset: "minimal", Replicas: &replicas}}
+	}
+
+	for i := range nodePools {
+		if nodePools[i].ID == "" {
+			nodePools[i].ID = uuid.NewString()
+		}
+	}
+
+	c := &cluster{
+		Cluster: client.Cluster{
+			ID:         uuid.NewString(),
+			Name:       request.Name,
+			Version:    version,
+			ConsoleURL: "https://console-openshift-console.apps." + request.Name + ".sandbox.local",
+			NodePools:  nodePools,
+			Roles:      []string{string(client.ClusterMemberRoleAdmin)},
+			// the pull secret is reported by its ID, as it was referenced
+			PullSecretRef: pullSecretID,
+		},
+		createdAt: s.now(),
+		members: []client.ClusterMember{{
+			Subject: clusterSubject(userSubject(s.users[0])),
+			Roles:   []client.ClusterMemberRole{client.ClusterMemberRoleAdmin},
+		}},
+	}
+
+	s.clusters = append(s.clusters, c)
+
+	writeJSON(w, http.StatusCreated, s.view(c))
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.findCluster(func(c *cluster) bool { return c.Name == r.PathValue("name") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "cluster "+r.PathValue("name")+" does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.view(c))
+}
+
+func (s *Server) getClusterStatus(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.view(c))
+}
+
+func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	var request client.UpdateClusterRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Version != "" && request.Version != c.Version {
+		if !s.offersVersion(request.Version) {
+			writeProblem(w, http.StatusBadRequest, "version "+request.Version+" is not offered")
+			return
+		}
+
+		c.Version = request.Version
+		c.upgradedAt = s.now()
+	}
+
+	writeJSON(w, http.StatusOK, s.view(c))
+}
+
+func (s *Server) listClusterVersions(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.versions)
+}
+
+// offersVersion reports whether clusters can be created with or upgraded to
+// the version.
+func (s *Server) offersVersion(version string) bool {
+	return slices.ContainsFunc(s.versions, func(v client.ClusterVersion) bool {
+		return v.Version == version && v.Supported
+	})
+}
+
+func (s *Server) deleteCluster(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.clusterByID(w, r); !ok {
+		return
+	}
+
+	s.clusters = slices.DeleteFunc(s.clusters, func(c *cluster) bool { return c.ID == r.PathValue("id") })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listClusterMembers(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.members)
+}
+
+func (s *Server) addClusterMembers(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload client.AddClusterMembersPayload
+	if !readJSON(w, r, &payload) {
+		return
+	}
+
+	for _, request := range payload.Values {
+		subject, found := s.resolveSubject(request.Subject.Type, request.Subject.ID)
+		if !found {
+			writeProblem(w, http.StatusBadRequest, request.Subject.Type+" "+request.Subject.ID+" does not exist")
+			return
+		}
+
+		if slices.ContainsFunc(c.members, func(m client.ClusterMember) bool { return m.Subject.ID == subject.ID }) {
+			writeProblem(w, http.StatusConflict, subject.Type+" "+subject.Name+" already has access")
+			return
+		}
+
+		c.members = append(c.members, client.ClusterMember{
+			Subject: clusterSubject(subject),
+			Roles:   request.Roles,
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeClusterMember(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	before := len(c.members)
+	c.members = slices.DeleteFunc(c.members, func(m client.ClusterMember) bool {
+		return memberID(m.Subject.Type, m.Subject.ID.String()) == r.PathValue("memberId")
+	})
+
+	if len(c.members) == before {
+		writeProblem(w, http.StatusNotFound, "member "+r.PathValue("memberId")+" does not exist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listNodePools(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.NodePools)
+}
+
+func (s *Server) addNodePool(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	var pool client.NodePool
+	if !readJSON(w, r, &pool) {
+		return
+	}
+
+	if pool.Name == "" {
+		writeProblem(w, http.StatusBadRequest, "node pool name is required")
+		return
+	}
+
+	if slices.ContainsFunc(c.NodePools, func(p client.NodePool) bool { return p.Name == pool.Name }) {
+		writeProblem(w, http.StatusConflict, "node pool "+pool.Name+" already exists")
+		return
+	}
+
+	pool.ID = uuid.NewString()
+	c.NodePools = append(c.NodePools, pool)
+
+	writeJSON(w, http.StatusCreated, pool)
+}
+
+func (s *Server) updateNodePool(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	index := slices.IndexFunc(c.NodePools, func(p client.NodePool) bool { return p.ID == r.PathValue("poolId") })
+	if index < 0 {
+		writeProblem(w, http.StatusNotFound, "node pool "+r.PathValue("poolId")+" does not exist")
+		return
+	}
+
+	var pool client.NodePool
+	if !readJSON(w, r, &pool) {
+		return
+	}
+
+	// the name and ID of a node pool cannot be changed
+	pool.ID = c.NodePools[index].ID
+	pool.Name = c.NodePools[index].Name
+	c.NodePools[index] = pool
+
+	writeJSON(w, http.StatusOK, pool)
+}
+
+func (s *Server) deleteNodePool(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.clusterByID(w, r)
+	if !ok {
+		return
+	}
+
+	index := slices.IndexFunc(c.NodePools, func(p client.NodePool) bool { return p.ID == r.PathValue("poolId") })
+	if index < 0 {
+		writeProblem(w, http.StatusNotFound, "node pool "+r.PathValue("poolId")+" does not exist")
+		return
+	}
+
+	if len(c.NodePools) == 1 {
+		writeProblem(w, http.StatusConflict, "the last node pool of a cluster cannot be deleted")
+		return
+	}
+
+	c.NodePools = slices.Delete(c.NodePools, index, index+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func clusterSubject(subject client.Subject) client.ClusterMemberSubject {
+	return client.ClusterMemberSubject(subject)
+}
+
+// memberID is the identifier of a member in member removal URLs.
+func memberID(subjectType, subjectID string) string {
+	return subjectType + ":" + subjectID
+}
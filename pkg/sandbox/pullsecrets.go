@@ -0,0 +1,98 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/intility/indev/pkg/client"
+)
+
+func (s *Server) findPullSecret(match func(client.PullSecret) bool) (client.PullSecret, bool) {
+	index := slices.IndexFunc(s.pullSecrets, match)
+	if index < 0 {
+		return client.PullSecret{}, false
+	}
+
+	return s.pullSecrets[index], true
+}
+
+func (s *Server) listPullSecrets(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.pullSecrets)
+}
+
+// createPullSecret stores the registries of the pull secret. The
+// credentials are not kept, as nothing in the sandbox pulls images.
+func (s *Server) createPullSecret(w http.ResponseWriter, r *http.Request) {
+	var request client.NewPullSecretRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeProblem(w, http.StatusBadRequest, "pull secret name is required")
+		return
+	}
+
+	if _, exists := s.findPullSecret(func(p client.PullSecret) bool { return p.Name == request.Name }); exists {
+		writeProblem(w, http.StatusConflict, "pull secret "+request.Name+" already exists")
+		return
+	}
+
+	var config struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+
+	if err := json.Unmarshal(request.DockerConfigJSON, &config); err != nil || len(config.Auths) == 0 {
+		writeProblem(w, http.StatusBadRequest, "dockerConfigJson must have credentials for at least one registry")
+		return
+	}
+
+	registries := make([]string, 0, len(config.Auths))
+	for registry := range config.Auths {
+		registries = append(registries, registry)
+	}
+
+	slices.Sort(registries)
+
+	secret := client.PullSecret{
+		ID:         uuid.NewString(),
+		Name:       request.Name,
+		Registries: registries,
+		CreatedAt:  s.now(),
+	}
+
+	s.pullSecrets = append(s.pullSecrets, secret)
+
+	writeJSON(w, http.StatusCreated, secret)
+}
+
+func (s *Server) getPullSecret(w http.ResponseWriter, r *http.Request) {
+	secret, ok := s.findPullSecret(func(p client.PullSecret) bool { return p.Name == r.PathValue("name") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "pull secret "+r.PathValue("name")+" does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, secret)
+}
+
+// deletePullSecret refuses to delete a pull secret that a cluster uses.
+func (s *Server) deletePullSecret(w http.ResponseWriter, r *http.Request) {
+	secret, ok := s.findPullSecret(func(p client.PullSecret) bool { return p.ID == r.PathValue("id") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "pull secret "+r.PathValue("id")+" does not exist")
+		return
+	}
+
+	if c, used := s.findCluster(func(c *cluster) bool { return c.PullSecretRef == secret.ID }); used {
+		writeProblem(w, http.StatusConflict, "pull secret "+secret.Name+" is used by cluster "+c.Name)
+		return
+	}
+
+	s.pullSecrets = slices.DeleteFunc(s.pullSecrets, func(p client.PullSecret) bool { return p.ID == secret.ID })
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,409 @@
+// Package sandbox implements an in-memory fake of the Intility Developer
+// Platform and Blurite APIs. It serves every endpoint used by the platform
+// client, and can be run with `indev sandbox serve` or embedded in tests
+// using httptest:
+//
+//	server := httptest.NewServer(sandbox.New())
+//	defer server.Close()
+//
+//	platformClient := client.New(
+//		client.WithBaseURIs(server.URL, server.URL),
+//		client.WithTokenProvider(client.StaticToken("sandbox")),
+//	)
+package sandbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/intility/indev/pkg/client"
+)
+
+// TenantID is the tenant of the sandbox user. It is allowed to use the AI
+// features.
+const TenantID = "9b5ff18e-53c0-45a2-8bc2-9c0c8f60b2c6"
+
+// DefaultProvisioningDelay is how long a new cluster stays in deployment
+// before it becomes ready.
+const DefaultProvisioningDelay = 30 * time.Second
+
+const maxRequestBodySize = 1 << 20
+
+var errInvalidPageToken = errors.New("invalid continuation token")
+
+// Server is an http.Handler serving the fake platform. It is safe for
+// concurrent use.
+type Server struct {
+	mux               *http.ServeMux
+	now               func() time.Time
+	provisioningDelay time.Duration
+
+	mu           sync.Mutex
+	me           client.Me
+	users        []client.User
+	teams        []*team
+	clusters     []*cluster
+	versions     []client.ClusterVersion
+	integrations []client.IntegrationInstance
+	models       []client.AIModel
+	deployments  []*deployment
+	pullSecrets  []client.PullSecret
+	idempotency  map[string]recordedResponse
+}
+
+type Option func(*Server)
+
+// WithClock sets the clock used for cluster status transitions.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithProvisioningDelay sets how long a new cluster stays in deployment
+// before it becomes ready.
+func WithProvisioningDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.provisioningDelay = delay
+	}
+}
+
+// New creates a sandbox with a signed-in user, a few colleagues, a team, an
+// SSO provisioner and a set of AI models.
+func New(options ...Option) *Server {
+	server := &Server{
+		mux:               http.NewServeMux(),
+		now:               time.Now,
+		provisioningDelay: DefaultProvisioningDelay,
+		mu:                sync.Mutex{},
+		idempotency:       make(map[string]recordedResponse),
+	}
+
+	for _, opt := range options {
+		opt(server)
+	}
+
+	server.seed()
+	server.routes()
+
+	return server
+}
+
+func (s *Server) routes() {
+	s.handle("GET /api/v1/me", s.getMe)
+	s.handle("GET /api/v1/users", s.listUsers)
+	s.handle("GET /api/v1/users/by-upn/{upn}", s.getUser)
+	s.handle("GET /api/v1/integrations/instances", s.listIntegrations)
+
+	s.handle("GET /api/v1/clusters", s.listClusters)
+	s.handle("GET /api/v1/clusters/versions", s.listClusterVersions)
+	s.handle("POST /api/v1/clusters", s.createCluster)
+	s.handle("GET /api/v1/clusters/{id}/{sub}", byNameOr(s.getCluster, map[string]http.HandlerFunc{
+		"status":    s.getClusterStatus,
+		"members":   s.listClusterMembers,
+		"nodepools": s.listNodePools,
+	}))
+	s.handle("PATCH /api/v1/clusters/{id}", s.updateCluster)
+	s.handle("DELETE /api/v1/clusters/{id}", s.deleteCluster)
+	s.handle("POST /api/v1/clusters/{id}/members", s.addClusterMembers)
+	s.handle("DELETE /api/v1/clusters/{id}/members/{memberId}", s.removeClusterMember)
+	s.handle("POST /api/v1/clusters/{id}/nodepools", s.addNodePool)
+	s.handle("PUT /api/v1/clusters/{id}/nodepools/{poolId}", s.updateNodePool)
+	s.handle("DELETE /api/v1/clusters/{id}/nodepools/{poolId}", s.deleteNodePool)
+
+	s.handle("GET /api/v1/teams", s.listTeams)
+	s.handle("POST /api/v1/teams", s.createTeam)
+	s.handle("GET /api/v1/teams/{id}/{sub}", byNameOr(s.getTeam, map[string]http.HandlerFunc{
+		"members": s.listTeamMembers,
+	}))
+	s.handle("DELETE /api/v1/teams/{id}", s.deleteTeam)
+	s.handle("POST /api/v1/teams/{id}/members", s.addTeamMembers)
+	s.handle("DELETE /api/v1/teams/{id}/members/{memberId}", s.removeTeamMember)
+
+	s.handle("GET /api/v1/pullsecrets", s.listPullSecrets)
+	s.handle("POST /api/v1/pullsecrets", s.createPullSecret)
+	s.handle("GET /api/v1/pullsecrets/by-name/{name}", s.getPullSecret)
+	s.handle("DELETE /api/v1/pullsecrets/{id}", s.deletePullSecret)
+
+	s.handle("GET /api/v1/blurite/models", s.listModels)
+	s.handle("GET /api/v1/blurite/llm-deployments", s.listDeployments)
+	s.handle("POST /api/v1/blurite/llm-deployments", s.createDeployment)
+	s.handle("GET /api/v1/blurite/llm-deployments/{id}/{sub}", byNameOr(s.getDeployment, map[string]http.HandlerFunc{
+		"api-keys": s.listAPIKeys,
+	}))
+	s.handle("DELETE /api/v1/blurite/llm-deployments/{id}", s.deleteDeployment)
+	s.handle("POST /api/v1/blurite/llm-deployments/{id}/api-keys", s.createAPIKey)
+	s.handle("GET /api/v1/blurite/llm-deployments/{id}/api-keys/by-name/{name}", s.getAPIKey)
+	s.handle("DELETE /api/v1/blurite/llm-deployments/{id}/api-keys/{keyId}", s.deleteAPIKey)
+}
+
+// handle registers a handler that runs with the state lock held.
+func (s *Server) handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		handler(w, r)
+	})
+}
+
+// byNameOr serves "<collection>/by-name/{name}" with byName, and
+// "<collection>/{id}/{sub}" with the handler of the subresource. The two
+// overlap, so they cannot be registered as separate patterns.
+func byNameOr(byName http.HandlerFunc, subresources map[string]http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "by-name" {
+			r.SetPathValue("name", r.PathValue("sub"))
+			byName(w, r)
+
+			return
+		}
+
+		handler, ok := subresources[r.PathValue("sub")]
+		if !ok {
+			writeProblem(w, http.StatusNotFound, r.URL.Path+" does not exist")
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if id := r.Header.Get("X-Request-ID"); id != "" {
+		w.Header().Set("X-Request-ID", id)
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeProblem(w, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	// POST requests with an Idempotency-Key are answered with the response
+	// of the first request carrying the same key
+	key := r.Header.Get("Idempotency-Key")
+	if r.Method != http.MethodPost || key == "" {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	recorded, ok := s.idempotency[key]
+	s.mu.Unlock()
+
+	if ok {
+		recorded.write(w)
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK, body: bytes.Buffer{}}
+	s.mux.ServeHTTP(recorder, r)
+
+	s.mu.Lock()
+	s.idempotency[key] = recordedResponse{
+		status: recorder.status,
+		header: w.Header().Clone(),
+		body:   recorder.body.Bytes(),
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) seed() {
+	meID := uuid.NewString()
+
+	s.me = client.Me{
+		ID:                meID,
+		Name:              "Sandbox User",
+		OrganizationRoles: []string{"owner"},
+		OrganizationName:  "Sandbox",
+	}
+
+	s.users = []client.User{
+		{ID: meID, Name: "Sandbox User", UPN: "sandbox@example.com", Roles: []string{"owner"}},
+		{ID: uuid.NewString(), Name: "Ada Lovelace", UPN: "ada@example.com", Roles: []string{"member"}},
+		{ID: uuid.NewString(), Name: "Alan Turing", UPN: "alan@example.com", Roles: []string{"member"}},
+	}
+
+	s.teams = []*team{{
+		Team: client.Team{
+			ID:          uuid.NewString(),
+			Name:        "platform",
+			Description: "Platform engineers",
+			Role:        []string{string(client.MemberRoleOwner)},
+		},
+		members: []client.TeamMember{{
+			Subject: userSubject(s.users[0]),
+			Roles:   []client.MemberRole{client.MemberRoleOwner},
+		}},
+	}}
+
+	s.versions = seedVersions()
+
+	s.integrations = []client.IntegrationInstance{{
+		ID:        uuid.NewString(),
+		Type:      "EntraID",
+		Name:      "Sandbox Entra ID",
+		CreatedAt: s.now(),
+	}}
+
+	s.models = []client.AIModel{
+		{ID: "gpt-4o", DisplayName: "GPT-4o", Description: "General purpose model", ContextLength: 128000},
+		{ID: "gpt-4o-mini", DisplayName: "GPT-4o mini", Description: "Small and fast model", ContextLength: 128000},
+	}
+}
+
+func (s *Server) getMe(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.me)
+}
+
+func (s *Server) listIntegrations(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.integrations)
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	writePage(w, r, s.users)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.findUser(func(u client.User) bool { return strings.EqualFold(u.UPN, r.PathValue("upn")) })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "user "+r.PathValue("upn")+" does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) findUser(match func(client.User) bool) (client.User, bool) {
+	index := slices.IndexFunc(s.users, match)
+	if index < 0 {
+		return client.User{}, false
+	}
+
+	return s.users[index], true
+}
+
+// writePage writes a list, paginated when the client asks for a page size.
+// Continuation tokens are opaque encodings of the offset of the next page.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	if items == nil {
+		items = []T{}
+	}
+
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize <= 0 {
+		writeJSON(w, http.StatusOK, items)
+		return
+	}
+
+	offset, err := decodePageToken(r.URL.Query().Get("continuationToken"))
+	if err != nil || offset > len(items) {
+		writeProblem(w, http.StatusBadRequest, errInvalidPageToken.Error())
+		return
+	}
+
+	end := min(offset+pageSize, len(items))
+	page := struct {
+		Items             []T    `json:"items"`
+		ContinuationToken string `json:"continuationToken,omitempty"`
+	}{
+		Items:             items[offset:end],
+		ContinuationToken: "",
+	}
+
+	if end < len(items) {
+		page.ContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, errInvalidPageToken
+	}
+
+	return offset, nil
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, value any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(value); err != nil {
+		writeProblem(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeProblem(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(client.ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: "",
+	})
+}
+
+type recordedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (r recordedResponse) write(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body)
+}
+
+// responseRecorder captures a response while it is written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+
+	return r.ResponseWriter.Write(p) //nolint:wrapcheck // transparent wrapper
+}
@@ -0,0 +1,325 @@
+package sandbox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/intility/indev/internal/telemetry"
+	"github.com/intility/indev/pkg/authenticator"
+	"github.com/intility/indev/pkg/client"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestClient(t *testing.T, options ...Option) *client.RestClient {
+	t.Helper()
+
+	server := httptest.NewServer(New(options...))
+	t.Cleanup(server.Close)
+
+	tokenSource := authenticator.NewTokenSource(authenticator.NewStaticAcquirer(TenantID, "test"))
+
+	return client.New(
+		client.WithTokenSource(tokenSource),
+		client.WithRetries(0),
+		client.WithBaseURIs(server.URL, server.URL),
+	)
+}
+
+func testContext() context.Context {
+	return telemetry.ContextWithTracer(context.Background(), noop.NewTracerProvider().Tracer("test"))
+}
+
+func TestClusterLifecycle(t *testing.T) {
+	ctx := testContext()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	platformClient := newTestClient(t, WithClock(clock.Now), WithProvisioningDelay(time.Minute))
+
+	created, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "demo"})
+	require.NoError(t, err)
+	assert.True(t, created.Status.Deployment.Active)
+	assert.False(t, created.Status.Ready.Status)
+	assert.Len(t, created.NodePools, 1)
+
+	_, err = platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "demo"})
+	require.ErrorIs(t, err, client.ErrConflict)
+
+	clock.Advance(time.Minute)
+
+	status, err := platformClient.GetClusterStatus(ctx, created.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Status.Ready.Status)
+	assert.False(t, status.Status.Deployment.Active)
+
+	cluster, err := platformClient.GetCluster(ctx, "demo")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, cluster.ID)
+
+	require.NoError(t, platformClient.DeleteCluster(ctx, created.ID))
+
+	clusters, err := platformClient.ListClusters(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, clusters)
+
+	_, err = platformClient.GetCluster(ctx, "demo")
+	require.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestFailingCluster(t *testing.T) {
+	ctx := testContext()
+	platformClient := newTestClient(t, WithProvisioningDelay(0))
+
+	created, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "fail-demo"})
+	require.NoError(t, err)
+	assert.True(t, created.Status.Deployment.Failed)
+	assert.False(t, created.Status.Ready.Status)
+}
+
+func TestClusterUpgrade(t *testing.T) {
+	ctx := testContext()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	platformClient := newTestClient(t, WithClock(clock.Now), WithProvisioningDelay(time.Minute))
+
+	versions, err := platformClient.ListClusterVersions(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, versions)
+
+	_, err = platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "old", Version: "4.15"})
+	require.ErrorIs(t, err, client.ErrBadRequest)
+
+	created, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "demo", Version: "4.16"})
+	require.NoError(t, err)
+	assert.Equal(t, "4.16", created.Version)
+
+	clock.Advance(time.Minute)
+
+	upgraded, err := platformClient.UpdateCluster(ctx, created.ID, client.UpdateClusterRequest{Version: "4.17"})
+	require.NoError(t, err)
+	assert.Equal(t, "4.17", upgraded.Version)
+	assert.True(t, upgraded.Status.Deployment.Active)
+	assert.Equal(t, "Upgrading", upgraded.Status.Ready.Reason)
+
+	clock.Advance(time.Minute)
+
+	status, err := platformClient.GetClusterStatus(ctx, created.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Status.Ready.Status)
+}
+
+func TestNodePools(t *testing.T) {
+	ctx := testContext()
+	platformClient := newTestClient(t)
+	replicas := 3
+
+	cluster, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "demo"})
+	require.NoError(t, err)
+
+	added, err := platformClient.AddNodePool(ctx, cluster.ID, client.NodePool{
+		Name:     "workers",
+		Preset:   "balanced",
+		Replicas: &replicas,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, added.ID)
+
+	_, err = platformClient.AddNodePool(ctx, cluster.ID, client.NodePool{Name: "workers", Preset: "minimal"})
+	require.ErrorIs(t, err, client.ErrConflict)
+
+	replicas = 6
+	added.Replicas = &replicas
+
+	updated, err := platformClient.UpdateNodePool(ctx, cluster.ID, *added)
+	require.NoError(t, err)
+	assert.Equal(t, 6, *updated.Replicas)
+
+	pools, err := platformClient.ListNodePools(ctx, cluster.ID)
+	require.NoError(t, err)
+	require.Len(t, pools, 2)
+	assert.Equal(t, 6, *pools[1].Replicas)
+
+	require.NoError(t, platformClient.DeleteNodePool(ctx, cluster.ID, added.ID))
+
+	err = platformClient.DeleteNodePool(ctx, cluster.ID, pools[0].ID)
+	require.ErrorIs(t, err, client.ErrConflict)
+
+	upgraded, err := platformClient.UpdateCluster(ctx, cluster.ID, client.UpdateClusterRequest{Version: "4.18"})
+	require.NoError(t, err)
+	assert.Equal(t, "4.18", upgraded.Version)
+}
+
+func TestPullSecrets(t *testing.T) {
+	ctx := testContext()
+	platformClient := newTestClient(t)
+
+	secret, err := platformClient.CreatePullSecret(ctx, client.NewPullSecretRequest{
+		Name:             "registry",
+		DockerConfigJSON: []byte(`{"auths":{"quay.io":{"auth":"dTpw"},"ghcr.io":{"auth":"dTpw"}}}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghcr.io", "quay.io"}, secret.Registries)
+
+	_, err = platformClient.CreatePullSecret(ctx, client.NewPullSecretRequest{
+		Name:             "empty",
+		DockerConfigJSON: []byte(`{"auths":{}}`),
+	})
+	require.ErrorIs(t, err, client.ErrBadRequest)
+
+	found, err := platformClient.GetPullSecret(ctx, "registry")
+	require.NoError(t, err)
+	assert.Equal(t, secret.ID, found.ID)
+
+	unknown := "unknown"
+	_, err = platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "demo", PullSecretRef: &unknown})
+	require.ErrorIs(t, err, client.ErrBadRequest)
+
+	cluster, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "demo", PullSecretRef: &secret.ID})
+	require.NoError(t, err)
+
+	// a pull secret cannot be deleted while a cluster uses it
+	err = platformClient.DeletePullSecret(ctx, secret.ID)
+	require.ErrorIs(t, err, client.ErrConflict)
+
+	require.NoError(t, platformClient.DeleteCluster(ctx, cluster.ID))
+	require.NoError(t, platformClient.DeletePullSecret(ctx, secret.ID))
+
+	secrets, err := platformClient.ListPullSecrets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+}
+
+func TestMembers(t *testing.T) {
+	ctx := testContext()
+	platformClient := newTestClient(t)
+
+	ada, err := platformClient.GetUser(ctx, "ada@example.com")
+	require.NoError(t, err)
+
+	team, err := platformClient.CreateTeam(ctx, client.NewTeamRequest{Name: "data", Description: "Data science"})
+	require.NoError(t, err)
+
+	err = platformClient.AddTeamMember(ctx, team.ID, []client.AddTeamMemberRequest{{
+		Subject: client.AddMemberSubject{Type: "user", ID: ada.ID},
+		Roles:   []client.MemberRole{client.MemberRoleMember},
+	}})
+	require.NoError(t, err)
+
+	members, err := platformClient.GetTeamMembers(ctx, team.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "Ada Lovelace", members[1].Subject.Name)
+
+	require.NoError(t, platformClient.RemoveTeamMember(ctx, team.ID, "user:"+ada.ID))
+
+	cluster, err := platformClient.CreateCluster(ctx, client.NewClusterRequest{Name: "shared"})
+	require.NoError(t, err)
+
+	err = platformClient.AddClusterMember(ctx, cluster.ID, []client.AddClusterMemberRequest{{
+		Subject: client.AddClusterMemberSubject{Type: "team", ID: team.ID},
+		Roles:   []client.ClusterMemberRole{client.ClusterMemberRoleReader},
+	}})
+	require.NoError(t, err)
+
+	clusterMembers, err := platformClient.GetClusterMembers(ctx, cluster.ID)
+	require.NoError(t, err)
+	require.Len(t, clusterMembers, 2)
+	assert.Equal(t, "data", clusterMembers[1].Subject.Name)
+
+	err = platformClient.RemoveClusterMember(ctx, cluster.ID, "team:"+ada.ID)
+	require.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestPagination(t *testing.T) {
+	ctx := testContext()
+	platformClient := newTestClient(t)
+
+	users, err := client.Collect(platformClient.IterUsers(ctx, client.ListOptions{PageSize: 2}))
+	require.NoError(t, err)
+	assert.Len(t, users, 3)
+}
+
+func TestAIDeployments(t *testing.T) {
+	ctx := testContext()
+	platformClient := newTestClient(t)
+
+	models, err := platformClient.ListAIModels(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, models)
+
+	deployment, err := platformClient.CreateAIDeployment(ctx, client.NewAIDeploymentRequest{
+		Name:  "assistant",
+		Model: models[0].ID,
+	})
+	require.NoError(t, err)
+
+	key, err := platformClient.CreateAIAPIKey(ctx, deployment.ID, client.NewAIAPIKeyRequest{Name: "ci", TTLDays: 7})
+	require.NoError(t, err)
+	assert.NotEmpty(t, key.Key)
+	assert.Contains(t, key.Key, key.Prefix)
+
+	fetched, err := platformClient.GetAIAPIKey(ctx, deployment.ID, "ci")
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, fetched.ID)
+
+	require.NoError(t, platformClient.DeleteAIAPIKey(ctx, deployment.ID, key.ID))
+
+	keys, err := platformClient.ListAIAPIKeys(ctx, deployment.ID)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestServeHTTP(t *testing.T) {
+	t.Run("requires a bearer token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		New().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("replays requests with the same idempotency key", func(t *testing.T) {
+		server := New()
+
+		create := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/teams", strings.NewReader(`{"name":"once"}`))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Idempotency-Key", "key")
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			return recorder
+		}
+
+		first, second := create(), create()
+
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.JSONEq(t, first.Body.String(), second.Body.String())
+		assert.Len(t, server.teams, 2)
+	})
+}
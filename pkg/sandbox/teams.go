@@ -0,0 +1,189 @@
+package sandbox
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/intility/indev/pkg/client"
+)
+
+type team struct {
+	client.Team
+	members []client.TeamMember
+}
+
+func (s *Server) findTeam(match func(*team) bool) (*team, bool) {
+	index := slices.IndexFunc(s.teams, match)
+	if index < 0 {
+		return nil, false
+	}
+
+	return s.teams[index], true
+}
+
+func (s *Server) teamByID(w http.ResponseWriter, r *http.Request) (*team, bool) {
+	t, ok := s.findTeam(func(t *team) bool { return t.ID == r.PathValue("id") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "team "+r.PathValue("id")+" does not exist")
+	}
+
+	return t, ok
+}
+
+func (s *Server) listTeams(w http.ResponseWriter, r *http.Request) {
+	teams := make([]client.Team, 0, len(s.teams))
+	for _, t := range s.teams {
+		teams = append(teams, t.Team)
+	}
+
+	writePage(w, r, teams)
+}
+
+func (s *Server) createTeam(w http.ResponseWriter, r *http.Request) {
+	var request client.NewTeamRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeProblem(w, http.StatusBadRequest, "team name is required")
+		return
+	}
+
+	if _, exists := s.findTeam(func(t *team) bool { return t.Name == request.Name }); exists {
+		writeProblem(w, http.StatusConflict, "team "+request.Name+" already exists")
+		return
+	}
+
+	t := &team{
+		Team: client.Team{
+			ID:          uuid.NewString(),
+			Name:        request.Name,
+			Description: request.Description,
+			Role:        []string{string(client.MemberRoleOwner)},
+		},
+		members: []client.TeamMember{{
+			Subject: userSubject(s.users[0]),
+			Roles:   []client.MemberRole{client.MemberRoleOwner},
+		}},
+	}
+
+	s.teams = append(s.teams, t)
+
+	writeJSON(w, http.StatusCreated, t.Team)
+}
+
+func (s *Server) getTeam(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.findTeam(func(t *team) bool { return t.Name == r.PathValue("name") })
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "team "+r.PathValue("name")+" does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t.Team)
+}
+
+func (s *Server) deleteTeam(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.teamByID(w, r); !ok {
+		return
+	}
+
+	s.teams = slices.DeleteFunc(s.teams, func(t *team) bool { return t.ID == r.PathValue("id") })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listTeamMembers(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.teamByID(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t.members)
+}
+
+func (s *Server) addTeamMembers(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.teamByID(w, r)
+	if !ok {
+		return
+	}
+
+	var requests []client.AddTeamMemberRequest
+	if !readJSON(w, r, &requests) {
+		return
+	}
+
+	for _, request := range requests {
+		subject, found := s.resolveSubject(request.Subject.Type, request.Subject.ID)
+		if !found {
+			writeProblem(w, http.StatusBadRequest, request.Subject.Type+" "+request.Subject.ID+" does not exist")
+			return
+		}
+
+		if slices.ContainsFunc(t.members, func(m client.TeamMember) bool { return m.Subject.ID == subject.ID }) {
+			writeProblem(w, http.StatusConflict, subject.Name+" is already a member")
+			return
+		}
+
+		t.members = append(t.members, client.TeamMember{Subject: subject, Roles: request.Roles})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeTeamMember(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.teamByID(w, r)
+	if !ok {
+		return
+	}
+
+	before := len(t.members)
+	t.members = slices.DeleteFunc(t.members, func(m client.TeamMember) bool {
+		return memberID(m.Subject.Type, m.Subject.ID.String()) == r.PathValue("memberId")
+	})
+
+	if len(t.members) == before {
+		writeProblem(w, http.StatusNotFound, "member "+r.PathValue("memberId")+" does not exist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveSubject looks up the user or team a membership request refers to.
+func (s *Server) resolveSubject(subjectType, id string) (client.Subject, bool) {
+	switch subjectType {
+	case "user":
+		user, ok := s.findUser(func(u client.User) bool { return u.ID == id })
+		if !ok {
+			return client.Subject{}, false
+		}
+
+		return userSubject(user), true
+	case "team":
+		t, ok := s.findTeam(func(t *team) bool { return t.ID == id })
+		if !ok {
+			return client.Subject{}, false
+		}
+
+		return client.Subject{
+			Type:    "team",
+			Name:    t.Name,
+			Details: t.Description,
+			ID:      uuid.MustParse(t.ID),
+		}, true
+	default:
+		return client.Subject{}, false
+	}
+}
+
+func userSubject(user client.User) client.Subject {
+	return client.Subject{
+		Type:    "user",
+		Name:    user.Name,
+		Details: user.UPN,
+		ID:      uuid.MustParse(user.ID),
+	}
+}
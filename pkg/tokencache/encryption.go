@@ -0,0 +1,131 @@
+package tokencache
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/adrg/xdg"
+
+	"github.com/intility/indev/pkg/config"
+	"github.com/intility/indev/pkg/credentialstore"
+)
+
+var (
+	ErrUnknownEncryption = errors.New("unknown token cache encryption")
+	ErrNoKeyFile         = errors.New("token cache encryption 'keyfile' requires a key file")
+	ErrAlreadyMigrated   = errors.New("the encrypted token cache already holds tokens")
+	ErrHelperEncryption  = errors.New("a token cache kept by a credential helper cannot be encrypted as well")
+)
+
+// EncryptedPath returns the path of the encrypted token cache.
+func EncryptedPath() string {
+	return filepath.Join(xdg.DataHome, "indev", "msal.cache.enc")
+}
+
+// WithEncryption stores the cache encrypted at EncryptedPath, with keys
+// from the given function.
+func WithEncryption(key credentialstore.KeyFunc) Option {
+	return func(tc *TokenCache) {
+		tc.store = credentialstore.NewEncryptedCredentialStore(
+			credentialstore.NewFilesystemCredentialStore(EncryptedPath()),
+			key,
+		)
+	}
+}
+
+// Open returns the token cache that the settings select. The passphrase is
+// only asked for once, when the encrypted cache is first read or written.
+func Open(settings config.TokenCache, passphrase func() ([]byte, error), options ...Option) (*TokenCache, error) {
+	if settings.Helper != "" {
+		// helpers keep the cache in a secret store of their own
+		if settings.Encryption != config.EncryptionNone {
+			return nil, ErrHelperEncryption
+		}
+
+		store := credentialstore.NewHelperCredentialStore(settings.Helper,
+			credentialstore.WithHelperTimeout(settings.HelperTimeout))
+
+		return New(append(options, WithCredentialStore(store))...), nil
+	}
+
+	switch settings.Encryption {
+	case config.EncryptionNone:
+	case config.EncryptionPassphrase:
+		options = append(options, WithEncryption(credentialstore.PassphraseKey(sync.OnceValues(passphrase))))
+	case config.EncryptionKeyFile:
+		if settings.KeyFile == "" {
+			return nil, ErrNoKeyFile
+		}
+
+		options = append(options, WithEncryption(credentialstore.KeyFileKey(settings.KeyFile)))
+	default:
+		return nil, fmt.Errorf("%w %q, use %q or %q", ErrUnknownEncryption,
+			settings.Encryption, config.EncryptionPassphrase, config.EncryptionKeyFile)
+	}
+
+	return New(options...), nil
+}
+
+// Migrate moves the tokens of the source cache into this cache, and wipes
+// the source. It reports whether there were tokens to move. This cache is
+// written even when there are none, so that its key is set up right away.
+// Only the empty partition is moved, as it holds every account.
+func (c *TokenCache) Migrate(source *TokenCache) (bool, error) {
+	unlockSource, err := source.lock(true)
+	if err != nil {
+		return false, err
+	}
+
+	defer unlockSource()
+
+	unlock, err := c.lock(true)
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	data, err := source.store.Get("")
+	if err != nil {
+		return false, fmt.Errorf("could not read cache file: %w", err)
+	}
+
+	existing, err := c.store.Get("")
+	if err != nil {
+		return false, fmt.Errorf("could not read cache file: %w", err)
+	}
+
+	if len(existing) != 0 {
+		if len(data) != 0 {
+			return false, ErrAlreadyMigrated
+		}
+
+		return false, nil
+	}
+
+	if err = c.store.Set(data, ""); err != nil {
+		return false, fmt.Errorf("could not write cache file: %w", err)
+	}
+
+	if len(data) == 0 {
+		return false, nil
+	}
+
+	if err = source.store.Clear(); err != nil {
+		return true, fmt.Errorf("could not clear cache: %w", err)
+	}
+
+	return true, nil
+}
+
+// Wipe removes both the plaintext and the encrypted token cache, so that
+// signing out leaves no tokens behind whichever cache is configured. No key
+// is needed, as the files are overwritten without being read.
+func Wipe() error {
+	plaintext := New()
+	encrypted := New(WithEncryption(nil))
+
+	return errors.Join(plaintext.Clear(), encrypted.Clear())
+}
@@ -0,0 +1,59 @@
+package tokencache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/indev/pkg/config"
+	"github.com/intility/indev/pkg/credentialstore"
+	"github.com/intility/indev/pkg/tokencache"
+)
+
+func TestOpen_InvalidSettings(t *testing.T) {
+	_, err := tokencache.Open(config.TokenCache{Encryption: "rot13"}, nil)
+	require.ErrorIs(t, err, tokencache.ErrUnknownEncryption)
+
+	_, err = tokencache.Open(config.TokenCache{Encryption: config.EncryptionKeyFile}, nil)
+	require.ErrorIs(t, err, tokencache.ErrNoKeyFile)
+}
+
+func TestTokenCache_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	plaintextPath := filepath.Join(dir, "msal.cache")
+
+	plaintextStore := credentialstore.NewFilesystemCredentialStore(plaintextPath)
+	require.NoError(t, plaintextStore.Set([]byte(`{"RefreshToken":{}}`), ""))
+
+	encryptedStore := credentialstore.NewEncryptedCredentialStore(
+		credentialstore.NewFilesystemCredentialStore(filepath.Join(dir, "msal.cache.enc")),
+		credentialstore.PassphraseKey(func() ([]byte, error) { return []byte("secret"), nil }),
+	)
+
+	plaintext := tokencache.New(tokencache.WithCredentialStore(plaintextStore))
+	encrypted := tokencache.New(tokencache.WithCredentialStore(encryptedStore))
+
+	migrated, err := encrypted.Migrate(plaintext)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+
+	assert.NoFileExists(t, plaintextPath)
+
+	data, err := encryptedStore.Get("")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"RefreshToken":{}}`, string(data))
+
+	// migrating again has nothing to move
+	migrated, err = encrypted.Migrate(plaintext)
+	require.NoError(t, err)
+	assert.False(t, migrated)
+
+	// tokens are not overwritten by a plaintext cache written since
+	require.NoError(t, os.WriteFile(plaintextPath, []byte(`{}`), 0o600))
+
+	_, err = encrypted.Migrate(plaintext)
+	require.ErrorIs(t, err, tokencache.ErrAlreadyMigrated)
+}
@@ -0,0 +1,79 @@
+package tokencache
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// sections is an MSAL cache: entries by key, in sections such as
+// "AccessToken", "RefreshToken" and "Account".
+type sections map[string]map[string]json.RawMessage
+
+// merge applies the changes from read to exported onto stored, so that the
+// entries that other processes wrote to stored in the meantime are kept.
+// When any of them cannot be decoded, exported is returned as it is.
+func merge(read, stored, exported []byte) []byte {
+	if bytes.Equal(read, stored) {
+		return exported
+	}
+
+	before, errBefore := decodeSections(read)
+	after, errAfter := decodeSections(exported)
+	result, errResult := decodeSections(stored)
+
+	if errBefore != nil || errAfter != nil || errResult != nil {
+		return exported
+	}
+
+	for name, entries := range before {
+		for key := range entries {
+			if _, ok := after[name][key]; !ok {
+				delete(result[name], key)
+			}
+		}
+	}
+
+	for name, entries := range after {
+		for key, entry := range entries {
+			if previous, ok := before[name][key]; ok && sameJSON(previous, entry) {
+				continue
+			}
+
+			if result[name] == nil {
+				result[name] = map[string]json.RawMessage{}
+			}
+
+			result[name][key] = entry
+		}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return exported
+	}
+
+	return data
+}
+
+func decodeSections(data []byte) (sections, error) {
+	decoded := sections{}
+	if len(data) == 0 {
+		return decoded, nil
+	}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err //nolint:wrapcheck // callers fall back to the exported cache
+	}
+
+	return decoded, nil
+}
+
+func sameJSON(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return false
+	}
+
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
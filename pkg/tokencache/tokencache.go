@@ -2,8 +2,10 @@ package tokencache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
 	"github.com/adrg/xdg"
@@ -11,17 +13,37 @@ import (
 	"github.com/intility/indev/pkg/credentialstore"
 )
 
+// ErrCorruptCache is returned when the token cache could not be decoded. The
+// cache is set aside when that happens, so that signing in again starts over
+// with an empty cache.
+var ErrCorruptCache = errors.New("the token cache was corrupt and has been reset, sign in again with 'indev login'")
+
+// TokenCache stores the MSAL cache in a credential store. The whole cache is
+// kept in the empty partition, whichever partition key MSAL passes: the cache
+// of a public client holds every account, and is read with the home account
+// ID as partition key when tokens are refreshed, so storing it in several
+// partitions would lose accounts and refresh tokens.
 type TokenCache struct {
 	store credentialstore.CredentialStore
+
+	mu sync.Mutex
+	// read is the data that MSAL was last given, which Export merges the
+	// changes of other processes into
+	read []byte
 }
 
 type Option func(*TokenCache)
 
-func New(options ...Option) *TokenCache {
-	cacheFilePath := filepath.Join(xdg.DataHome, "indev", "msal.cache")
+// DefaultPath returns the path of the plaintext token cache.
+func DefaultPath() string {
+	return filepath.Join(xdg.DataHome, "indev", "msal.cache")
+}
 
+func New(options ...Option) *TokenCache {
 	tc := &TokenCache{
-		store: credentialstore.NewFilesystemCredentialStore(cacheFilePath),
+		store: credentialstore.NewFilesystemCredentialStore(DefaultPath()),
+		mu:    sync.Mutex{},
+		read:  nil,
 	}
 
 	for _, option := range options {
@@ -38,38 +60,126 @@ func WithCredentialStore(store credentialstore.CredentialStore) Option {
 }
 
 func (c *TokenCache) Replace(ctx context.Context, cache cache.Unmarshaler, hints cache.ReplaceHints) error {
-	cacheData, err := c.store.Get(hints.PartitionKey)
+	unlock, err := c.lock(false)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	cacheData, err := c.store.Get("")
 	if err != nil {
 		return fmt.Errorf("could not read cache file: %w", err)
 	}
 
 	err = cache.Unmarshal(cacheData)
 	if err != nil {
-		return fmt.Errorf("could not unmarshal cache: %w", err)
+		c.setRead([]byte{})
+
+		return c.reset("", err)
 	}
 
+	c.setRead(cacheData)
+
 	return nil
 }
 
+// Export writes the cache of MSAL. Other processes may have written the
+// store since Replace read it, so the store is read again under the write
+// lock, and only the entries that MSAL added, changed or removed since then
+// are applied to it.
 func (c *TokenCache) Export(ctx context.Context, cache cache.Marshaler, hints cache.ExportHints) error {
 	cacheData, err := cache.Marshal()
 	if err != nil {
 		return fmt.Errorf("could not marshal cache: %w", err)
 	}
 
-	err = c.store.Set(cacheData, hints.PartitionKey)
+	unlock, err := c.lock(true)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	stored, err := c.store.Get("")
+	if err != nil {
+		return fmt.Errorf("could not read cache file: %w", err)
+	}
+
+	c.mu.Lock()
+	read := c.read
+	c.mu.Unlock()
+
+	if read != nil {
+		cacheData = merge(read, stored, cacheData)
+	}
+
+	err = c.store.Set(cacheData, "")
 	if err != nil {
 		return fmt.Errorf("could not write cache file: %w", err)
 	}
 
+	c.setRead(cacheData)
+
 	return nil
 }
 
 func (c *TokenCache) Clear() error {
-	err := c.store.Clear()
+	unlock, err := c.lock(true)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	err = c.store.Clear()
 	if err != nil {
 		return fmt.Errorf("could not clear cache: %w", err)
 	}
 
 	return nil
 }
+
+func (c *TokenCache) setRead(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.read = data
+}
+
+// lock locks the store against other processes if it supports it. The
+// returned function releases the lock.
+func (c *TokenCache) lock(exclusive bool) (func(), error) {
+	locker, ok := c.store.(credentialstore.Locker)
+	if !ok {
+		return func() {}, nil
+	}
+
+	unlock, err := locker.Lock(exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("could not lock cache: %w", err)
+	}
+
+	return func() {
+		_ = unlock()
+	}, nil
+}
+
+// reset sets aside a partition that could not be decoded, so that the next
+// sign-in starts over instead of failing on the same data again.
+func (c *TokenCache) reset(key string, cause error) error {
+	if quarantiner, ok := c.store.(credentialstore.Quarantiner); ok {
+		path, err := quarantiner.Quarantine(key)
+		if err == nil {
+			return fmt.Errorf("could not unmarshal cache, moved it to %s: %w: %w", path, cause, ErrCorruptCache)
+		}
+	}
+
+	// stores that cannot keep the data aside lose it, along with credentials
+	// that could not be read anyway
+	if err := c.store.Set([]byte{}, key); err != nil {
+		return fmt.Errorf("could not unmarshal cache: %w", errors.Join(cause, err))
+	}
+
+	return fmt.Errorf("could not unmarshal cache: %w: %w", cause, ErrCorruptCache)
+}
@@ -1,15 +1,19 @@
 package tokencache_test
 
 import (
+	"context"
 	"errors"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
 	"github.com/intility/indev/mocks"
+	"github.com/intility/indev/pkg/credentialstore"
 	"github.com/intility/indev/pkg/tokencache"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -57,7 +61,7 @@ func TestTokenCache_Replace(t *testing.T) {
 			name:          "successful replacement",
 			cacheFilePath: "cache_file",
 			prepareStore: func(store *mocks.CredentialStore) {
-				store.On("Get", "test").Return([]byte{}, nil).Once() // Expecting the GetCluster function to be called once
+				store.On("Get", "").Return([]byte{}, nil).Once() // Expecting the GetCluster function to be called once
 			},
 			unmarshal: func(u *mocks.Unmarshaler) {
 				u.On("Unmarshal", mock.Anything).Return(nil).Once() // Expecting the Unmarshal function to be called once
@@ -67,7 +71,7 @@ func TestTokenCache_Replace(t *testing.T) {
 		{
 			name: "error reading credential store",
 			prepareStore: func(store *mocks.CredentialStore) {
-				store.On("Get", "test").Return(nil, errors.New("unknown err")).Once() // Expecting the GetCluster function to be called once
+				store.On("Get", "").Return(nil, errors.New("unknown err")).Once() // Expecting the GetCluster function to be called once
 			},
 			wantErr: "could not read cache file: unknown err",
 		},
@@ -75,12 +79,13 @@ func TestTokenCache_Replace(t *testing.T) {
 			name:          "error unmarshalling cache",
 			cacheFilePath: "cache_file",
 			prepareStore: func(store *mocks.CredentialStore) {
-				store.On("Get", "test").Return([]byte{}, nil).Once() // Expecting the GetCluster function to be called once
+				store.On("Get", "").Return([]byte{}, nil).Once() // Expecting the GetCluster function to be called once
+				store.On("Set", []byte{}, "").Return(nil).Once() // Expecting the corrupt cache to be reset
 			},
 			unmarshal: func(u *mocks.Unmarshaler) {
 				u.On("Unmarshal", mock.Anything).Return(errors.New("unknown err")).Once() // Expecting the Unmarshal function to be called once
 			},
-			wantErr: "could not unmarshal cache: unknown err",
+			wantErr: tokencache.ErrCorruptCache.Error(),
 		},
 	}
 
@@ -112,6 +117,7 @@ func TestTokenCache_Replace(t *testing.T) {
 			}
 
 			unmarshaler.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
 		})
 	}
 }
@@ -126,7 +132,8 @@ func TestTokenCache_Export(t *testing.T) {
 		{
 			name: "successful export",
 			prepareStore: func(store *mocks.CredentialStore) {
-				store.On("Set", mock.Anything, "test").Return(nil).Once() // Expecting the Set function to be called once
+				store.On("Get", "").Return([]byte{}, nil).Once()      // Expecting the stored cache to be read again
+				store.On("Set", mock.Anything, "").Return(nil).Once() // Expecting the Set function to be called once
 			},
 			marshal: func(m *mocks.Marshaler) {
 				m.On("Marshal").Return([]byte("foo"), nil).Once() // Expecting the Marshal function to be called once
@@ -136,7 +143,8 @@ func TestTokenCache_Export(t *testing.T) {
 		{
 			name: "error writing credential store",
 			prepareStore: func(store *mocks.CredentialStore) {
-				store.On("Set", mock.Anything, "test").Return(errors.New("unknown err")).Once() // Expecting the Set function to be called once
+				store.On("Get", "").Return([]byte{}, nil).Once()                            // Expecting the stored cache to be read again
+				store.On("Set", mock.Anything, "").Return(errors.New("unknown err")).Once() // Expecting the Set function to be called once
 			},
 			marshal: func(m *mocks.Marshaler) {
 				m.On("Marshal").Return([]byte("foo"), nil).Once() // Expecting the Marshal function to be called once
@@ -182,3 +190,48 @@ func TestTokenCache_Export(t *testing.T) {
 		})
 	}
 }
+
+type msalCache struct {
+	data []byte
+}
+
+func (c *msalCache) Unmarshal(data []byte) error {
+	c.data = data
+
+	return nil
+}
+
+func (c *msalCache) Marshal() ([]byte, error) {
+	return c.data, nil
+}
+
+func TestTokenCache_ExportKeepsConcurrentChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "msal.cache")
+	initial := `{"Account":{"ada":{"name":"ada"},"alan":{"name":"alan"}},"RefreshToken":{"ada":{"secret":"1"}}}`
+
+	require.NoError(t, credentialstore.NewFilesystemCredentialStore(path).Set([]byte(initial), ""))
+
+	first := tokencache.New(tokencache.WithCredentialStore(credentialstore.NewFilesystemCredentialStore(path)))
+	second := tokencache.New(tokencache.WithCredentialStore(credentialstore.NewFilesystemCredentialStore(path)))
+	firstMSAL, secondMSAL := &msalCache{}, &msalCache{}
+
+	require.NoError(t, first.Replace(context.Background(), firstMSAL, cache.ReplaceHints{}))
+	require.NoError(t, second.Replace(context.Background(), secondMSAL, cache.ReplaceHints{}))
+
+	// the first process refreshes the token of ada, while the second one
+	// signs in as grace and signs out of alan
+	firstMSAL.data = []byte(`{"Account":{"ada":{"name":"ada"},"alan":{"name":"alan"}},` +
+		`"RefreshToken":{"ada":{"secret":"2"}}}`)
+	secondMSAL.data = []byte(`{"Account":{"ada":{"name":"ada"},"grace":{"name":"grace"}},` +
+		`"RefreshToken":{"ada":{"secret":"1"},"grace":{"secret":"3"}}}`)
+
+	require.NoError(t, second.Export(context.Background(), secondMSAL, cache.ExportHints{}))
+	require.NoError(t, first.Export(context.Background(), firstMSAL, cache.ExportHints{}))
+
+	stored, err := credentialstore.NewFilesystemCredentialStore(path).Get("")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Account":{"ada":{"name":"ada"},"grace":{"name":"grace"}},
+		"RefreshToken":{"ada":{"secret":"2"},"grace":{"secret":"3"}}
+	}`, string(stored))
+}
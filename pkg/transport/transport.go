@@ -0,0 +1,162 @@
+// Package transport configures the HTTP transport shared by the platform
+// client and the authenticator, so that both reach the network through the
+// same proxy and trust the same certificates.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	ErrInvalidProxy         = errors.New("invalid proxy URL")
+	ErrNoCertificates       = errors.New("no PEM certificates found")
+	ErrClientKeyWithoutCert = errors.New("a client key requires a client certificate")
+)
+
+// Config describes how requests reach the network. The zero value leaves the
+// transport unchanged: the proxy is taken from the HTTPS_PROXY, HTTP_PROXY
+// and NO_PROXY environment variables, and only system certificates are
+// trusted.
+type Config struct {
+	// Proxy is the URL of the proxy used for all requests.
+	Proxy string
+	// NoProxy is a comma-separated list of hosts, domains and CIDR ranges
+	// that are reached without the proxy.
+	NoProxy string
+	// CABundle is the path of a PEM file with certificates that are trusted
+	// in addition to the system certificates.
+	CABundle string
+	// ClientCert is the path of a PEM client certificate presented to
+	// servers that ask for one.
+	ClientCert string
+	// ClientKey is the path of the PEM private key of the client
+	// certificate. It defaults to ClientCert, for files holding both.
+	ClientKey string
+}
+
+// New returns a copy of http.DefaultTransport configured with config.
+func New(config Config) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // always a *http.Transport
+
+	if err := Configure(transport, config); err != nil {
+		return nil, err
+	}
+
+	return transport, nil
+}
+
+// Configure applies config to an existing transport. It must be called before
+// the transport sends its first request.
+func Configure(transport *http.Transport, config Config) error {
+	if config.Proxy != "" || config.NoProxy != "" {
+		proxy, err := proxyFunc(config)
+		if err != nil {
+			return err
+		}
+
+		transport.Proxy = proxy
+	}
+
+	if config.CABundle == "" && config.ClientCert == "" && config.ClientKey == "" {
+		return nil
+	}
+
+	tlsConfig, err := tlsConfig(transport.TLSClientConfig, config)
+	if err != nil {
+		return err
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return nil
+}
+
+// proxyFunc returns a proxy selector that takes the settings missing from
+// config from the environment.
+func proxyFunc(config Config) (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidProxy, config.Proxy)
+		}
+
+		proxyConfig.HTTPProxy = config.Proxy
+		proxyConfig.HTTPSProxy = config.Proxy
+	}
+
+	if config.NoProxy != "" {
+		proxyConfig.NoProxy = config.NoProxy
+	}
+
+	selectProxy := proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(req.URL)
+	}, nil
+}
+
+func tlsConfig(base *tls.Config, config Config) (*tls.Config, error) {
+	result := &tls.Config{MinVersion: tls.VersionTLS12} //nolint:exhaustruct // defaults are secure
+	if base != nil {
+		result = base.Clone()
+	}
+
+	if config.CABundle != "" {
+		pool, err := certPool(config.CABundle)
+		if err != nil {
+			return nil, err
+		}
+
+		result.RootCAs = pool
+	}
+
+	if config.ClientKey != "" && config.ClientCert == "" {
+		return nil, ErrClientKeyWithoutCert
+	}
+
+	if config.ClientCert != "" {
+		keyFile := config.ClientKey
+		if keyFile == "" {
+			keyFile = config.ClientCert
+		}
+
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	return result, nil
+}
+
+// certPool returns the system certificates together with the certificates in
+// the bundle.
+func certPool(bundle string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w in %s", ErrNoCertificates, bundle)
+	}
+
+	return pool, nil
+}
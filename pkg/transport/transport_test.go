@@ -0,0 +1,144 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blocks ...*pem.Block) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bundle.pem")
+
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, pem.EncodeToMemory(block)...)
+	}
+
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func certificateBlock(server *httptest.Server) *pem.Block {
+	return &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+}
+
+func get(t *testing.T, transport *http.Transport, url string) error {
+	t.Helper()
+
+	resp, err := (&http.Client{Transport: transport}).Get(url) //nolint:noctx // test request
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	return err //nolint:wrapcheck // test helper
+}
+
+func TestCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	t.Run("trusts the certificates in the bundle", func(t *testing.T) {
+		transport, err := New(Config{CABundle: writePEM(t, certificateBlock(server))})
+		require.NoError(t, err)
+
+		require.NoError(t, get(t, transport, server.URL))
+	})
+
+	t.Run("does not trust unknown certificates", func(t *testing.T) {
+		transport, err := New(Config{})
+		require.NoError(t, err)
+
+		var unknownAuthority x509.UnknownAuthorityError
+		require.ErrorAs(t, get(t, transport, server.URL), &unknownAuthority)
+	})
+
+	t.Run("rejects bundles without certificates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+
+		_, err := New(Config{CABundle: path})
+		require.ErrorIs(t, err, ErrNoCertificates)
+	})
+
+	t.Run("reports missing bundles", func(t *testing.T) {
+		_, err := New(Config{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestClientCertificate(t *testing.T) {
+	// reuse the certificate of a test server as the client certificate
+	identity := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	identity.Close()
+
+	key, err := x509.MarshalPKCS8PrivateKey(identity.TLS.Certificates[0].PrivateKey)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	t.Run("presents the certificate", func(t *testing.T) {
+		transport, err := New(Config{
+			CABundle:   writePEM(t, certificateBlock(server)),
+			ClientCert: writePEM(t, certificateBlock(identity), &pem.Block{Type: "PRIVATE KEY", Bytes: key}),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, get(t, transport, server.URL))
+	})
+
+	t.Run("requires a certificate for a key", func(t *testing.T) {
+		_, err := New(Config{ClientKey: "key.pem"})
+		require.ErrorIs(t, err, ErrClientKeyWithoutCert)
+	})
+}
+
+func TestProxy(t *testing.T) {
+	transport, err := New(Config{Proxy: "http://proxy.example.com:3128", NoProxy: "internal.example.com"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "external host", url: "https://login.microsoftonline.com/token", want: "http://proxy.example.com:3128"},
+		{name: "excluded host", url: "https://api.internal.example.com/clusters", want: ""},
+		{name: "loopback", url: "http://localhost:8080/api/v1/me", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			proxyURL, err := transport.Proxy(req)
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				assert.Nil(t, proxyURL)
+			} else {
+				assert.Equal(t, tt.want, proxyURL.String())
+			}
+		})
+	}
+
+	t.Run("rejects invalid URLs", func(t *testing.T) {
+		_, err := New(Config{Proxy: "://proxy"})
+		require.ErrorIs(t, err, ErrInvalidProxy)
+	})
+}